@@ -0,0 +1,354 @@
+// Command graphql-schema-generator writes a GraphQL schema for the structs in
+// Go packages. The packages are read from source, so it works from a
+// //go:generate directive or in CI without writing any Go code:
+//
+//	//go:generate go run github.com/warpspeed-cloud/graphql-schema-generator/cmd/graphql-schema-generator -root User -o schema.graphql ./models
+//
+// Every exported struct in the packages is added to the schema unless root
+// types are given, in which case only they and the types they reference are.
+//
+// Settings can also be read from a YAML or JSON config file given with -config,
+// flags and package arguments win over the config file.
+//
+// The schema is checked against the GraphQL specification and nothing is written
+// when it is invalid, each error points back to the Go type or field it comes from.
+//
+// With -introspection the schema is also written as the JSON result of the
+// introspection query, for tools that don't read SDL, and with -jsonschema the
+// Go types are written as a JSON Schema document, i.e. to validate REST payloads,
+// and with -typescript as TypeScript declarations for frontends.
+//
+// With -check nothing is written, instead the command fails with a diff when the
+// output files aren't what would be generated, so CI catches a stale schema.
+//
+// With -compare nothing is written either, instead the changes from a previous
+// version of the schema are reported as breaking, dangerous or safe, with -report
+// json giving a machine readable report. The command fails on breaking changes.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	schemadiff "github.com/warpspeed-cloud/graphql-schema-generator/internal/schema-diff"
+	sourceloader "github.com/warpspeed-cloud/graphql-schema-generator/internal/source-loader"
+	textdiff "github.com/warpspeed-cloud/graphql-schema-generator/internal/text-diff"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schemagen"
+)
+
+const usage = `Usage: graphql-schema-generator [flags] [packages]
+
+Writes a GraphQL schema for the structs declared in the Go packages,
+which default to the package in the current directory.
+
+Flags:
+`
+
+// rootsFlag collects root type names given either as repeated flags or comma separated.
+type rootsFlag []string
+
+func (r *rootsFlag) String() string {
+	return strings.Join(*r, ",")
+}
+
+func (r *rootsFlag) Set(value string) error {
+	for _, root := range strings.Split(value, ",") {
+		if root = strings.TrimSpace(root); root != "" {
+			*r = append(*r, root)
+		}
+	}
+
+	return nil
+}
+
+// outputFile is a file the schema is written to besides the SDL output.
+type outputFile struct {
+	path    string
+	content string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run is the whole command, it returns the exit code.
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	var roots rootsFlag
+
+	flags := flag.NewFlagSet("graphql-schema-generator", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+
+	flags.Var(&roots, "root", "a struct to add to the schema along with the types it references, may be repeated or comma separated (default: every exported struct)")
+	output := flags.String("o", "-", "the file to write the schema to, - writes to stdout")
+	introspection := flags.String("introspection", "", "a file to also write the schema to as the JSON result of the introspection query")
+	jsonSchema := flags.String("jsonschema", "", "a file to also write the Go types to as a JSON Schema document")
+	typeScript := flags.String("typescript", "", "a file to also write the Go types to as TypeScript declarations")
+	order := flags.String("order", "", "the order types are output in, discovery, alphabetical or dependency (default: discovery)")
+	docComments := flags.Bool("doc-comments", true, "use Go doc comments as descriptions when a field has no description tag")
+	configPath := flags.String("config", "", "a YAML or JSON config file")
+	check := flags.Bool("check", false, "don't write the schema, fail with a diff if the output file is out of date")
+	compare := flags.String("compare", "", "don't write the schema, report the changes from the schema in this file and fail on breaking changes")
+	reportFormat := flags.String("report", "text", "the format of the -compare report, text or json")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+
+		return 2
+	}
+
+	if *reportFormat != "text" && *reportFormat != "json" {
+		fmt.Fprintf(stderr, "graphql-schema-generator: -report must be text or json, not '%s'\n", *reportFormat)
+
+		return 2
+	}
+
+	if !validOrder(schema.TypeOrder(*order)) {
+		fmt.Fprintf(stderr, "graphql-schema-generator: -order must be discovery, alphabetical or dependency, not '%s'\n", *order)
+
+		return 2
+	}
+
+	cfg := schemagen.DefaultConfig()
+
+	if *configPath != "" {
+		var err error
+
+		if cfg, err = schemagen.LoadConfig(*configPath); err != nil {
+			fmt.Fprintf(stderr, "graphql-schema-generator: %s\n", err)
+
+			return 1
+		}
+	}
+
+	// Flags that were given override the config file.
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "root":
+			cfg.Roots = roots
+		case "o":
+			cfg.Output = *output
+		case "introspection":
+			cfg.Introspection = *introspection
+		case "jsonschema":
+			cfg.JSONSchema = *jsonSchema
+		case "typescript":
+			cfg.TypeScript = *typeScript
+		case "doc-comments":
+			cfg.Options.DocComments = *docComments
+		case "order":
+			cfg.Options.TypeOrder = schema.TypeOrder(*order)
+		}
+	})
+
+	if flags.NArg() > 0 {
+		cfg.Packages = flags.Args()
+	}
+
+	g, err := generate(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "graphql-schema-generator: %s\n", err)
+
+		return 1
+	}
+
+	for _, warning := range g.Warnings() {
+		fmt.Fprintf(stderr, "graphql-schema-generator: warning: %s\n", warning)
+	}
+
+	if err := buildSchema(g); err != nil {
+		fmt.Fprintf(stderr, "graphql-schema-generator: %s\n", err)
+
+		return 1
+	}
+
+	// An invalid schema is never written, it would only fail later in the GraphQL server.
+	if err := g.Validate(); err != nil {
+		fmt.Fprintf(stderr, "graphql-schema-generator: %s\n", err)
+
+		return 1
+	}
+
+	sdl := g.Build()
+
+	if *compare != "" {
+		return compareSchema(*compare, sdl, *reportFormat, stdout, stderr)
+	}
+
+	toStdout := cfg.Output == "" || cfg.Output == "-"
+
+	var files []outputFile
+
+	if cfg.Introspection != "" {
+		files = append(files, outputFile{path: cfg.Introspection, content: g.Introspection()})
+	}
+
+	if cfg.JSONSchema != "" {
+		files = append(files, outputFile{path: cfg.JSONSchema, content: g.JSONSchema()})
+	}
+
+	if cfg.TypeScript != "" {
+		files = append(files, outputFile{path: cfg.TypeScript, content: g.TypeScript()})
+	}
+
+	if *check {
+		if toStdout {
+			fmt.Fprintln(stderr, "graphql-schema-generator: -check needs an output file to compare the schema with")
+
+			return 2
+		}
+
+		code := checkSchema(cfg.Output, sdl, stdout, stderr)
+
+		for _, file := range files {
+			if fileCode := checkSchema(file.path, file.content, stdout, stderr); fileCode > code {
+				code = fileCode
+			}
+		}
+
+		return code
+	}
+
+	if toStdout {
+		_, err = io.WriteString(stdout, sdl)
+	} else {
+		err = os.WriteFile(cfg.Output, []byte(sdl), 0o644) //nolint: gosec
+	}
+
+	for _, file := range files {
+		if err == nil {
+			err = os.WriteFile(file.path, []byte(file.content), 0o644) //nolint: gosec
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "graphql-schema-generator: %s\n", err)
+
+		return 1
+	}
+
+	return 0
+}
+
+// checkSchema compares the schema with the one in an output file, printing a diff
+// and returning a non-zero exit code when they differ. A missing file is empty.
+func checkSchema(path string, generated string, stdout io.Writer, stderr io.Writer) int {
+	current, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(stderr, "graphql-schema-generator: %s\n", err)
+
+		return 1
+	}
+
+	diff := textdiff.Unified(path, path+" (generated)", string(current), generated)
+	if diff == "" {
+		return 0
+	}
+
+	fmt.Fprint(stdout, diff)
+	fmt.Fprintf(stderr, "graphql-schema-generator: '%s' is out of date, run graphql-schema-generator to update it\n", path)
+
+	return 1
+}
+
+// compareSchema reports the changes made to the schema in the previous file
+// and returns a non-zero exit code when any of them is breaking.
+func compareSchema(path string, sdl string, format string, stdout io.Writer, stderr io.Writer) int {
+	previous, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "graphql-schema-generator: %s\n", err)
+
+		return 1
+	}
+
+	report, err := schemadiff.CompareSDL(string(previous), sdl)
+	if err != nil {
+		fmt.Fprintf(stderr, "graphql-schema-generator: %s\n", err)
+
+		return 1
+	}
+
+	if format == "json" {
+		fmt.Fprint(stdout, report.JSON())
+	} else {
+		fmt.Fprint(stdout, report.String())
+	}
+
+	if report.HasBreakingChanges() {
+		fmt.Fprintf(stderr, "graphql-schema-generator: the schema has breaking changes from '%s'\n", path)
+
+		return 1
+	}
+
+	return 0
+}
+
+// buildSchema builds the schema of the types added to a generator, the checks
+// run on the schema panic when they fail, which is returned as an error.
+func buildSchema(g *schemagen.Generator) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	g.Schema()
+
+	return nil
+}
+
+// generate loads the packages and adds the root types to a generator.
+func generate(cfg *schemagen.Config) (g *schemagen.Generator, err error) {
+	packages, err := sourceloader.Load(".", cfg.Packages...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The type parser panics on types it can't handle, which
+	// is reported like any other error from the command.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	g = schemagen.New(&cfg.Options)
+
+	if len(cfg.Roots) == 0 {
+		for _, s := range packages.Structs() {
+			g.AddSourceStruct(s)
+		}
+	}
+
+	for _, root := range cfg.Roots {
+		rootStruct, err := packages.LookupStruct(root)
+		if err != nil {
+			return nil, err
+		}
+
+		g.AddSourceStruct(rootStruct)
+	}
+
+	return g, nil
+}
+
+// validOrder returns whether the types can be output in an order.
+func validOrder(order schema.TypeOrder) bool {
+	for _, valid := range schema.TypeOrders {
+		if order == valid {
+			return true
+		}
+	}
+
+	return false
+}
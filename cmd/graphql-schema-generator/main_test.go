@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const modulePath = "github.com/warpspeed-cloud/graphql-schema-generator/cmd/graphql-schema-generator"
+
+const expectedModelsSchema = `directive @unique on FIELD_DEFINITION
+
+"User is someone who can sign in."
+type User {
+  "The ID of the user."
+  id: ID!
+  "The username of the user"
+  username: String! @unique
+  "The email of the user"
+  email: String
+  "The projects of the user"
+  projects: [Project!]!
+}
+
+type Project {
+  "The name of the project"
+  name: String!
+  "The meta data of the project"
+  meta: [ProjectMeta!]!
+  "The editors of the project"
+  editors: [User]!
+  "Whether the project is archived"
+  archived: Boolean!
+}
+
+type ProjectMeta {
+  key: String!
+  value: String!
+}
+`
+
+const expectedAlphabeticalSchema = `directive @unique on FIELD_DEFINITION
+
+type Project {
+  "The name of the project"
+  name: String!
+  "The meta data of the project"
+  meta: [ProjectMeta!]!
+  "The editors of the project"
+  editors: [User]!
+  "Whether the project is archived"
+  archived: Boolean!
+}
+
+type ProjectMeta {
+  key: String!
+  value: String!
+}
+
+"User is someone who can sign in."
+type User {
+  "The ID of the user."
+  id: ID!
+  "The username of the user"
+  username: String! @unique
+  "The email of the user"
+  email: String
+  "The projects of the user"
+  projects: [Project!]!
+}
+`
+
+const expectedConfigSchema = `type Project {
+  "The name of the project"
+  name: String!
+  "The meta data of the project"
+  meta: JSON!
+  "Whether the project is archived"
+  archived: Boolean!
+}
+
+scalar JSON
+`
+
+func TestRun(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "schema.graphql")
+
+	tests := []struct {
+		name     string
+		args     []string
+		code     int
+		stdout   string
+		stderr   string
+		expected string
+	}{
+		{
+			name:   "Every struct to stdout",
+			args:   []string{"-config", "testdata/directives.yaml", "./testdata/models"},
+			stdout: expectedModelsSchema,
+		},
+		{
+			name:     "Root type to a file",
+			args:     []string{"-config", "testdata/directives.yaml", "-root", "Project", "-o", output, "./testdata/models"},
+			expected: expectedModelsSchema,
+		},
+		{
+			name:   "Config file",
+			args:   []string{"-config", "testdata/config.yaml"},
+			stdout: expectedConfigSchema,
+		},
+		{
+			name:     "Flags override the config file",
+			args:     []string{"-config", "testdata/config.yaml", "-o", output, "./testdata/models"},
+			expected: expectedConfigSchema,
+		},
+		{
+			name:   "Invalid config file",
+			args:   []string{"-config", "testdata/models/models.go"},
+			code:   1,
+			stderr: "graphql-schema-generator: config 'testdata/models/models.go' must be a .yaml, .yml or .json file\n",
+		},
+		{
+			name:   "Unknown root type",
+			args:   []string{"-root", "Order", "./testdata/models"},
+			code:   1,
+			stderr: "graphql-schema-generator: no struct type 'Order' in the loaded packages\n",
+		},
+		{
+			name:   "Unknown tag option",
+			args:   []string{"./testdata/typos"},
+			stdout: "type Note {\n  text: String!\n}\n",
+			stderr: "graphql-schema-generator: warning: unknown option 'descripton' in the graphql tag on 'Note.Text' at column 1, did you mean 'description'?\n",
+		},
+		{
+			name: "Invalid schema",
+			args: []string{"-root", "Vault", "-o", filepath.Join(t.TempDir(), "invalid.graphql"), "./testdata/invalid"},
+			code: 1,
+			stderr: "graphql-schema-generator: invalid schema: 2 errors\n" +
+				"  Secret: the object type Secret has no fields (Go type invalid.Secret)\n" +
+				"  Vault.updates: the type chan isn't defined (Go field invalid.Vault.Updates)\n",
+		},
+		{
+			name: "Undefined directive",
+			args: []string{"./testdata/models"},
+			code: 1,
+			stderr: "graphql-schema-generator: invalid schema: 1 error\n" +
+				"  User.username: the directive @unique isn't defined (Go field models.User.Username)\n",
+		},
+		{
+			name: "Name collision",
+			args: []string{"-root", "Customer", "./testdata/collisions"},
+			code: 1,
+			stderr: "graphql-schema-generator: The Go types '" + modulePath + "/testdata/collisions.Invoice' and '" +
+				modulePath + "/testdata/collisions/billing.Invoice' are both named 'Invoice' in the schema, " +
+				"give one of them an alias or prefix them with the name of their package\n",
+		},
+		{
+			name:   "Alphabetical order",
+			args:   []string{"-config", "testdata/directives.yaml", "-order", "alphabetical", "./testdata/models"},
+			stdout: expectedAlphabeticalSchema,
+		},
+		{
+			name:   "Unknown order",
+			args:   []string{"-order", "random", "./testdata/models"},
+			code:   2,
+			stderr: "graphql-schema-generator: -order must be discovery, alphabetical or dependency, not 'random'\n",
+		},
+		{
+			name: "Unknown flag",
+			args: []string{"-unknown"},
+			code: 2,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+			assert.Equal(t, test.code, run(test.args, stdout, stderr))
+			assert.Equal(t, test.stdout, stdout.String())
+
+			if test.stderr != "" {
+				assert.Equal(t, test.stderr, stderr.String())
+			}
+
+			if test.expected != "" {
+				written, err := os.ReadFile(output)
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, string(written))
+			}
+		})
+	}
+}
+
+func TestRun_ExtraOutputs(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "schema.graphql")
+	introspection := filepath.Join(dir, "schema.json")
+	jsonSchema := filepath.Join(dir, "types.schema.json")
+	typeScript := filepath.Join(dir, "types.ts")
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	args := []string{"-config", "testdata/config.yaml", "-o", output, "-introspection", introspection, "-jsonschema", jsonSchema, "-typescript", typeScript}
+
+	assert.Equal(t, 0, run(args, stdout, stderr))
+	assert.Empty(t, stderr.String())
+
+	written, err := os.ReadFile(introspection)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(written), "{\n  \"data\": {\n    \"__schema\": {"))
+	assert.Contains(t, string(written), `"name": "Project"`)
+
+	written, err = os.ReadFile(jsonSchema)
+	assert.NoError(t, err)
+	assert.Contains(t, string(written), `"$schema": "https://json-schema.org/draft/2020-12/schema"`)
+	assert.Contains(t, string(written), `"required": [`)
+
+	written, err = os.ReadFile(typeScript)
+	assert.NoError(t, err)
+	assert.Equal(t, `export interface Project {
+  /** The name of the project */
+  name: string;
+  /** The meta data of the project */
+  meta: Record<string, string> | null;
+  /** Whether the project is archived */
+  archived: boolean;
+}
+`, string(written))
+
+	// Check mode compares the introspection file too.
+	assert.NoError(t, os.WriteFile(introspection, []byte("{}"), 0o600))
+	assert.Equal(t, 1, run(append([]string{"-check"}, args...), stdout, stderr))
+	assert.Contains(t, stderr.String(), "'"+introspection+"' is out of date")
+	assert.NotContains(t, stderr.String(), "'"+output+"' is out of date")
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	upToDate := filepath.Join(dir, "up-to-date.graphql")
+	stale := filepath.Join(dir, "stale.graphql")
+	missing := filepath.Join(dir, "missing.graphql")
+
+	assert.NoError(t, os.WriteFile(upToDate, []byte(expectedConfigSchema), 0o600))
+	assert.NoError(t, os.WriteFile(stale, []byte(strings.Replace(expectedConfigSchema, "meta: JSON!", "meta: String", 1)), 0o600))
+
+	tests := []struct {
+		name   string
+		output string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{
+			name:   "Up to date",
+			output: upToDate,
+		},
+		{
+			name:   "Stale",
+			output: stale,
+			code:   1,
+			stdout: `--- ` + stale + `
++++ ` + stale + ` (generated)
+@@ -2,7 +2,7 @@
+   "The name of the project"
+   name: String!
+   "The meta data of the project"
+-  meta: String
++  meta: JSON!
+   "Whether the project is archived"
+   archived: Boolean!
+ }
+`,
+			stderr: "graphql-schema-generator: '" + stale + "' is out of date, run graphql-schema-generator to update it\n",
+		},
+		{
+			name:   "Missing",
+			output: missing,
+			code:   1,
+			stdout: "--- " + missing + "\n+++ " + missing + " (generated)\n@@ -0,0 +1,10 @@\n+" +
+				strings.ReplaceAll(strings.TrimSuffix(expectedConfigSchema, "\n"), "\n", "\n+") + "\n",
+			stderr: "graphql-schema-generator: '" + missing + "' is out of date, run graphql-schema-generator to update it\n",
+		},
+		{
+			name:   "Stdout",
+			output: "-",
+			code:   2,
+			stderr: "graphql-schema-generator: -check needs an output file to compare the schema with\n",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+			assert.Equal(t, test.code, run([]string{"-check", "-config", "testdata/config.yaml", "-o", test.output}, stdout, stderr))
+			assert.Equal(t, test.stdout, stdout.String())
+			assert.Equal(t, test.stderr, stderr.String())
+		})
+	}
+
+	// Nothing is written in check mode.
+	_, err := os.Stat(missing)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCompare(t *testing.T) {
+	dir := t.TempDir()
+	same := filepath.Join(dir, "same.graphql")
+	breaking := filepath.Join(dir, "breaking.graphql")
+	safe := filepath.Join(dir, "safe.graphql")
+
+	assert.NoError(t, os.WriteFile(same, []byte(expectedConfigSchema), 0o600))
+	assert.NoError(t, os.WriteFile(breaking, []byte(strings.Replace(expectedConfigSchema, "}", "  owner: String\n}", 1)), 0o600))
+	assert.NoError(t, os.WriteFile(safe, []byte(strings.Replace(expectedConfigSchema, "  \"Whether the project is archived\"\n  archived: Boolean!\n", "", 1)), 0o600))
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{
+			name:   "No changes",
+			args:   []string{"-compare", same},
+			stdout: "No changes.\n",
+		},
+		{
+			name:   "Breaking changes",
+			args:   []string{"-compare", breaking},
+			code:   1,
+			stdout: "BREAKING  Project.owner: Field Project.owner was removed.\n",
+			stderr: "graphql-schema-generator: the schema has breaking changes from '" + breaking + "'\n",
+		},
+		{
+			name:   "Safe changes as JSON",
+			args:   []string{"-compare", safe, "-report", "json"},
+			stdout: "{\n  \"changes\": [\n    {\n      \"criticality\": \"SAFE\",\n      \"type\": \"FIELD_ADDED\",\n      \"path\": \"Project.archived\",\n      \"message\": \"Field Project.archived was added.\"\n    }\n  ]\n}\n",
+		},
+		{
+			name:   "Unknown report format",
+			args:   []string{"-compare", same, "-report", "xml"},
+			code:   2,
+			stderr: "graphql-schema-generator: -report must be text or json, not 'xml'\n",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+			assert.Equal(t, test.code, run(append([]string{"-config", "testdata/config.yaml"}, test.args...), stdout, stderr))
+			assert.Equal(t, test.stdout, stdout.String())
+			assert.Equal(t, test.stderr, stderr.String())
+		})
+	}
+}
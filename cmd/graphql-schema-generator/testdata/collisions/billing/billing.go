@@ -0,0 +1,5 @@
+package billing
+
+type Invoice struct {
+	Total int `json:"total"`
+}
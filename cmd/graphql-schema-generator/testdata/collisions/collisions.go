@@ -0,0 +1,12 @@
+package collisions
+
+import "github.com/warpspeed-cloud/graphql-schema-generator/cmd/graphql-schema-generator/testdata/collisions/billing"
+
+type Invoice struct {
+	Number string `json:"number"`
+}
+
+type Customer struct {
+	Invoices []Invoice       `json:"invoices"`
+	Billing  billing.Invoice `json:"billing"`
+}
@@ -0,0 +1,12 @@
+package invalid
+
+// Secret has no field in the schema, which makes it an empty type.
+type Secret struct {
+	Value string `json:"-"`
+}
+
+type Vault struct {
+	Name    string   `json:"name"`
+	Secret  Secret   `json:"secret"`
+	Updates chan int `json:"updates"`
+}
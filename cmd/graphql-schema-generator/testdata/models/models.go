@@ -0,0 +1,25 @@
+package models
+
+// User is someone who can sign in.
+type User struct {
+	// The ID of the user.
+	ID       string    `json:"id"`
+	Username string    `json:"username" graphql:"description=The username of the user,decorators=[+unique()]"`
+	Password string    `json:"-"`
+	Email    *string   `json:"email" graphql:"description=The email of the user"`
+	Projects []Project `json:"projects" graphql:"description=The projects of the user"`
+}
+
+type Project struct {
+	Name     string            `json:"name" graphql:"description=The name of the project"`
+	Meta     map[string]string `json:"meta" graphql:"description=The meta data of the project"`
+	Editors  []*User           `json:"editors" graphql:"description=The editors of the project"`
+	Archived bool              `json:"archived" graphql:"description=Whether the project is archived"`
+}
+
+type unexported struct {
+	Name string
+}
+
+// Keep the linter from complaining about the unexported type.
+var _ = unexported{}
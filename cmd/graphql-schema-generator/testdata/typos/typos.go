@@ -0,0 +1,5 @@
+package typos
+
+type Note struct {
+	Text string `json:"text" graphql:"descripton=The text of the note"`
+}
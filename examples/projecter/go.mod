@@ -5,3 +5,5 @@ replace github.com/warpspeed-cloud/graphql-schema-generator => ../../
 go 1.18
 
 require github.com/warpspeed-cloud/graphql-schema-generator v0.0.0-00010101000000-000000000000
+
+require gopkg.in/yaml.v3 v3.0.1 // indirect
@@ -3,7 +3,8 @@ package main
 import (
 	"log"
 
-	builder "github.com/warpspeed-cloud/graphql-schema-generator/internal/builder"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schemagen"
 )
 
 type Roles uint
@@ -40,33 +41,46 @@ func (w *GraphQLSchemaFileWriter) WriteSchema(schema string) {
 
 func main() {
 	// First we must configure the builder instance.
-	// This simple project doesn't any plugins.
-	schema := builder.NewGraphQLSchemaBuilder(&builder.GraphQLSchemaBuilderOptions{
+	// This simple project doesn't use any plugins, see schemagen.Plugin to change the schema before it is output.
+	generator := schemagen.New(&schemagen.Options{
 		Writer: &GraphQLSchemaFileWriter{},
+		// Fields of type Roles are output as the Roles enum added below rather than as an Int.
+		Scalars: map[string]string{"main.Roles": "Roles"},
+		// User.ID is output as the ID scalar rather than a String, as is every field matching
+		// these patterns, which are the default. An empty list outputs IDs as their Go type.
+		IDFields: schemagen.DefaultIDFields,
 	})
 
 	// Now that the schema builder is configured, we can add types to it.
 	// This will take the User struct and recursively parse it and discover all the types and other
 	// nested structs that it contains and add them to our builder schema.
 	// GOTCHA: Due to the way Go handles enums, we need to manually add our Roles enum to the schema a bit later.
-	schema.AddStruct(User{}, nil)
+	generator.AddStruct(User{})
 
 	// The Schema should now have both the user and project types registered to it, due to the way that
 	// Go handles enums we now must also add the Roles enum to the schema.
-	schema.AddEnum(builder.Enum{
+	generator.AddEnum(schemagen.Enum{
 		Name: "Roles",
-		Values: []*builder.EnumKeyPairOptions{
+		Values: []schemagen.EnumValue{
 			{
-				Key:   "USER",
-				Value: "0",
+				Name:  "USER",
+				Value: Roles_USER,
 			},
 			{
-				Key:   "ADMIN",
-				Value: "1",
+				Name:  "ADMIN",
+				Value: Roles_ADMIN,
 			},
 		},
 	})
 
+	// The unique decorator on User.username applies a directive, which must be defined
+	// for the schema to be valid. Only built in directives like @deprecated don't need to be.
+	generator.AddDirective(&schema.Directive{
+		Name:        "unique",
+		Description: "The value of the field is unique across all the objects of its type.",
+		Locations:   []string{"FIELD_DEFINITION"},
+	})
+
 	// Now that we have added all the types to the schema, we can generate the schema.
-	schema.Build()
+	generator.Build()
 }
@@ -0,0 +1,194 @@
+package builder
+
+import (
+	"encoding/json"
+	"go/types"
+	"sync"
+
+	typeparser "github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+// Writer receives the schema once it has been built.
+type Writer interface {
+	WriteSchema(schema string)
+}
+
+type EnumKeyPairOptions struct {
+	Key         string
+	Value       interface{}
+	Description *string
+	// Deprecation is the reason the value is deprecated, an empty reason deprecates it without one.
+	Deprecation *string
+}
+
+type Enum struct {
+	Name        string
+	Description *string
+	Values      []*EnumKeyPairOptions
+}
+
+// MapStrategy is how maps are printed, as GraphQL doesn't have maps.
+type MapStrategy string
+
+const (
+	// MapStrategyEntries prints a map as a list of key value pairs, i.e. [ProjectMeta!]!
+	// where ProjectMeta is a type with a key and a value field.
+	MapStrategyEntries MapStrategy = "entries"
+	// MapStrategyScalar prints a map as a scalar, JSON unless MapScalar says otherwise.
+	MapStrategyScalar MapStrategy = "scalar"
+
+	// DefaultMapScalar is the scalar maps are printed as with MapStrategyScalar.
+	DefaultMapScalar = "JSON"
+)
+
+type GraphQLSchemaBuilderOptions struct {
+	// Writer is given the schema when it is built, it is optional
+	// as the schema is also returned by Build.
+	Writer Writer
+
+	// TypeParserOptions configure how types are discovered.
+	TypeParserOptions *typeparser.TypeParserOptions
+
+	// MapStrategy is how maps are printed, MapStrategyEntries by default.
+	MapStrategy MapStrategy
+
+	// MapScalar is the scalar maps are printed as with MapStrategyScalar.
+	MapScalar string
+
+	// Federation prints the schema as an Apollo Federation v2 subgraph.
+	Federation bool
+
+	// TypeOrder is the order types are printed in, the order they were found by default.
+	TypeOrder schema.TypeOrder
+}
+
+// GraphQLSchemaBuilder discovers types with a TypeParser and prints them as a GraphQL schema.
+type GraphQLSchemaBuilder struct {
+	options *GraphQLSchemaBuilderOptions
+	parser  *typeparser.TypeParser
+
+	// mu guards the directive definitions added so far, the parser has its own lock.
+	mu         sync.Mutex
+	directives []*schema.Directive
+}
+
+func NewGraphQLSchemaBuilder(options *GraphQLSchemaBuilderOptions) *GraphQLSchemaBuilder {
+	if options == nil {
+		options = &GraphQLSchemaBuilderOptions{}
+	}
+
+	return &GraphQLSchemaBuilder{
+		options: options,
+		parser:  typeparser.NewTypeParser(options.TypeParserOptions),
+	}
+}
+
+// AddStruct adds a struct, and every type discovered from it, to the schema.
+// See typeparser.TypeParser.AddStruct.
+func (b *GraphQLSchemaBuilder) AddStruct(s any, options *typeparser.AddStructOptions) *GraphQLSchemaBuilder {
+	b.parser.AddStruct(s, options)
+
+	return b
+}
+
+// AddSourceStruct adds a struct type that was type checked from source, and every
+// type discovered from it, to the schema. See typeparser.TypeParser.AddSourceStruct.
+func (b *GraphQLSchemaBuilder) AddSourceStruct(s types.Type, options *typeparser.AddStructOptions) *GraphQLSchemaBuilder {
+	b.parser.AddSourceStruct(s, options)
+
+	return b
+}
+
+// AddEnum adds an enum to the schema, Go enums can't be discovered so they are added by hand.
+func (b *GraphQLSchemaBuilder) AddEnum(e Enum) *GraphQLSchemaBuilder {
+	values := make([]typeparser.EnumKeyPairOptions, 0, len(e.Values))
+
+	for _, value := range e.Values {
+		if value != nil {
+			values = append(values, typeparser.EnumKeyPairOptions(*value))
+		}
+	}
+
+	b.parser.AddEnum(typeparser.Enum{
+		Name:        e.Name,
+		Description: e.Description,
+		Values:      values,
+	})
+
+	return b
+}
+
+// AddDirective adds the definition of a directive applied by decorators to the schema.
+// See typeparser.SchemaOptions.Directives.
+func (b *GraphQLSchemaBuilder) AddDirective(directive *schema.Directive) *GraphQLSchemaBuilder {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.directives = append(b.directives, directive)
+
+	return b
+}
+
+// Schema returns the types added so far as a schema, before it is printed.
+func (b *GraphQLSchemaBuilder) Schema() *schema.Schema {
+	b.mu.Lock()
+	directives := append([]*schema.Directive(nil), b.directives...)
+	b.mu.Unlock()
+
+	options := &typeparser.SchemaOptions{
+		Federation: b.options.Federation,
+		Directives: directives,
+		TypeOrder:  b.options.TypeOrder,
+	}
+
+	if b.options.MapStrategy == MapStrategyScalar {
+		options.MapScalar = b.options.MapScalar
+		if options.MapScalar == "" {
+			options.MapScalar = DefaultMapScalar
+		}
+	}
+
+	return b.parser.Schema(options)
+}
+
+// Validate checks a schema built from the types added so far against the GraphQL
+// specification, see typeparser.TypeParser.Validate.
+func (b *GraphQLSchemaBuilder) Validate(s *schema.Schema) error {
+	return b.parser.Validate(s)
+}
+
+// Warnings returns the problems found in the types added so far that don't prevent the
+// schema from being built, see typeparser.TypeParser.Warnings.
+func (b *GraphQLSchemaBuilder) Warnings() []string {
+	return b.parser.Warnings()
+}
+
+// JSONSchema prints the types added so far as a JSON Schema document, see
+// typeparser.TypeParser.JSONSchema.
+func (b *GraphQLSchemaBuilder) JSONSchema() string {
+	// The document only holds strings, booleans, slices and the values of enums.
+	out, err := json.MarshalIndent(b.parser.JSONSchema(), "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	return string(out) + "\n"
+}
+
+// TypeScript prints the types added so far as TypeScript declarations, see
+// typeparser.TypeParser.TypeScript.
+func (b *GraphQLSchemaBuilder) TypeScript(options *typeparser.TypeScriptOptions) string {
+	return b.parser.TypeScript(options)
+}
+
+// Build prints the schema, hands it to the writer if there is one and returns it.
+func (b *GraphQLSchemaBuilder) Build() string {
+	sdl := Print(b.Schema())
+
+	if b.options.Writer != nil {
+		b.options.Writer.WriteSchema(sdl)
+	}
+
+	return sdl
+}
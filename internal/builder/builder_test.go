@@ -0,0 +1,405 @@
+package builder_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warpspeed-cloud/graphql-schema-generator/internal/builder"
+	"github.com/warpspeed-cloud/graphql-schema-generator/internal/ptr"
+	typeparser "github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+type Roles uint
+
+type User struct {
+	ID       string    `json:"id" graphql:"description=The ID of the user"`
+	Username string    `json:"username" graphql:"description=The username of the user,decorators=[+unique(), +requireAuthRole(role: \"admin\")]"`
+	Password string    `json:"-"`
+	Email    *string   `json:"email" graphql:"description=The email of the user"`
+	Roles    []Roles   `json:"roles"`
+	Projects []Project `json:"projects" graphql:"description=The projects of the user"`
+}
+
+type Project struct {
+	Name     string            `json:"name"`
+	Meta     map[string]string `json:"meta"`
+	Editors  *[]*User          `json:"editors"`
+	Archived bool              `json:"archived"`
+}
+
+type collectingWriter struct {
+	schema string
+}
+
+func (w *collectingWriter) WriteSchema(schema string) {
+	w.schema = schema
+}
+
+func TestBuild(t *testing.T) {
+	writer := &collectingWriter{}
+
+	sdl := builder.NewGraphQLSchemaBuilder(&builder.GraphQLSchemaBuilderOptions{
+		Writer: writer,
+	}).AddDirective(&schema.Directive{
+		Name:      "unique",
+		Locations: []string{"FIELD_DEFINITION"},
+	}).AddDirective(&schema.Directive{
+		Name:      "requireAuthRole",
+		Arguments: []*schema.InputValue{{Name: "role", Type: schema.NonNullOf(schema.Named("String"))}},
+		Locations: []string{"FIELD_DEFINITION"},
+	}).AddStruct(User{}, nil).AddEnum(builder.Enum{
+		Name:        "Roles",
+		Description: ptr.Of("The roles a user can have.\n\nRoles are additive."),
+		Values: []*builder.EnumKeyPairOptions{
+			{Key: "USER", Value: 0},
+			{Key: "ADMIN", Value: 1, Description: ptr.Of("Can do anything")},
+		},
+	}).Build()
+
+	assert.Equal(t, `directive @unique on FIELD_DEFINITION
+
+directive @requireAuthRole(role: String!) on FIELD_DEFINITION
+
+type Project {
+  name: String!
+  meta: [ProjectMeta!]!
+  editors: [User]
+  archived: Boolean!
+}
+
+type User {
+  "The ID of the user"
+  id: String!
+  "The username of the user"
+  username: String! @unique @requireAuthRole(role: "admin")
+  "The email of the user"
+  email: String
+  roles: [Int!]!
+  "The projects of the user"
+  projects: [Project!]!
+}
+
+type ProjectMeta {
+  key: String!
+  value: String!
+}
+
+"""
+The roles a user can have.
+
+Roles are additive.
+"""
+enum Roles {
+  USER
+  "Can do anything"
+  ADMIN
+}
+`, sdl)
+	assert.Equal(t, sdl, writer.schema)
+}
+
+func TestBuild_NestedMaps(t *testing.T) {
+	schema := builder.NewGraphQLSchemaBuilder(nil).AddStruct(struct {
+		Regions map[string]*map[string]*string `json:"regions"`
+	}{}, nil).Build()
+
+	assert.Equal(t, `type Struct0 {
+  regions: [Struct0Regions!]!
+}
+
+type Struct0RegionsMap1 {
+  key: String!
+  value: String
+}
+
+type Struct0Regions {
+  key: String!
+  value: [Struct0RegionsMap1!]
+}
+`, schema)
+}
+
+type Event struct {
+	Name       string            `json:"name"`
+	StartsAt   time.Time         `json:"startsAt"`
+	EndsAt     *time.Time        `json:"endsAt"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+func TestBuild_Scalars(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *builder.GraphQLSchemaBuilderOptions
+		expected string
+	}{
+		{
+			name: "Custom scalars are declared",
+			options: &builder.GraphQLSchemaBuilderOptions{
+				TypeParserOptions: &typeparser.TypeParserOptions{
+					Scalars: map[string]string{"time.Time": "DateTime"},
+				},
+			},
+			expected: `type Event {
+  name: String!
+  startsAt: DateTime!
+  endsAt: DateTime
+  attributes: [EventAttributes!]!
+}
+
+type EventAttributes {
+  key: String!
+  value: String!
+}
+
+scalar DateTime
+`,
+		},
+		{
+			name: "Maps as a scalar",
+			options: &builder.GraphQLSchemaBuilderOptions{
+				TypeParserOptions: &typeparser.TypeParserOptions{
+					Scalars: map[string]string{"time.Time": "DateTime"},
+				},
+				MapStrategy: builder.MapStrategyScalar,
+			},
+			expected: `type Event {
+  name: String!
+  startsAt: DateTime!
+  endsAt: DateTime
+  attributes: JSON!
+}
+
+scalar DateTime
+
+scalar JSON
+`,
+		},
+		{
+			name: "Excluded types and maps as a named scalar",
+			options: &builder.GraphQLSchemaBuilderOptions{
+				TypeParserOptions: &typeparser.TypeParserOptions{
+					ExcludeTypes: []string{"time.Time"},
+				},
+				MapStrategy: builder.MapStrategyScalar,
+				MapScalar:   "Attributes",
+			},
+			expected: `type Event {
+  name: String!
+  attributes: Attributes!
+}
+
+scalar Attributes
+`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.expected, builder.NewGraphQLSchemaBuilder(test.options).AddStruct(Event{}, nil).Build())
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	reason := `"Use name"`
+	first := "10"
+
+	s := &schema.Schema{
+		Directives: []*schema.Directive{
+			{
+				Name:        "auth",
+				Description: "Restricts a field to a role.",
+				Arguments:   []*schema.InputValue{{Name: "role", Type: schema.NonNullOf(schema.Named("String"))}},
+				Locations:   []string{"FIELD_DEFINITION", "OBJECT"},
+				Repeatable:  true,
+			},
+		},
+		Types: []schema.Type{
+			&schema.InterfaceType{
+				Name:   "Node",
+				Fields: []*schema.Field{{Name: "id", Type: schema.NonNullOf(schema.Named("ID"))}},
+			},
+			&schema.ObjectType{
+				Name:       "User",
+				Interfaces: []string{"Node"},
+				Directives: []*schema.AppliedDirective{{Name: "auth", Arguments: []*schema.Argument{{Name: "role", Value: `"admin"`}}}},
+				Fields: []*schema.Field{
+					{Name: "id", Type: schema.NonNullOf(schema.Named("ID"))},
+					{
+						Name:       "username",
+						Type:       schema.Named("String"),
+						Directives: []*schema.AppliedDirective{{Name: "deprecated", Arguments: []*schema.Argument{{Name: "reason", Value: reason}}}},
+					},
+					{
+						Name: "projects",
+						Arguments: []*schema.InputValue{
+							{Name: "first", Description: "How many", Type: schema.Named("Int"), DefaultValue: &first},
+							{Name: "filter", Type: schema.Named("ProjectFilter")},
+						},
+						Type: schema.NonNullOf(schema.ListOf(schema.NonNullOf(schema.Named("Project")))),
+					},
+				},
+			},
+			&schema.UnionType{Name: "SearchResult", Members: []string{"User", "Project"}},
+			&schema.InputObjectType{
+				Name:   "ProjectFilter",
+				Fields: []*schema.InputValue{{Name: "archived", Description: "Archived projects only", Type: schema.Named("Boolean")}},
+			},
+			&schema.EnumType{
+				Name:   "Role",
+				Values: []*schema.EnumValue{{Name: "USER"}, {Name: "ADMIN", Directives: []*schema.AppliedDirective{{Name: "deprecated"}}}},
+			},
+			&schema.ScalarType{Name: "Date", Description: "A date, i.e. 2006-01-02."},
+		},
+	}
+
+	assert.Equal(t, `"Restricts a field to a role."
+directive @auth(role: String!) repeatable on FIELD_DEFINITION | OBJECT
+
+interface Node {
+  id: ID!
+}
+
+type User implements Node @auth(role: "admin") {
+  id: ID!
+  username: String @deprecated(reason: "Use name")
+  projects("How many" first: Int = 10, filter: ProjectFilter): [Project!]!
+}
+
+union SearchResult = User | Project
+
+input ProjectFilter {
+  "Archived projects only"
+  archived: Boolean
+}
+
+enum Role {
+  USER
+  ADMIN @deprecated
+}
+
+"A date, i.e. 2006-01-02."
+scalar Date
+`, builder.Print(s))
+}
+
+func TestIntrospection(t *testing.T) {
+	first := "10"
+
+	s := &schema.Schema{
+		Directives: []*schema.Directive{
+			{Name: "auth", Arguments: []*schema.InputValue{{Name: "role", Type: schema.NonNullOf(schema.Named("Role"))}}, Locations: []string{"OBJECT"}, Repeatable: true},
+		},
+		Types: []schema.Type{
+			&schema.InterfaceType{
+				Name:   "Node",
+				Fields: []*schema.Field{{Name: "id", Type: schema.NonNullOf(schema.Named("ID"))}},
+			},
+			&schema.ObjectType{
+				Name:        "User",
+				Description: "Someone who can sign in.",
+				Interfaces:  []string{"Node"},
+				Fields: []*schema.Field{
+					{Name: "id", Type: schema.NonNullOf(schema.Named("ID"))},
+					{
+						Name:       "username",
+						Type:       schema.Named("String"),
+						Directives: []*schema.AppliedDirective{{Name: "deprecated", Arguments: []*schema.Argument{{Name: "reason", Value: `"Use name"`}}}},
+					},
+				},
+			},
+			&schema.ObjectType{
+				Name: "Query",
+				Fields: []*schema.Field{{
+					Name:      "users",
+					Arguments: []*schema.InputValue{{Name: "first", Type: schema.Named("Int"), DefaultValue: &first}},
+					Type:      schema.NonNullOf(schema.ListOf(schema.NonNullOf(schema.Named("User")))),
+				}},
+			},
+			&schema.UnionType{Name: "SearchResult", Members: []string{"User"}},
+			&schema.EnumType{
+				Name:   "Role",
+				Values: []*schema.EnumValue{{Name: "USER"}, {Name: "ADMIN", Directives: []*schema.AppliedDirective{{Name: "deprecated"}}}},
+			},
+			&schema.ScalarType{
+				Name:       "Date",
+				Directives: []*schema.AppliedDirective{{Name: "specifiedBy", Arguments: []*schema.Argument{{Name: "url", Value: `"https://example.com/date"`}}}},
+			},
+		},
+	}
+
+	var result struct {
+		Data struct {
+			Schema struct {
+				QueryType    *struct{ Name string } `json:"queryType"`
+				MutationType *struct{ Name string } `json:"mutationType"`
+				Types        []map[string]any       `json:"types"`
+				Directives   []map[string]any       `json:"directives"`
+			} `json:"__schema"`
+		} `json:"data"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(builder.Introspection(s)), &result))
+
+	introspected := result.Data.Schema
+	assert.Equal(t, "Query", introspected.QueryType.Name)
+	assert.Nil(t, introspected.MutationType)
+
+	types := map[string]map[string]any{}
+	names := make([]string, 0, len(introspected.Types))
+
+	for _, t := range introspected.Types {
+		types[t["name"].(string)] = t
+		names = append(names, t["name"].(string))
+	}
+
+	// Float isn't used so it is left out, String and Boolean are always there.
+	assert.Equal(t, []string{"Node", "User", "Query", "SearchResult", "Role", "Date", "String", "Boolean", "Int", "ID"}, names)
+
+	assert.Equal(t, []any{map[string]any{"kind": "OBJECT", "name": "User", "ofType": nil}}, types["Node"]["possibleTypes"])
+	assert.Equal(t, "Someone who can sign in.", types["User"]["description"])
+	assert.Equal(t, []any{map[string]any{"kind": "INTERFACE", "name": "Node", "ofType": nil}}, types["User"]["interfaces"])
+	assert.Equal(t, map[string]any{
+		"name":              "username",
+		"description":       nil,
+		"args":              []any{},
+		"type":              map[string]any{"kind": "SCALAR", "name": "String", "ofType": nil},
+		"isDeprecated":      true,
+		"deprecationReason": "Use name",
+	}, types["User"]["fields"].([]any)[1])
+	assert.Nil(t, types["User"]["enumValues"])
+
+	users := types["Query"]["fields"].([]any)[0].(map[string]any)
+	assert.Equal(t, "10", users["args"].([]any)[0].(map[string]any)["defaultValue"])
+	assert.Equal(t, map[string]any{
+		"kind": "NON_NULL",
+		"name": nil,
+		"ofType": map[string]any{
+			"kind": "LIST",
+			"name": nil,
+			"ofType": map[string]any{
+				"kind":   "NON_NULL",
+				"name":   nil,
+				"ofType": map[string]any{"kind": "OBJECT", "name": "User", "ofType": nil},
+			},
+		},
+	}, users["type"])
+
+	admin := types["Role"]["enumValues"].([]any)[1].(map[string]any)
+	assert.Equal(t, true, admin["isDeprecated"])
+	assert.Equal(t, "No longer supported", admin["deprecationReason"])
+	assert.Equal(t, "https://example.com/date", types["Date"]["specifiedByURL"])
+
+	directives := make([]string, 0, len(introspected.Directives))
+	for _, directive := range introspected.Directives {
+		directives = append(directives, directive["name"].(string))
+	}
+
+	assert.Equal(t, []string{"include", "skip", "deprecated", "specifiedBy", "auth"}, directives)
+	assert.Equal(t, true, introspected.Directives[4]["isRepeatable"])
+}
@@ -0,0 +1,369 @@
+package builder
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/warpspeed-cloud/graphql-schema-generator/internal/ptr"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+// introspectionScalars are the built-in scalars in the order they are output. String
+// and Boolean are always output as the built-in directives refer to them.
+var introspectionScalars = []struct {
+	name        string
+	description string
+	always      bool
+}{
+	{"String", "The `String` scalar type represents textual data, represented as UTF-8 character sequences.", true},
+	{"Boolean", "The `Boolean` scalar type represents `true` or `false`.", true},
+	{"Int", "The `Int` scalar type represents non-fractional signed whole numeric values.", false},
+	{"Float", "The `Float` scalar type represents signed double-precision fractional values.", false},
+	{"ID", "The `ID` scalar type represents a unique identifier, serialized as a string.", false},
+}
+
+type introspectionResult struct {
+	Data struct {
+		Schema introspectionSchema `json:"__schema"`
+	} `json:"data"`
+}
+
+type introspectionSchema struct {
+	Description      *string                  `json:"description"`
+	QueryType        *introspectionName       `json:"queryType"`
+	MutationType     *introspectionName       `json:"mutationType"`
+	SubscriptionType *introspectionName       `json:"subscriptionType"`
+	Types            []introspectionType      `json:"types"`
+	Directives       []introspectionDirective `json:"directives"`
+}
+
+type introspectionName struct {
+	Name string `json:"name"`
+}
+
+type introspectionType struct {
+	Kind           schema.Kind               `json:"kind"`
+	Name           string                    `json:"name"`
+	Description    *string                   `json:"description"`
+	SpecifiedByURL *string                   `json:"specifiedByURL"`
+	Fields         []introspectionField      `json:"fields"`
+	InputFields    []introspectionInputValue `json:"inputFields"`
+	Interfaces     []introspectionTypeRef    `json:"interfaces"`
+	EnumValues     []introspectionEnumValue  `json:"enumValues"`
+	PossibleTypes  []introspectionTypeRef    `json:"possibleTypes"`
+}
+
+type introspectionField struct {
+	Name              string                    `json:"name"`
+	Description       *string                   `json:"description"`
+	Args              []introspectionInputValue `json:"args"`
+	Type              introspectionTypeRef      `json:"type"`
+	IsDeprecated      bool                      `json:"isDeprecated"`
+	DeprecationReason *string                   `json:"deprecationReason"`
+}
+
+type introspectionInputValue struct {
+	Name              string               `json:"name"`
+	Description       *string              `json:"description"`
+	Type              introspectionTypeRef `json:"type"`
+	DefaultValue      *string              `json:"defaultValue"`
+	IsDeprecated      bool                 `json:"isDeprecated"`
+	DeprecationReason *string              `json:"deprecationReason"`
+}
+
+type introspectionEnumValue struct {
+	Name              string  `json:"name"`
+	Description       *string `json:"description"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+type introspectionTypeRef struct {
+	Kind   schema.Kind           `json:"kind"`
+	Name   *string               `json:"name"`
+	OfType *introspectionTypeRef `json:"ofType"`
+}
+
+type introspectionDirective struct {
+	Name         string                    `json:"name"`
+	Description  *string                   `json:"description"`
+	IsRepeatable bool                      `json:"isRepeatable"`
+	Locations    []string                  `json:"locations"`
+	Args         []introspectionInputValue `json:"args"`
+}
+
+// introspector turns a schema into the result of an introspection query.
+type introspector struct {
+	schema *schema.Schema
+}
+
+// Introspection prints a schema as the JSON result of the introspection query, i.e.
+// {"data": {"__schema": ...}}, as read by tools that don't read SDL. The built-in
+// scalars and directives are included, the introspection types, i.e. __Type, aren't.
+func Introspection(s *schema.Schema) string {
+	i := &introspector{schema: s}
+
+	result := introspectionResult{}
+	result.Data.Schema = introspectionSchema{
+		QueryType:        i.rootType("Query"),
+		MutationType:     i.rootType("Mutation"),
+		SubscriptionType: i.rootType("Subscription"),
+		Types:            i.types(),
+		Directives:       i.directives(),
+	}
+
+	// The result only holds strings, booleans and slices, which always marshal.
+	out, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	return string(out) + "\n"
+}
+
+// rootType returns the name of a root operation type, when the schema has it.
+func (i *introspector) rootType(name string) *introspectionName {
+	if _, ok := i.schema.Type(name).(*schema.ObjectType); !ok {
+		return nil
+	}
+
+	return &introspectionName{Name: name}
+}
+
+func (i *introspector) types() []introspectionType {
+	types := make([]introspectionType, 0, len(i.schema.Types)+len(introspectionScalars))
+
+	for _, t := range i.schema.Types {
+		types = append(types, i.namedType(t))
+	}
+
+	used := i.usedTypes()
+
+	for _, scalar := range introspectionScalars {
+		if scalar.always || used[scalar.name] {
+			types = append(types, introspectionType{
+				Kind:        schema.KindScalar,
+				Name:        scalar.name,
+				Description: optionalString(scalar.description),
+			})
+		}
+	}
+
+	return types
+}
+
+// usedTypes returns the names of the types fields, arguments and input fields refer to.
+func (i *introspector) usedTypes() map[string]bool {
+	used := map[string]bool{}
+
+	addValues := func(values []*schema.InputValue) {
+		for _, value := range values {
+			used[value.Type.NamedType()] = true
+		}
+	}
+
+	addFields := func(fields []*schema.Field) {
+		for _, field := range fields {
+			used[field.Type.NamedType()] = true
+			addValues(field.Arguments)
+		}
+	}
+
+	for _, t := range i.schema.Types {
+		switch t := t.(type) {
+		case *schema.ObjectType:
+			addFields(t.Fields)
+		case *schema.InterfaceType:
+			addFields(t.Fields)
+		case *schema.InputObjectType:
+			addValues(t.Fields)
+		}
+	}
+
+	for _, directive := range i.schema.Directives {
+		addValues(directive.Arguments)
+	}
+
+	return used
+}
+
+func (i *introspector) namedType(t schema.Type) introspectionType {
+	result := introspectionType{
+		Kind:        t.TypeKind(),
+		Name:        t.TypeName(),
+		Description: optionalString(t.TypeDescription()),
+	}
+
+	switch t := t.(type) {
+	case *schema.ObjectType:
+		result.Fields = i.fields(t.Fields)
+		result.Interfaces = i.namedRefs(t.Interfaces)
+	case *schema.InterfaceType:
+		result.Fields = i.fields(t.Fields)
+		result.Interfaces = i.namedRefs(t.Interfaces)
+		result.PossibleTypes = i.namedRefs(i.implementations(t.Name))
+	case *schema.UnionType:
+		result.PossibleTypes = i.namedRefs(t.Members)
+	case *schema.EnumType:
+		result.EnumValues = make([]introspectionEnumValue, 0, len(t.Values))
+
+		for _, value := range t.Values {
+			result.EnumValues = append(result.EnumValues, introspectionEnumValue{
+				Name:              value.Name,
+				Description:       optionalString(value.Description),
+				IsDeprecated:      value.IsDeprecated(),
+				DeprecationReason: deprecationReason(value.Directives),
+			})
+		}
+	case *schema.InputObjectType:
+		result.InputFields = i.inputValues(t.Fields)
+	case *schema.ScalarType:
+		if specifiedBy := schema.FindDirective(t.Directives, "specifiedBy"); specifiedBy != nil {
+			result.SpecifiedByURL = argumentString(specifiedBy, "url")
+		}
+	}
+
+	return result
+}
+
+// implementations returns the names of the object types implementing an interface.
+func (i *introspector) implementations(name string) []string {
+	var names []string
+
+	for _, t := range i.schema.Types {
+		if object, ok := t.(*schema.ObjectType); ok {
+			for _, implemented := range object.Interfaces {
+				if implemented == name {
+					names = append(names, object.Name)
+				}
+			}
+		}
+	}
+
+	return names
+}
+
+func (i *introspector) directives() []introspectionDirective {
+	builtinDirectives := schema.BuiltinDirectives()
+	directives := make([]introspectionDirective, 0, len(builtinDirectives)+len(i.schema.Directives))
+
+	for _, directive := range append(builtinDirectives, i.schema.Directives...) {
+		directives = append(directives, introspectionDirective{
+			Name:         directive.Name,
+			Description:  optionalString(directive.Description),
+			IsRepeatable: directive.Repeatable,
+			Locations:    append([]string{}, directive.Locations...),
+			Args:         i.inputValues(directive.Arguments),
+		})
+	}
+
+	return directives
+}
+
+func (i *introspector) fields(fields []*schema.Field) []introspectionField {
+	result := make([]introspectionField, 0, len(fields))
+
+	for _, field := range fields {
+		result = append(result, introspectionField{
+			Name:              field.Name,
+			Description:       optionalString(field.Description),
+			Args:              i.inputValues(field.Arguments),
+			Type:              i.typeRef(field.Type),
+			IsDeprecated:      field.IsDeprecated(),
+			DeprecationReason: deprecationReason(field.Directives),
+		})
+	}
+
+	return result
+}
+
+func (i *introspector) inputValues(values []*schema.InputValue) []introspectionInputValue {
+	result := make([]introspectionInputValue, 0, len(values))
+
+	for _, value := range values {
+		result = append(result, introspectionInputValue{
+			Name:              value.Name,
+			Description:       optionalString(value.Description),
+			Type:              i.typeRef(value.Type),
+			DefaultValue:      value.DefaultValue,
+			IsDeprecated:      value.IsDeprecated(),
+			DeprecationReason: deprecationReason(value.Directives),
+		})
+	}
+
+	return result
+}
+
+// typeRef returns a reference to a type, the kind of a named type is looked up
+// in the schema, built-in and unknown types are taken for scalars.
+func (i *introspector) typeRef(ref *schema.TypeRef) introspectionTypeRef {
+	switch {
+	case ref.NonNull:
+		ofType := i.typeRef(ref.OfType)
+
+		return introspectionTypeRef{Kind: "NON_NULL", OfType: &ofType}
+	case ref.List:
+		ofType := i.typeRef(ref.OfType)
+
+		return introspectionTypeRef{Kind: "LIST", OfType: &ofType}
+	}
+
+	name := ref.Name
+	kind := schema.KindScalar
+
+	if t := i.schema.Type(name); t != nil {
+		kind = t.TypeKind()
+	}
+
+	return introspectionTypeRef{Kind: kind, Name: &name}
+}
+
+func (i *introspector) namedRefs(names []string) []introspectionTypeRef {
+	refs := make([]introspectionTypeRef, 0, len(names))
+
+	for _, name := range names {
+		refs = append(refs, i.typeRef(schema.Named(name)))
+	}
+
+	return refs
+}
+
+// deprecationReason returns the reason of the @deprecated directive, if any,
+// which defaults to the reason of the directive definition.
+func deprecationReason(directives []*schema.AppliedDirective) *string {
+	deprecated := schema.FindDirective(directives, "deprecated")
+	if deprecated == nil {
+		return nil
+	}
+
+	if reason := argumentString(deprecated, "reason"); reason != nil {
+		return reason
+	}
+
+	return ptr.Of(schema.DefaultDeprecationReason)
+}
+
+// argumentString returns the value of a string argument of an applied directive.
+func argumentString(directive *schema.AppliedDirective, name string) *string {
+	for _, argument := range directive.Arguments {
+		if argument.Name != name {
+			continue
+		}
+
+		if value, err := strconv.Unquote(argument.Value); err == nil {
+			return &value
+		}
+
+		return &argument.Value
+	}
+
+	return nil
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
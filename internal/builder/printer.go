@@ -0,0 +1,205 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+const indent = "  "
+
+// printDescription prints a description above a definition, using a block
+// string when the description spans multiple lines.
+func printDescription(out *strings.Builder, description string, prefix string) {
+	if description == "" {
+		return
+	}
+
+	if !strings.Contains(description, "\n") {
+		out.WriteString(fmt.Sprintf("%s%q\n", prefix, description))
+
+		return
+	}
+
+	out.WriteString(prefix + `"""` + "\n")
+
+	for _, line := range strings.Split(strings.ReplaceAll(description, `"""`, `\"""`), "\n") {
+		if line == "" {
+			out.WriteString("\n")
+		} else {
+			out.WriteString(prefix + line + "\n")
+		}
+	}
+
+	out.WriteString(prefix + `"""` + "\n")
+}
+
+// printDirectives prints the directives applied to a definition, i.e. @auth(role: "admin").
+func printDirectives(out *strings.Builder, directives []*schema.AppliedDirective) {
+	for _, directive := range directives {
+		out.WriteString(" @" + directive.Name)
+
+		if len(directive.Arguments) == 0 {
+			continue
+		}
+
+		arguments := make([]string, 0, len(directive.Arguments))
+
+		for _, argument := range directive.Arguments {
+			if argument.Name == "" {
+				arguments = append(arguments, argument.Value)
+			} else {
+				arguments = append(arguments, argument.Name+": "+argument.Value)
+			}
+		}
+
+		out.WriteString("(" + strings.Join(arguments, ", ") + ")")
+	}
+}
+
+// printInputValue prints an argument or an input field, without its description.
+func printInputValue(out *strings.Builder, value *schema.InputValue) {
+	out.WriteString(fmt.Sprintf("%s: %s", value.Name, value.Type))
+
+	if value.DefaultValue != nil {
+		out.WriteString(" = " + *value.DefaultValue)
+	}
+
+	printDirectives(out, value.Directives)
+}
+
+// printArguments prints the arguments of a field or a directive between parentheses.
+func printArguments(out *strings.Builder, arguments []*schema.InputValue) {
+	if len(arguments) == 0 {
+		return
+	}
+
+	out.WriteString("(")
+
+	for i, argument := range arguments {
+		if i > 0 {
+			out.WriteString(", ")
+		}
+
+		if argument.Description != "" {
+			out.WriteString(fmt.Sprintf("%q ", argument.Description))
+		}
+
+		printInputValue(out, argument)
+	}
+
+	out.WriteString(")")
+}
+
+func printFields(out *strings.Builder, fields []*schema.Field) {
+	out.WriteString(" {\n")
+
+	for _, field := range fields {
+		printDescription(out, field.Description, indent)
+		out.WriteString(indent + field.Name)
+		printArguments(out, field.Arguments)
+		out.WriteString(fmt.Sprintf(": %s", field.Type))
+		printDirectives(out, field.Directives)
+		out.WriteString("\n")
+	}
+
+	out.WriteString("}\n")
+}
+
+func printInterfaces(out *strings.Builder, interfaces []string) {
+	if len(interfaces) > 0 {
+		out.WriteString(" implements " + strings.Join(interfaces, " & "))
+	}
+}
+
+func printType(out *strings.Builder, t schema.Type) {
+	printDescription(out, t.TypeDescription(), "")
+
+	switch t := t.(type) {
+	case *schema.ObjectType:
+		out.WriteString("type " + t.Name)
+		printInterfaces(out, t.Interfaces)
+		printDirectives(out, t.Directives)
+		printFields(out, t.Fields)
+	case *schema.InterfaceType:
+		out.WriteString("interface " + t.Name)
+		printInterfaces(out, t.Interfaces)
+		printDirectives(out, t.Directives)
+		printFields(out, t.Fields)
+	case *schema.UnionType:
+		out.WriteString("union " + t.Name)
+		printDirectives(out, t.Directives)
+		out.WriteString(" = " + strings.Join(t.Members, " | ") + "\n")
+	case *schema.EnumType:
+		out.WriteString("enum " + t.Name)
+		printDirectives(out, t.Directives)
+		out.WriteString(" {\n")
+
+		for _, value := range t.Values {
+			printDescription(out, value.Description, indent)
+			out.WriteString(indent + value.Name)
+			printDirectives(out, value.Directives)
+			out.WriteString("\n")
+		}
+
+		out.WriteString("}\n")
+	case *schema.ScalarType:
+		out.WriteString("scalar " + t.Name)
+		printDirectives(out, t.Directives)
+		out.WriteString("\n")
+	case *schema.InputObjectType:
+		out.WriteString("input " + t.Name)
+		printDirectives(out, t.Directives)
+		out.WriteString(" {\n")
+
+		for _, field := range t.Fields {
+			printDescription(out, field.Description, indent)
+			out.WriteString(indent)
+			printInputValue(out, field)
+			out.WriteString("\n")
+		}
+
+		out.WriteString("}\n")
+	}
+}
+
+func printDirective(out *strings.Builder, directive *schema.Directive) {
+	printDescription(out, directive.Description, "")
+	out.WriteString("directive @" + directive.Name)
+	printArguments(out, directive.Arguments)
+
+	if directive.Repeatable {
+		out.WriteString(" repeatable")
+	}
+
+	out.WriteString(" on " + strings.Join(directive.Locations, " | ") + "\n")
+}
+
+// Print prints a schema in SDL, the schema extension first, then directive
+// definitions and every type, separated by a blank line.
+func Print(s *schema.Schema) string {
+	definitions := make([]string, 0, len(s.Directives)+len(s.Types)+1)
+
+	if len(s.ExtensionDirectives) > 0 {
+		out := &strings.Builder{}
+		out.WriteString("extend schema")
+		printDirectives(out, s.ExtensionDirectives)
+		out.WriteString("\n")
+		definitions = append(definitions, out.String())
+	}
+
+	for _, directive := range s.Directives {
+		out := &strings.Builder{}
+		printDirective(out, directive)
+		definitions = append(definitions, out.String())
+	}
+
+	for _, t := range s.Types {
+		out := &strings.Builder{}
+		printType(out, t)
+		definitions = append(definitions, out.String())
+	}
+
+	return strings.Join(definitions, "\n")
+}
@@ -0,0 +1,315 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/warpspeed-cloud/graphql-schema-generator/internal/builder"
+	schemadiff "github.com/warpspeed-cloud/graphql-schema-generator/internal/schema-diff"
+	typeparser "github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+// Format is the format a config file is written in.
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+type Naming struct {
+	// Fields is the naming convention of field names, one of camelCase or snake_case.
+	// By default the name in the json tag, or the Go field name, is used as is.
+	Fields typeparser.FieldNaming `yaml:"fields" json:"fields"`
+	// Generics is the text/template the instantiations of generic types are named with,
+	// i.e. {{.Type}}_{{join .Args "_"}} names Page[User] Page_User. By default it is UserPage.
+	Generics typeparser.GenericNaming `yaml:"generics" json:"generics"`
+	// Aliases are the names of structs in the schema, keyed by the Go type qualified
+	// by its package path, i.e. github.com/x/billing.Account: BillingAccount.
+	Aliases map[string]string `yaml:"aliases" json:"aliases"`
+	// Collisions is what happens when two Go types would have the same name in the
+	// schema, error (the default) or prefix, which prefixes the name of every one of
+	// them with the name of its package.
+	Collisions typeparser.NameCollision `yaml:"collisions" json:"collisions"`
+}
+
+type Maps struct {
+	// Strategy is how maps are output, either as a list of key value pairs
+	// (entries, the default) or as a scalar.
+	Strategy builder.MapStrategy `yaml:"strategy" json:"strategy"`
+	// Scalar is the scalar maps are output as with the scalar strategy, JSON by default.
+	Scalar string `yaml:"scalar" json:"scalar"`
+}
+
+type TypeScript struct {
+	// Output is the file the command line tool writes the Go types to as TypeScript declarations.
+	Output string `yaml:"output" json:"output"`
+	// Enums is how enums are output, as a union of their values (union, the default)
+	// or as a TypeScript enum.
+	Enums typeparser.TypeScriptEnumStyle `yaml:"enums" json:"enums"`
+}
+
+// Config configures the generator, it is shared by library and command line users so
+// that a schema is generated the same way whichever is used. Paths are relative to the
+// directory the generator runs in.
+type Config struct {
+	// Packages are the package patterns the command line tool reads types from.
+	Packages []string `yaml:"packages" json:"packages"`
+	// Roots are the structs to add to the schema along with the types they reference.
+	Roots []string `yaml:"roots" json:"roots"`
+	// Output is the file the command line tool writes the schema to.
+	Output string `yaml:"output" json:"output"`
+	// Introspection is a file the command line tool also writes the schema to,
+	// as the JSON result of the introspection query.
+	Introspection string `yaml:"introspection" json:"introspection"`
+	// JSONSchema is a file the command line tool writes the Go types to as a JSON Schema document.
+	JSONSchema string `yaml:"jsonSchema" json:"jsonSchema"`
+
+	// DocComments uses Go doc comments as descriptions, it is on by default.
+	DocComments bool `yaml:"docComments" json:"docComments"`
+	// Scalars maps Go types, i.e. time.Time or int64, to a GraphQL scalar.
+	Scalars map[string]string `yaml:"scalars" json:"scalars"`
+	// Exclude are types left out of the schema, along with the fields of their type.
+	Exclude []string `yaml:"exclude" json:"exclude"`
+	// IDFields are patterns of the fields output as the ID scalar, i.e. ID or *ID. Fields
+	// named ID or id are by default, an empty list, [], outputs them as their Go type.
+	IDFields []string `yaml:"idFields" json:"idFields"`
+	Naming   Naming   `yaml:"naming" json:"naming"`
+	Maps     Maps     `yaml:"maps" json:"maps"`
+	// TypeScript configures the TypeScript declarations of the Go types.
+	TypeScript TypeScript `yaml:"typescript" json:"typescript"`
+	// Federation outputs the schema as an Apollo Federation v2 subgraph.
+	Federation bool `yaml:"federation" json:"federation"`
+	// Order is the order types are output in: discovery (the default), alphabetical,
+	// or dependency, where the types no other type refers to come first.
+	Order schema.TypeOrder `yaml:"order" json:"order"`
+	// Directives are the definitions, in SDL, of the directives applied by decorators, i.e.
+	// directive @auth(role: String!) on FIELD_DEFINITION. Every decorator is checked
+	// against them, so one that applies a directive that isn't built in must be defined here.
+	Directives string `yaml:"directives" json:"directives"`
+}
+
+// ValidationError lists every problem found in a config.
+type ValidationError struct {
+	Path     string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid config '%s':\n  %s", e.Path, strings.Join(e.Problems, "\n  "))
+}
+
+// Default returns the config used when there is no config file.
+func Default() *Config {
+	return &Config{
+		DocComments: true,
+		Maps: Maps{
+			Strategy: builder.MapStrategyEntries,
+			Scalar:   builder.DefaultMapScalar,
+		},
+		TypeScript: TypeScript{
+			Enums: typeparser.TypeScriptEnumUnion,
+		},
+	}
+}
+
+// Load reads a config file, the format is picked from its extension,
+// .yaml, .yml or .json, and the config is validated.
+func Load(path string) (*Config, error) {
+	var format Format
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = FormatYAML
+	case ".json":
+		format = FormatJSON
+	default:
+		return nil, fmt.Errorf("config '%s' must be a .yaml, .yml or .json file", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config: %w", err)
+	}
+
+	return Parse(path, data, format)
+}
+
+// Parse decodes and validates a config, path is only used in error messages.
+// Settings missing from the config keep their default value.
+func Parse(path string, data []byte, format Format) (*Config, error) {
+	config := Default()
+
+	var err error
+
+	switch format {
+	case FormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(config)
+
+		// An empty file is a valid config holding only defaults.
+		if err != nil && strings.TrimSpace(string(data)) == "" {
+			err = nil
+		}
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(config)
+	default:
+		err = fmt.Errorf("unknown format '%s'", format)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse config '%s': %w", path, err)
+	}
+
+	if problems := config.validate(); len(problems) > 0 {
+		return nil, &ValidationError{Path: path, Problems: problems}
+	}
+
+	return config, nil
+}
+
+// validate returns a message for every problem in the config.
+func (c *Config) validate() []string {
+	var problems []string
+
+	goTypes := make([]string, 0, len(c.Scalars))
+	for goType := range c.Scalars {
+		goTypes = append(goTypes, goType)
+	}
+
+	sort.Strings(goTypes)
+
+	for _, goType := range goTypes {
+		if strings.TrimSpace(goType) == "" {
+			problems = append(problems, "scalars: a Go type can't be empty")
+		}
+
+		if scalar := c.Scalars[goType]; !schema.IsValidName(scalar) {
+			problems = append(problems, fmt.Sprintf("scalars: '%s' is mapped to '%s' which is not a valid GraphQL name", goType, scalar))
+		}
+	}
+
+	for i, excluded := range c.Exclude {
+		if strings.TrimSpace(excluded) == "" {
+			problems = append(problems, fmt.Sprintf("exclude[%d]: a type name can't be empty", i))
+		}
+	}
+
+	for i, pattern := range c.IDFields {
+		if _, err := path.Match(pattern, ""); err != nil || strings.TrimSpace(pattern) == "" {
+			problems = append(problems, fmt.Sprintf("idFields[%d]: '%s' is not a valid pattern", i, pattern))
+		}
+	}
+
+	for i, root := range c.Roots {
+		if strings.TrimSpace(root) == "" {
+			problems = append(problems, fmt.Sprintf("roots[%d]: a type name can't be empty", i))
+		}
+	}
+
+	validNaming := false
+
+	for _, naming := range typeparser.FieldNamings {
+		validNaming = validNaming || c.Naming.Fields == naming
+	}
+
+	if !validNaming {
+		problems = append(problems, fmt.Sprintf("naming.fields: '%s' is not one of camelCase or snake_case", c.Naming.Fields))
+	}
+
+	if err := c.Naming.Generics.Validate(); err != nil {
+		problems = append(problems, "naming.generics: "+err.Error())
+	}
+
+	goTypes = make([]string, 0, len(c.Naming.Aliases))
+	for goType := range c.Naming.Aliases {
+		goTypes = append(goTypes, goType)
+	}
+
+	sort.Strings(goTypes)
+
+	for _, goType := range goTypes {
+		if !strings.Contains(goType, ".") {
+			problems = append(problems, fmt.Sprintf("naming.aliases: '%s' is not qualified by its package path, i.e. github.com/x/models.%s", goType, goType))
+		}
+
+		if alias := c.Naming.Aliases[goType]; !schema.IsValidName(alias) {
+			problems = append(problems, fmt.Sprintf("naming.aliases: '%s' is aliased to '%s' which is not a valid GraphQL name", goType, alias))
+		}
+	}
+
+	validCollisions := false
+
+	for _, collisions := range typeparser.NameCollisions {
+		validCollisions = validCollisions || c.Naming.Collisions == collisions
+	}
+
+	if !validCollisions {
+		problems = append(problems, fmt.Sprintf("naming.collisions: '%s' is not one of error or prefix", c.Naming.Collisions))
+	}
+
+	validOrder := false
+
+	for _, order := range schema.TypeOrders {
+		validOrder = validOrder || c.Order == order
+	}
+
+	if !validOrder {
+		problems = append(problems, fmt.Sprintf("order: '%s' is not one of discovery, alphabetical or dependency", c.Order))
+	}
+
+	if c.Maps.Strategy != builder.MapStrategyEntries && c.Maps.Strategy != builder.MapStrategyScalar {
+		problems = append(problems, fmt.Sprintf("maps.strategy: '%s' is not one of entries or scalar", c.Maps.Strategy))
+	}
+
+	if !schema.IsValidName(c.Maps.Scalar) {
+		problems = append(problems, fmt.Sprintf("maps.scalar: '%s' is not a valid GraphQL name", c.Maps.Scalar))
+	}
+
+	validEnums := false
+
+	for _, style := range typeparser.TypeScriptEnumStyles {
+		validEnums = validEnums || c.TypeScript.Enums == style
+	}
+
+	if !validEnums {
+		problems = append(problems, fmt.Sprintf("typescript.enums: '%s' is not one of union or enum", c.TypeScript.Enums))
+	}
+
+	if _, err := c.DirectiveDefinitions(); err != nil {
+		problems = append(problems, "directives: "+err.Error())
+	}
+
+	return problems
+}
+
+// DirectiveDefinitions returns the directive definitions of the config, an
+// error when they aren't valid SDL or when it holds something else.
+func (c *Config) DirectiveDefinitions() ([]*schema.Directive, error) {
+	if strings.TrimSpace(c.Directives) == "" {
+		return nil, nil
+	}
+
+	definitions, err := schemadiff.Parse(c.Directives)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(definitions.Types) > 0 || len(definitions.ExtensionDirectives) > 0 {
+		return nil, fmt.Errorf("only directive definitions are allowed")
+	}
+
+	return definitions.Directives, nil
+}
@@ -0,0 +1,156 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warpspeed-cloud/graphql-schema-generator/internal/builder"
+	"github.com/warpspeed-cloud/graphql-schema-generator/internal/config"
+	typeparser "github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+func TestLoad(t *testing.T) {
+	expected := &config.Config{
+		Packages:      []string{"./models/..."},
+		Roots:         []string{"User"},
+		Output:        "schema.graphql",
+		Introspection: "schema.json",
+		JSONSchema:    "types.schema.json",
+		DocComments:   false,
+		Scalars:       map[string]string{"time.Time": "DateTime", "int64": "String"},
+		Exclude:       []string{"Secret"},
+		IDFields:      []string{"ID", "*ID"},
+		Naming: config.Naming{
+			Fields:     typeparser.FieldNamingCamelCase,
+			Generics:   `{{.Type}}_{{join .Args "_"}}`,
+			Aliases:    map[string]string{"github.com/x/billing.Account": "BillingAccount"},
+			Collisions: typeparser.NameCollisionPrefix,
+		},
+		Maps:       config.Maps{Strategy: builder.MapStrategyScalar, Scalar: "Map"},
+		Federation: true,
+		Order:      schema.TypeOrderAlphabetical,
+		TypeScript: config.TypeScript{Output: "types.ts", Enums: typeparser.TypeScriptEnumEnum},
+		Directives: "directive @auth(role: String!) on FIELD_DEFINITION\n",
+	}
+
+	for _, path := range []string{"testdata/config.yaml", "testdata/config.json"} {
+		path := path
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := config.Load(path)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+
+	_, err := config.Load("testdata/config.toml")
+	assert.EqualError(t, err, "config 'testdata/config.toml' must be a .yaml, .yml or .json file")
+
+	_, err = config.Load("testdata/missing.yaml")
+	assert.Error(t, err)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		format   config.Format
+		expected *config.Config
+		err      string
+	}{
+		{
+			name:     "Empty YAML gives the defaults",
+			data:     "",
+			format:   config.FormatYAML,
+			expected: config.Default(),
+		},
+		{
+			name:   "Settings left out keep their default",
+			data:   "roots: [User]",
+			format: config.FormatYAML,
+			expected: &config.Config{
+				Roots:       []string{"User"},
+				DocComments: true,
+				Maps:        config.Maps{Strategy: builder.MapStrategyEntries, Scalar: builder.DefaultMapScalar},
+				TypeScript:  config.TypeScript{Enums: typeparser.TypeScriptEnumUnion},
+			},
+		},
+		{
+			name:   "Unknown YAML key",
+			data:   "root: [User]",
+			format: config.FormatYAML,
+			err:    "could not parse config 'config': yaml: unmarshal errors:\n  line 1: field root not found in type config.Config",
+		},
+		{
+			name:   "Unknown JSON key",
+			data:   `{"root": ["User"]}`,
+			format: config.FormatJSON,
+			err:    `could not parse config 'config': json: unknown field "root"`,
+		},
+		{
+			name: "Every problem is reported",
+			data: `
+roots: [""]
+scalars:
+  time.Time: Date Time
+exclude: [" "]
+idFields: ["[ID", ""]
+naming:
+  fields: PascalCase
+  generics: '{{.Type}}[{{join .Args ","}}]'
+  aliases:
+    Account: Billing Account
+  collisions: rename
+order: random
+maps:
+  strategy: list
+  scalar: "1Map"
+typescript:
+  enums: const
+directives: "type User { id: ID }"
+`,
+			format: config.FormatYAML,
+			err: `invalid config 'config':
+  scalars: 'time.Time' is mapped to 'Date Time' which is not a valid GraphQL name
+  exclude[0]: a type name can't be empty
+  idFields[0]: '[ID' is not a valid pattern
+  idFields[1]: '' is not a valid pattern
+  roots[0]: a type name can't be empty
+  naming.fields: 'PascalCase' is not one of camelCase or snake_case
+  naming.generics: the generic naming template names Page[User] 'Page[User]', which is not a valid GraphQL name
+  naming.aliases: 'Account' is not qualified by its package path, i.e. github.com/x/models.Account
+  naming.aliases: 'Account' is aliased to 'Billing Account' which is not a valid GraphQL name
+  naming.collisions: 'rename' is not one of error or prefix
+  order: 'random' is not one of discovery, alphabetical or dependency
+  maps.strategy: 'list' is not one of entries or scalar
+  maps.scalar: '1Map' is not a valid GraphQL name
+  typescript.enums: 'const' is not one of union or enum
+  directives: only directive definitions are allowed`,
+		},
+		{
+			name:   "Directives that aren't valid SDL",
+			data:   `directives: "directive @auth(role: String!) FIELD_DEFINITION"`,
+			format: config.FormatYAML,
+			err:    "invalid config 'config':\n  directives: syntax error at 1:32: expected 'on', found 'FIELD_DEFINITION'",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := config.Parse("config", []byte(test.data), test.format)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package docparser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"strings"
+)
+
+// Suffix given to the package path of types declared in an external test package.
+const testPackageSuffix = "_test"
+
+// deprecatedPrefix starts the paragraph of a doc comment that deprecates what it documents.
+const deprecatedPrefix = "Deprecated:"
+
+// Type holds the documentation of a single named type.
+type Type struct {
+	Doc    string
+	Fields map[string]string
+}
+
+// Package holds the documentation of every named type declared in a Go package.
+type Package struct {
+	Types map[string]*Type
+}
+
+// TypeDoc returns the cleaned doc comment of a type, or an empty string.
+func (p *Package) TypeDoc(typeName string) string {
+	if p == nil {
+		return ""
+	}
+
+	if t, ok := p.Types[typeName]; ok {
+		return t.Doc
+	}
+
+	return ""
+}
+
+// FieldDoc returns the cleaned doc comment of a struct field, or an empty string.
+func (p *Package) FieldDoc(typeName string, fieldName string) string {
+	if p == nil {
+		return ""
+	}
+
+	if t, ok := p.Types[typeName]; ok {
+		return t.Fields[fieldName]
+	}
+
+	return ""
+}
+
+// Load finds the source of the package with the given import path and parses
+// its doc comments. Types declared in an external test package have a path ending
+// in "_test", in which case only the test files of the package directory are read.
+func Load(importPath string) (*Package, error) {
+	testOnly := strings.HasSuffix(importPath, testPackageSuffix)
+
+	pkg, err := build.Import(strings.TrimSuffix(importPath, testPackageSuffix), ".", build.FindOnly)
+	if err != nil {
+		return nil, fmt.Errorf("could not find the source of package '%s': %w", importPath, err)
+	}
+
+	return loadDir(pkg.Dir, testOnly)
+}
+
+// LoadDir parses the doc comments of the non-test Go files in a directory.
+func LoadDir(dir string) (*Package, error) {
+	return loadDir(dir, false)
+}
+
+func loadDir(dir string, testOnly bool) (*Package, error) {
+	filter := func(info fs.FileInfo) bool {
+		return strings.HasSuffix(info.Name(), "_test.go") == testOnly
+	}
+
+	packages, err := parser.ParseDir(token.NewFileSet(), dir, filter, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the source in '%s': %w", dir, err)
+	}
+
+	result := &Package{Types: map[string]*Type{}}
+
+	for name, pkg := range packages {
+		// An internal test file shares the package name of the package it tests,
+		// so when reading test files only the external test package is of interest.
+		if testOnly && !strings.HasSuffix(name, testPackageSuffix) {
+			continue
+		}
+
+		for _, file := range pkg.Files {
+			collectFile(result, file)
+		}
+	}
+
+	return result, nil
+}
+
+// collectFile adds the documentation of every type declared in a file.
+func collectFile(result *Package, file *ast.File) {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			// A lone type declaration carries its comment on the declaration
+			// rather than on the spec, i.e. "// Doc\ntype X struct{}".
+			doc := typeSpec.Doc
+			if doc == nil && !genDecl.Lparen.IsValid() {
+				doc = genDecl.Doc
+			}
+
+			t := &Type{
+				Doc:    Clean(doc.Text()),
+				Fields: map[string]string{},
+			}
+
+			if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+				for _, field := range structType.Fields.List {
+					fieldDoc := Clean(field.Doc.Text())
+
+					for _, name := range field.Names {
+						t.Fields[name.Name] = fieldDoc
+					}
+				}
+			}
+
+			result.Types[typeSpec.Name.Name] = t
+		}
+	}
+}
+
+// Clean turns the text of a comment (with its markers already stripped) into a
+// description. Lines of a paragraph are joined with a space and paragraphs are
+// separated by a blank line. Indented lines, e.g. code examples, keep their line breaks.
+func Clean(text string) string {
+	var (
+		paragraphs []string
+		current    []string
+	)
+
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, strings.Join(current, ""))
+			current = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimRight(line, " \t")
+
+		switch {
+		case trimmed == "":
+			flush()
+		case len(current) == 0:
+			current = append(current, trimmed)
+		case strings.HasPrefix(trimmed, " ") || strings.HasPrefix(trimmed, "\t"):
+			current = append(current, "\n", trimmed)
+		default:
+			current = append(current, " ", trimmed)
+		}
+	}
+
+	flush()
+
+	// Block comments keep the space following "/*".
+	return strings.TrimSpace(strings.Join(paragraphs, "\n\n"))
+}
+
+// SplitDeprecation splits the Deprecated: paragraph, as written by the Go convention, from a
+// cleaned doc comment. It returns the rest of the comment and the reason given in the paragraph,
+// or nil when there is no such paragraph.
+func SplitDeprecation(doc string) (string, *string) {
+	paragraphs := strings.Split(doc, "\n\n")
+
+	for i, paragraph := range paragraphs {
+		if !strings.HasPrefix(paragraph, deprecatedPrefix) {
+			continue
+		}
+
+		reason := strings.TrimSpace(strings.TrimPrefix(paragraph, deprecatedPrefix))
+		rest := append(append([]string{}, paragraphs[:i]...), paragraphs[i+1:]...)
+
+		return strings.Join(rest, "\n\n"), &reason
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,131 @@
+package docparser_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	docparser "github.com/warpspeed-cloud/graphql-schema-generator/internal/doc-parser"
+	"github.com/warpspeed-cloud/graphql-schema-generator/internal/ptr"
+)
+
+func TestLoadDir(t *testing.T) {
+	docs, err := docparser.LoadDir("testdata/shop")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		actual   string
+		expected string
+	}{
+		{
+			name:     "Multi paragraph type doc",
+			actual:   docs.TypeDoc("Product"),
+			expected: "Product is something we sell.\n\nProducts are listed in the catalogue:\n\n\tGET /products",
+		},
+		{
+			name:     "Field doc",
+			actual:   docs.FieldDoc("Product", "ID"),
+			expected: "The ID of the product.",
+		},
+		{
+			name:     "Wrapped field doc",
+			actual:   docs.FieldDoc("Product", "Name"),
+			expected: "The name of the product as shown to customers.",
+		},
+		{
+			name:     "Block comment",
+			actual:   docs.FieldDoc("Product", "Price"),
+			expected: "The price in cents.",
+		},
+		{
+			name:     "Trailing comment",
+			actual:   docs.FieldDoc("Product", "Undocumented"),
+			expected: "",
+		},
+		{
+			name:     "Shared field doc",
+			actual:   docs.FieldDoc("Product", "Height"),
+			expected: "Both fields share this comment.",
+		},
+		{
+			name:     "Grouped type declaration",
+			actual:   docs.TypeDoc("Category"),
+			expected: "Category groups products.",
+		},
+		{
+			name:     "Directive comments are dropped",
+			actual:   docs.TypeDoc("Tag"),
+			expected: "",
+		},
+		{
+			name:     "Unknown type",
+			actual:   docs.TypeDoc("Order"),
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.expected, test.actual)
+		})
+	}
+}
+
+func TestSplitDeprecation(t *testing.T) {
+	docs, err := docparser.LoadDir("testdata/shop")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		doc         string
+		description string
+		reason      *string
+	}{
+		{
+			name:        "Deprecated paragraph",
+			doc:         docs.FieldDoc("Product", "Weight"),
+			description: "The weight in grams.",
+			reason:      ptr.Of("Use Width and Height instead."),
+		},
+		{
+			name:        "Not deprecated",
+			doc:         docs.FieldDoc("Product", "ID"),
+			description: "The ID of the product.",
+		},
+		{
+			name:   "Deprecated without a reason",
+			doc:    "Deprecated:",
+			reason: ptr.Of(""),
+		},
+		{
+			name:        "Deprecated in the middle of a sentence",
+			doc:         "Not Deprecated: at all.",
+			description: "Not Deprecated: at all.",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			description, reason := docparser.SplitDeprecation(test.doc)
+			assert.Equal(t, test.description, description)
+			assert.Equal(t, test.reason, reason)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	docs, err := docparser.Load("github.com/warpspeed-cloud/graphql-schema-generator/internal/doc-parser_test")
+	assert.NoError(t, err)
+	assert.Equal(t, "Fixture is only declared in the test package.", docs.TypeDoc("Fixture"))
+
+	_, err = docparser.Load("github.com/warpspeed-cloud/graphql-schema-generator/internal/does-not-exist")
+	assert.Error(t, err)
+}
+
+// Fixture is only declared in the test package.
+type Fixture struct{}
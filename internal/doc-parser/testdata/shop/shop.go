@@ -0,0 +1,34 @@
+package shop
+
+// Product is something we sell.
+//
+// Products are listed in the catalogue:
+//
+//	GET /products
+type Product struct {
+	// The ID of the product.
+	ID string
+	// The name of the product as shown
+	// to customers.
+	Name string
+	/* The price in cents. */
+	Price        int
+	Undocumented bool // Trailing comments are not doc comments.
+	// Both fields share this comment.
+	Width, Height int
+	// The weight in grams.
+	//
+	// Deprecated: Use Width and
+	// Height instead.
+	Weight int
+}
+
+type (
+	// Category groups products.
+	Category struct {
+		Name string
+	}
+
+	//nolint:revive
+	Tag string
+)
@@ -1,12 +1,92 @@
 package tagparser
 
 import (
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
 )
 
-// Tag represents a GraphQL tag.
+// Nullability overrides whether a field can be null, which is otherwise
+// given by whether its Go type is a pointer.
+type Nullability string
+
+const (
+	// NullabilityNullable makes a field nullable, with the nullable flag.
+	NullabilityNullable Nullability = "nullable"
+	// NullabilityNonNull makes a field non-null, with the nonNull flag.
+	NullabilityNonNull Nullability = "nonNull"
+)
+
+// Tag is a parsed graphql tag, i.e. description=The name,connection.
 type Tag struct {
-	Options map[string]string
+	// Description is given with the description option.
+	Description *string
+	// Name replaces the name of the field, given with the name option.
+	Name string
+	// Type replaces the type the Go type of the field is output as, i.e. ID in type=ID.
+	Type string
+	// Deprecated is the reason given with the deprecated option, i.e. Use name in
+	// deprecated="Use name". It is empty but not nil when deprecated is a flag.
+	Deprecated *string
+	// Directives are the decorators listed in the decorators option.
+	Directives []Directive
+	// Args are the arguments of the field, i.e. args=[first: Int = 10, after: String].
+	Args []Argument
+	// Nullability is set by the nullable and nonNull flags.
+	Nullability Nullability
+	// Exclude leaves the field out of the schema, with the exclude flag or a tag of -.
+	Exclude bool
+
+	// Connection outputs a slice as a Relay connection.
+	Connection bool
+	// Node makes the field the ID of a Relay node.
+	Node bool
+
+	// Key makes the field part of the federation key of its type.
+	Key bool
+	// Keys are the federation keys given on the blank field of a type, i.e. key=[id, sku upc].
+	Keys []string
+	// Shareable and External are the federation flags of the same name.
+	Shareable bool
+	External  bool
+	// Requires and Provides are the field sets of the federation options of the same name.
+	Requires string
+	Provides string
+
+	// Warnings are the problems that don't prevent the tag from being used, i.e. unknown options.
+	Warnings []string
+}
+
+// Directive is a single decorator from the decorators option of a tag,
+// i.e. +requireAuthRole(role: "admin").
+type Directive struct {
+	Name string
+	// Arguments are the raw arguments between the parentheses, if any.
+	Arguments string
+}
+
+// Argument is an argument of a field given with the args option, i.e. first: Int = 10.
+type Argument struct {
+	Name string
+	// Type is the type as it is written in SDL, i.e. [String!]!.
+	Type string
+	// DefaultValue is the default value as it is written in SDL, if any.
+	DefaultValue *string
+}
+
+// knownOptions are the options of a tag, an option that isn't one of them is reported with a warning.
+var knownOptions = []string{
+	"description", "name", "type", "deprecated", "decorators", "args", "nullable", "nonNull", "exclude",
+	"connection", "node", "key", "shareable", "external", "requires", "provides",
+}
+
+// valueOptions are the options that can't be flags.
+var valueOptions = map[string]bool{
+	"description": true, "name": true, "type": true, "decorators": true, "args": true, "requires": true, "provides": true,
 }
 
 // GetTagFromField Get the tag from a Field.
@@ -20,84 +100,406 @@ func GetTagFromField(field reflect.StructField) *Tag {
 	return ParseTag(tag, field.Name)
 }
 
-// ParseTag Parse a tag.
-func ParseTag(tag string, fieldName string) *Tag { //nolint: cyclop
-	// Loop over each character and assemble a map of tags,
-	// we do this because decorators and comments can contain commas,
-	// and we don't want to split on those
-	tagOptions := make(map[string]string)
+// ParseTag parses a tag, see Parse. Errors are ignored and as much of the tag
+// as could be parsed is returned.
+func ParseTag(tag string, fieldName string) *Tag {
+	parsed, _ := Parse(tag, fieldName)
 
-	var (
-		currentKey   string
-		currentValue string
-		inQuotes     bool
-		escaped      bool
-		inArray      bool
-		prevChar     rune
-	)
+	return parsed
+}
 
-	for _, char := range tag {
-		if string(char) == " " && string(prevChar) == "," {
-			prevChar = char
-			// Skip if we have a space after a comma.
-			continue
+// Parse parses a tag, i.e. description=The name,connection. It returns nil for an empty
+// tag, and a *SyntaxError for the first error found in the tag along with as much of the
+// tag as could be parsed. fieldName is only used in errors and warnings.
+func Parse(tag string, fieldName string) (*Tag, error) {
+	if strings.TrimSpace(tag) == "" {
+		return nil, nil
+	}
+
+	if strings.TrimSpace(tag) == "-" {
+		return &Tag{Exclude: true}, nil
+	}
+
+	l := &lexer{source: []rune(tag), field: fieldName}
+	options := l.options()
+
+	var parsed *Tag
+	if len(options) > 0 {
+		parsed = &Tag{}
+
+		for _, o := range options {
+			parsed.set(l, o)
+		}
+	}
+
+	if l.err != nil {
+		return parsed, l.err
+	}
+
+	return parsed, nil
+}
+
+// set sets an option of the tag, recording an error in the lexer when its value isn't valid.
+func (t *Tag) set(l *lexer, o option) {
+	if o.flag && valueOptions[o.key] {
+		l.errorAt(o.keyOffset, "option '%s' needs a value, i.e. %s=value", o.key, o.key)
+
+		return
+	}
+
+	switch o.key {
+	case "description":
+		t.Description = ptrOf(l.stringValue(o))
+	case "name":
+		if !schema.IsValidName(o.value) {
+			l.errorAt(o.valueOffset, "name '%s' is not a valid GraphQL name", o.value)
 		}
 
-		if char == '[' {
-			inArray = true
+		t.Name = o.value
+	case "type":
+		if !schema.IsValidName(o.value) {
+			l.errorAt(o.valueOffset, "type '%s' is not a valid GraphQL name", o.value)
 		}
 
-		if char == ']' {
-			inArray = false
+		t.Type = o.value
+	case "deprecated":
+		reason := ""
+		if !o.flag {
+			reason = l.stringValue(o)
 		}
 
-		if char == '\\' {
-			escaped = true
+		t.Deprecated = &reason
+	case "decorators":
+		directives, offset, err := splitDirectives(o.raw)
+		if err != nil {
+			l.errorAt(o.valueOffset+offset, "%s", err)
+		}
+
+		t.Directives = directives
+	case "args":
+		t.Args = l.arguments(o)
+	case "nullable", "nonNull":
+		if l.flag(o) {
+			if t.Nullability != "" {
+				l.errorAt(o.keyOffset, "a field can't be both nullable and nonNull")
+			}
+
+			t.Nullability = Nullability(o.key)
+		}
+	case "exclude":
+		t.Exclude = l.flag(o)
+	case "connection":
+		t.Connection = l.flag(o)
+	case "node":
+		t.Node = l.flag(o)
+	case "key":
+		if _, err := strconv.ParseBool(o.value); err == nil {
+			t.Key = l.flag(o)
+		} else {
+			t.Keys = fieldSets(o.value)
+		}
+	case "shareable":
+		t.Shareable = l.flag(o)
+	case "external":
+		t.External = l.flag(o)
+	case "requires":
+		t.Requires = o.value
+	case "provides":
+		t.Provides = o.value
+	default:
+		warning := fmt.Sprintf("unknown option '%s' in the graphql tag on '%s' at column %d", o.key, l.field, o.keyOffset+1)
+
+		if suggestion := closestOption(o.key); suggestion != "" {
+			warning += fmt.Sprintf(", did you mean '%s'?", suggestion)
+		}
+
+		t.Warnings = append(t.Warnings, warning)
+	}
+}
+
+// flag returns the value of a flag, which can be given as true or false too, i.e. connection=false.
+func (l *lexer) flag(o option) bool {
+	value, err := strconv.ParseBool(o.value)
+	if err != nil {
+		l.errorAt(o.valueOffset, "option '%s' is a flag, its value can only be true or false", o.key)
+	}
+
+	return value
+}
+
+// stringValue returns the value of an option, unquoted when it is quoted.
+func (l *lexer) stringValue(o option) string {
+	if !strings.HasPrefix(o.value, "\"") {
+		return o.value
+	}
+
+	unquoted, err := strconv.Unquote(o.value)
+	if err != nil {
+		l.errorAt(o.valueOffset, "%s is not a valid string", o.value)
+
+		return o.value
+	}
+
+	return unquoted
+}
+
+// arguments parses the args option, a list of arguments or a single one.
+func (l *lexer) arguments(o option) []Argument {
+	list := o.value
+	if strings.HasPrefix(list, "[") && strings.HasSuffix(list, "]") {
+		list = list[1 : len(list)-1]
+	}
+
+	var arguments []Argument
+
+	for _, raw := range splitList(list) {
+		name, rest, found := strings.Cut(raw, ":")
+		argument := Argument{Name: strings.TrimSpace(name)}
+
+		typeRef, defaultValue, hasDefault := strings.Cut(rest, "=")
+		argument.Type = strings.TrimSpace(typeRef)
+
+		if hasDefault {
+			value := strings.TrimSpace(defaultValue)
+			argument.DefaultValue = &value
+		}
+
+		if !found || !schema.IsValidName(argument.Name) || !isType(argument.Type) || (hasDefault && *argument.DefaultValue == "") {
+			l.errorAt(o.valueOffset, "argument '%s' is not of the form name: Type or name: Type = default", raw)
 
-			prevChar = char
 			continue
 		}
 
-		if char == '"' && !escaped {
+		arguments = append(arguments, argument)
+	}
+
+	return arguments
+}
+
+// splitList splits a list at the commas that aren't inside a string or brackets.
+func splitList(list string) []string {
+	var (
+		items    []string
+		current  strings.Builder
+		depth    int
+		inQuotes bool
+		escaped  bool
+	)
+
+	add := func() {
+		if item := strings.TrimSpace(current.String()); item != "" {
+			items = append(items, item)
+		}
+
+		current.Reset()
+	}
+
+	for _, char := range list {
+		switch {
+		case escaped:
+			escaped = false
+		case inQuotes && char == '\\':
+			escaped = true
+		case char == '"':
 			inQuotes = !inQuotes
+		case inQuotes:
+		case char == '[' || char == '(' || char == '{':
+			depth++
+		case char == ']' || char == ')' || char == '}':
+			depth--
+		case char == ',' && depth <= 0:
+			add()
+
+			continue
 		}
 
-		if char == ',' && !inQuotes && !inArray {
-			if currentKey == "" {
-				currentKey = currentValue
-				currentValue = "true"
+		current.WriteRune(char)
+	}
+
+	add()
+
+	return items
+}
+
+// fieldSets splits a list of field sets, i.e. [id, sku upc], a single field set is kept as is.
+func fieldSets(value string) []string {
+	value = strings.TrimSpace(value)
+
+	if !strings.HasPrefix(value, "[") {
+		return []string{value}
+	}
+
+	return splitList(strings.TrimSuffix(strings.TrimPrefix(value, "["), "]"))
+}
+
+// closestOption returns the known option closest to an unknown one, if it is close enough to be a typo.
+func closestOption(key string) string {
+	closest, closestDistance := "", 3
+
+	for _, known := range knownOptions {
+		if distance := editDistance(strings.ToLower(key), strings.ToLower(known)); distance < closestDistance {
+			closest, closestDistance = known, distance
+		}
+	}
+
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a string, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
 			}
 
-			tagOptions[currentKey] = currentValue
+			current[j] = previous[j-1] + cost
 
-			currentKey = ""
-			currentValue = ""
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
 
-			prevChar = char
-			continue
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
 		}
 
-		if char == '=' {
-			currentKey = currentValue
-			currentValue = ""
+		previous, current = current, previous
+	}
 
-			prevChar = char
-			continue
+	return previous[len(target)]
+}
+
+func ptrOf(s string) *string {
+	return &s
+}
+
+// isType returns whether a type is written in SDL, i.e. [String!]!.
+func isType(t string) bool {
+	t = strings.TrimSuffix(t, "!")
+
+	if strings.HasPrefix(t, "[") && strings.HasSuffix(t, "]") {
+		return isType(t[1 : len(t)-1])
+	}
+
+	return schema.IsValidName(t)
+}
+
+// splitDirectives splits a list of decorators, i.e. [+unique(), +auth(roles: [ADMIN])].
+// The brackets around a single decorator can be left out. On an error it returns the
+// decorators before it along with the offset of the error in the list.
+func splitDirectives(list string) ([]Directive, int, error) {
+	source := []rune(list)
+	start, end := 0, len(source)
+
+	for start < end && unicode.IsSpace(source[start]) {
+		start++
+	}
+
+	for end > start && unicode.IsSpace(source[end-1]) {
+		end--
+	}
+
+	if start < end && source[start] == '[' {
+		if source[end-1] != ']' {
+			return nil, start, fmt.Errorf("the list of decorators is never closed")
+		}
+
+		start++
+		end--
+	}
+
+	var decorators []Directive
+
+	for itemStart := start; itemStart <= end; {
+		itemEnd := itemStart
+		depth := 0
+		inQuotes := false
+
+		for ; itemEnd < end; itemEnd++ {
+			char := source[itemEnd]
+
+			switch {
+			case inQuotes && char == '\\':
+				itemEnd++
+			case char == '"':
+				inQuotes = !inQuotes
+			case inQuotes:
+			case char == '(' || char == '[' || char == '{':
+				depth++
+			case char == ')' || char == ']' || char == '}':
+				depth--
+			}
+
+			if char == ',' && !inQuotes && depth <= 0 {
+				break
+			}
 		}
 
-		currentValue += string(char)
-		prevChar = char
+		if itemEnd > end {
+			itemEnd = end
+		}
+
+		decorator, offset, err := parseDecorator(source[itemStart:itemEnd])
+		if err != nil {
+			return decorators, itemStart + offset, err
+		}
+
+		if decorator != nil {
+			decorators = append(decorators, *decorator)
+		}
+
+		itemStart = itemEnd + 1
 	}
 
-	if currentKey != "" && currentValue != "" {
-		tagOptions[currentKey] = currentValue
+	return decorators, 0, nil
+}
+
+// parseDecorator parses a single decorator, i.e. +auth(role: "admin"). It returns nil
+// for an empty item, and the offset of an error in the item.
+func parseDecorator(item []rune) (*Directive, int, error) {
+	start := 0
+	for start < len(item) && unicode.IsSpace(item[start]) {
+		start++
 	}
 
-	if len(tagOptions) == 0 {
-		return nil
+	raw := strings.TrimRightFunc(string(item[start:]), unicode.IsSpace)
+	if raw == "" {
+		return nil, 0, nil
 	}
 
-	return &Tag{
-		Options: tagOptions,
+	if !strings.HasPrefix(raw, "+") {
+		return nil, start, fmt.Errorf("a decorator must start with +, found %q", raw)
 	}
+
+	raw = raw[1:]
+	decorator := &Directive{Name: raw}
+
+	if open := strings.Index(raw, "("); open >= 0 {
+		if !strings.HasSuffix(raw, ")") {
+			return nil, start, fmt.Errorf("the arguments of decorator '%s' are never closed", raw[:open])
+		}
+
+		decorator.Name = raw[:open]
+		decorator.Arguments = strings.TrimSpace(raw[open+1 : len(raw)-1])
+	}
+
+	decorator.Name = strings.TrimSpace(decorator.Name)
+
+	if decorator.Name == "" || strings.IndexFunc(decorator.Name, func(r rune) bool { return !isKeyRune(r) }) >= 0 {
+		return nil, start, fmt.Errorf("'%s' is not a valid decorator name", decorator.Name)
+	}
+
+	return decorator, 0, nil
 }
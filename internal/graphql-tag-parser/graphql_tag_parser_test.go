@@ -1,6 +1,7 @@
 package tagparser_test
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -22,11 +23,333 @@ func TestGetTagsFromStruct(t *testing.T) {
 		assert.Equal(t, true, exists)
 		got := tagparser.GetTagFromField(field)
 
+		description := "This is a tagged field"
 		assert.Equal(t, tagparser.Tag{
-			Options: map[string]string{
-				"description": "This is a tagged field",
-				"decorators":  "[+doc(description: \"This field is tagged.\"),+requireAuthRole(role: \"admin\"))]",
+			Description: &description,
+			Directives: []tagparser.Directive{
+				{Name: "doc", Arguments: `description: "This field is tagged."`},
+				{Name: "requireAuthRole", Arguments: `role: "admin")`},
 			},
 		}, *got)
 	})
 }
+
+func TestDirectives(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      *tagparser.Tag
+		expected []tagparser.Directive
+	}{
+		{
+			name:     "No decorators",
+			tag:      tagparser.ParseTag("description=A field", "field"),
+			expected: nil,
+		},
+		{
+			name: "Decorators with and without arguments",
+			tag:  tagparser.ParseTag(`description=A field,decorators=[+unique(), +requireAuthRole(role: "admin,owner"), +cost]`, "field"),
+			expected: []tagparser.Directive{
+				{Name: "unique"},
+				{Name: "requireAuthRole", Arguments: `role: "admin,owner"`},
+				{Name: "cost"},
+			},
+		},
+		{
+			name: "Nested parentheses",
+			tag:  tagparser.ParseTag(`decorators=[+doc(description: "A (nested) comment"), +limit(max: 10)]`, "field"),
+			expected: []tagparser.Directive{
+				{Name: "doc", Arguments: `description: "A (nested) comment"`},
+				{Name: "limit", Arguments: "max: 10"},
+			},
+		},
+		{
+			name: "Nested lists",
+			tag:  tagparser.ParseTag(`decorators=[+auth(roles: [ADMIN, EDITOR]), +tag(names: ["a, b"])]`, "field"),
+			expected: []tagparser.Directive{
+				{Name: "auth", Arguments: "roles: [ADMIN, EDITOR]"},
+				{Name: "tag", Arguments: `names: ["a, b"]`},
+			},
+		},
+		{
+			name:     "A single decorator without brackets",
+			tag:      tagparser.ParseTag(`decorators=+unique`, "field"),
+			expected: []tagparser.Directive{{Name: "unique"}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.expected, test.tag.Directives)
+		})
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      *tagparser.Tag
+		expected *string
+	}{
+		{
+			name:     "Quoted reason",
+			tag:      tagparser.ParseTag(`deprecated="Use name, or email",description=The login`, "field"),
+			expected: ptrOf("Use name, or email"),
+		},
+		{
+			name:     "Unquoted reason",
+			tag:      tagparser.ParseTag("deprecated=Use name", "field"),
+			expected: ptrOf("Use name"),
+		},
+		{
+			name:     "Flag",
+			tag:      tagparser.ParseTag("description=The login,deprecated", "field"),
+			expected: ptrOf(""),
+		},
+		{
+			name: "Not deprecated",
+			tag:  tagparser.ParseTag("description=deprecated", "field"),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.expected, test.tag.Deprecated)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      string
+		expected *tagparser.Tag
+		err      string
+	}{
+		{
+			name: "Empty tag",
+			tag:  " ",
+		},
+		{
+			name:     "Excluded field",
+			tag:      "-",
+			expected: &tagparser.Tag{Exclude: true},
+		},
+		{
+			name: "Options and flags",
+			tag:  `description=The owner, connection ,type=ID,name=owner,nullable,node=false`,
+			expected: &tagparser.Tag{
+				Description: ptrOf("The owner"),
+				Connection:  true,
+				Type:        "ID",
+				Name:        "owner",
+				Nullability: tagparser.NullabilityNullable,
+			},
+		},
+		{
+			name: "= inside a value",
+			tag:  `description=a=b is true,node`,
+			expected: &tagparser.Tag{
+				Description: ptrOf("a=b is true"),
+				Node:        true,
+			},
+		},
+		{
+			name: "Quoted value with escapes and brackets",
+			tag:  `deprecated="Use \"name\", or [email",description=x`,
+			expected: &tagparser.Tag{
+				Deprecated:  ptrOf(`Use "name", or [email`),
+				Description: ptrOf("x"),
+			},
+		},
+		{
+			name: "Brackets in text",
+			tag:  `description=Smile :),deprecated=Use name (or [email,node`,
+			expected: &tagparser.Tag{
+				Description: ptrOf("Smile :)"),
+				Deprecated:  ptrOf("Use name (or [email"),
+				Node:        true,
+			},
+		},
+		{
+			name:     "Unclosed bracket in text",
+			tag:      `description=(legacy`,
+			expected: &tagparser.Tag{Description: ptrOf("(legacy")},
+		},
+		{
+			name:     "Escaped comma",
+			tag:      `description=Name\, or email,exclude`,
+			expected: &tagparser.Tag{Description: ptrOf("Name, or email"), Exclude: true},
+		},
+		{
+			name: "Nested brackets",
+			tag:  `decorators=[+auth(roles: [ADMIN, EDITOR], where: {level: 2}), +unique],key`,
+			expected: &tagparser.Tag{
+				Directives: []tagparser.Directive{
+					{Name: "auth", Arguments: "roles: [ADMIN, EDITOR], where: {level: 2}"},
+					{Name: "unique"},
+				},
+				Key: true,
+			},
+		},
+		{
+			name: "Arguments",
+			tag:  `args=[first: Int = 10, after: String, filter: [Status!]! = [OPEN]]`,
+			expected: &tagparser.Tag{Args: []tagparser.Argument{
+				{Name: "first", Type: "Int", DefaultValue: ptrOf("10")},
+				{Name: "after", Type: "String"},
+				{Name: "filter", Type: "[Status!]!", DefaultValue: ptrOf("[OPEN]")},
+			}},
+		},
+		{
+			name: "Federation options",
+			tag:  `key=[id, sku upc],shareable,external,requires=weight,provides=author { name }`,
+			expected: &tagparser.Tag{
+				Keys:      []string{"id", "sku upc"},
+				Shareable: true,
+				External:  true,
+				Requires:  "weight",
+				Provides:  "author { name }",
+			},
+		},
+		{
+			name: "Unknown options",
+			tag:  `descripton=The name,color=blue`,
+			expected: &tagparser.Tag{Warnings: []string{
+				"unknown option 'descripton' in the graphql tag on 'User.name' at column 1, did you mean 'description'?",
+				"unknown option 'color' in the graphql tag on 'User.name' at column 21",
+			}},
+		},
+		{
+			name:     "Option without a value",
+			tag:      `connection,description=`,
+			expected: &tagparser.Tag{Connection: true},
+			err:      "invalid graphql tag on 'User.name' at column 24: option 'description' has no value",
+		},
+		{
+			name:     "Trailing comma",
+			tag:      `node,`,
+			expected: &tagparser.Tag{Node: true},
+			err:      "invalid graphql tag on 'User.name' at column 6: expected an option after the comma",
+		},
+		{
+			name:     "Missing =",
+			tag:      `description The name,node`,
+			expected: &tagparser.Tag{Node: true},
+			err:      "invalid graphql tag on 'User.name' at column 13: expected a comma or =, found 'T'",
+		},
+		{
+			name:     "Option given twice",
+			tag:      `type=ID,type=String`,
+			expected: &tagparser.Tag{Type: "String"},
+			err:      "invalid graphql tag on 'User.name' at column 9: option 'type' is given more than once",
+		},
+		{
+			name:     "Unclosed string",
+			tag:      `description="The name`,
+			expected: &tagparser.Tag{Description: ptrOf(`"The name`)},
+			err:      "invalid graphql tag on 'User.name' at column 13: string is never closed",
+		},
+		{
+			name:     "Unclosed bracket",
+			tag:      `key=[id, sku`,
+			expected: &tagparser.Tag{Keys: []string{"id", "sku"}},
+			err:      "invalid graphql tag on 'User.name' at column 5: '[' is never closed",
+		},
+		{
+			name:     "Mismatched bracket",
+			tag:      `decorators=[+unique(]`,
+			expected: &tagparser.Tag{},
+			err:      "invalid graphql tag on 'User.name' at column 21: unexpected ']'",
+		},
+		{
+			name:     "Decorator without a +",
+			tag:      `decorators=[+unique(), auth(role: "admin")]`,
+			expected: &tagparser.Tag{Directives: []tagparser.Directive{{Name: "unique"}}},
+			err:      `invalid graphql tag on 'User.name' at column 24: a decorator must start with +, found "auth(role: \"admin\")"`,
+		},
+		{
+			name:     "Invalid option name",
+			tag:      `+unique()`,
+			expected: nil,
+			err:      "invalid graphql tag on 'User.name' at column 1: expected an option name, found '+'",
+		},
+		{
+			name:     "Flag with a value",
+			tag:      `connection=yes`,
+			expected: &tagparser.Tag{},
+			err:      "invalid graphql tag on 'User.name' at column 12: option 'connection' is a flag, its value can only be true or false",
+		},
+		{
+			name:     "Both nullable and non-null",
+			tag:      `nullable,nonNull`,
+			expected: &tagparser.Tag{Nullability: tagparser.NullabilityNonNull},
+			err:      "invalid graphql tag on 'User.name' at column 10: a field can't be both nullable and nonNull",
+		},
+		{
+			name:     "Invalid argument",
+			tag:      `args=[first Int]`,
+			expected: &tagparser.Tag{},
+			err:      "invalid graphql tag on 'User.name' at column 6: argument 'first Int' is not of the form name: Type or name: Type = default",
+		},
+		{
+			name:     "Option without a value given as a flag",
+			tag:      `node,type`,
+			expected: &tagparser.Tag{Node: true},
+			err:      "invalid graphql tag on 'User.name' at column 6: option 'type' needs a value, i.e. type=value",
+		},
+		{
+			name:     "Invalid type",
+			tag:      `type=[ID]`,
+			expected: &tagparser.Tag{Type: "[ID]"},
+			err:      "invalid graphql tag on 'User.name' at column 6: type '[ID]' is not a valid GraphQL name",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := tagparser.Parse(test.tag, "User.name")
+			assert.Equal(t, test.expected, actual)
+
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, tag := range []string{
+		"description=The name,connection",
+		`decorators=[+auth(roles: [ADMIN, EDITOR]), +unique()]`,
+		`deprecated="Use \"name\"",type=ID`,
+		`key=[id, sku upc],shareable`,
+		`args=[first: Int = 10, after: String]`,
+		`description=a\,b=c,`,
+		`decorators=[+doc(description: "A (nested) comment"), +limit(max: 10)]`,
+		`"(]`,
+	} {
+		f.Add(tag)
+	}
+
+	f.Fuzz(func(t *testing.T, tag string) {
+		_, err := tagparser.Parse(tag, "field")
+
+		var syntaxError *tagparser.SyntaxError
+		if err != nil && (!errors.As(err, &syntaxError) || syntaxError.Column < 1) {
+			t.Fatalf("unexpected error %v for %q", err, tag)
+		}
+	})
+}
+
+func ptrOf(s string) *string {
+	return &s
+}
@@ -0,0 +1,246 @@
+package tagparser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SyntaxError is an error in a graphql tag with the column it was found at.
+type SyntaxError struct {
+	// Field is the field the tag is on.
+	Field string
+	// Column is the column of the error in the tag, starting at 1.
+	Column  int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid graphql tag on '%s' at column %d: %s", e.Field, e.Column, e.Message)
+}
+
+// closers are the brackets closing each opening bracket.
+var closers = map[rune]rune{'[': ']', '(': ')', '{': '}'}
+
+// textOptions are the options whose values are text, which brackets don't nest in.
+var textOptions = map[string]bool{"description": true, "deprecated": true}
+
+// lexer splits a tag into its options. The grammar of a tag is
+//
+//	tag    = option { "," option }
+//	option = key [ "=" value ]
+//
+// A key is a name of letters, digits, _, - and . characters. A value runs up to the next
+// comma that isn't inside a string or brackets, so it can hold commas and = characters.
+// The values of text options, i.e. description=Smile :), are the exception: brackets are
+// plain text in them, so they don't need to be closed. Strings are quoted with " and their escapes are kept as
+// is, outside strings a \ escapes the next character, i.e. \, is a comma within a value.
+type lexer struct {
+	source []rune
+	offset int
+	field  string
+
+	// err is the first error found, lexing goes on after it
+	// to return as much of the tag as it can.
+	err *SyntaxError
+}
+
+func (l *lexer) eof() bool {
+	return l.offset >= len(l.source)
+}
+
+func (l *lexer) peek() rune {
+	return l.source[l.offset]
+}
+
+// errorAt records an error at an offset, unless an error was already found.
+func (l *lexer) errorAt(offset int, format string, args ...interface{}) {
+	if l.err == nil {
+		l.err = &SyntaxError{Field: l.field, Column: offset + 1, Message: fmt.Sprintf(format, args...)}
+	}
+}
+
+func (l *lexer) skipSpaces() {
+	for !l.eof() && unicode.IsSpace(l.peek()) {
+		l.offset++
+	}
+}
+
+// option is an option of a tag as it was lexed, with the offsets of its key and value.
+type option struct {
+	key   string
+	value string
+	// raw is the value as it is written in the tag.
+	raw  string
+	flag bool
+
+	keyOffset   int
+	valueOffset int
+}
+
+// options lexes the whole tag into its options, flags are given the value true.
+func (l *lexer) options() []option {
+	var (
+		options []option
+		seen    = map[string]bool{}
+	)
+
+	for {
+		l.skipSpaces()
+
+		start := l.offset
+		key := l.key()
+
+		if key == "" {
+			if l.eof() {
+				l.errorAt(start, "expected an option after the comma")
+			} else {
+				l.errorAt(start, "expected an option name, found %q", l.peek())
+			}
+		}
+
+		o := option{key: key, value: "true", flag: true, keyOffset: start}
+
+		if !l.eof() && l.peek() == '=' {
+			l.offset++
+			l.skipSpaces()
+
+			o.valueOffset = l.offset
+			o.value = l.value(!textOptions[key])
+			o.raw = strings.TrimRightFunc(string(l.source[o.valueOffset:l.offset]), unicode.IsSpace)
+			o.flag = false
+
+			if o.value == "" {
+				l.errorAt(o.valueOffset, "option '%s' has no value", key)
+			}
+		}
+
+		if seen[key] {
+			l.errorAt(start, "option '%s' is given more than once", key)
+		}
+
+		seen[key] = true
+
+		if key != "" && o.value != "" {
+			options = append(options, o)
+		}
+
+		if l.eof() {
+			return options
+		}
+
+		if l.peek() != ',' {
+			l.errorAt(l.offset, "expected a comma or =, found %q", l.peek())
+
+			// Skip to the next option.
+			l.value(true)
+
+			if l.eof() {
+				return options
+			}
+		}
+
+		l.offset++
+	}
+}
+
+// key lexes the name of an option.
+func (l *lexer) key() string {
+	start := l.offset
+
+	for !l.eof() && isKeyRune(l.peek()) {
+		l.offset++
+	}
+
+	key := string(l.source[start:l.offset])
+	l.skipSpaces()
+
+	return key
+}
+
+func isKeyRune(r rune) bool {
+	return r == '_' || r == '-' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// value lexes the value of an option up to the comma ending it, brackets are kept as
+// text unless they nest. Spaces after the commas inside brackets are dropped, i.e.
+// [a, b] is lexed as [a,b].
+func (l *lexer) value(nested bool) string {
+	var (
+		value strings.Builder
+		// Offsets of the brackets that are open.
+		open []int
+	)
+
+	for !l.eof() {
+		char := l.peek()
+
+		switch {
+		case char == ',' && len(open) == 0:
+			return strings.TrimRightFunc(value.String(), unicode.IsSpace)
+		case char == '"':
+			value.WriteString(l.quoted())
+
+			continue
+		case char == '\\':
+			l.offset++
+
+			if l.eof() {
+				l.errorAt(l.offset-1, "expected a character after \\")
+
+				continue
+			}
+
+			char = l.peek()
+		case !nested:
+		case closers[char] != 0:
+			open = append(open, l.offset)
+		case char == ']' || char == ')' || char == '}':
+			if len(open) == 0 || closers[l.source[open[len(open)-1]]] != char {
+				l.errorAt(l.offset, "unexpected %q", char)
+			} else {
+				open = open[:len(open)-1]
+			}
+		case char == ',':
+			value.WriteRune(char)
+			l.offset++
+			l.skipSpaces()
+
+			continue
+		}
+
+		value.WriteRune(char)
+		l.offset++
+	}
+
+	if len(open) > 0 {
+		offset := open[len(open)-1]
+		l.errorAt(offset, "%q is never closed", l.source[offset])
+	}
+
+	return strings.TrimRightFunc(value.String(), unicode.IsSpace)
+}
+
+// quoted lexes a string, quotes and escapes included.
+func (l *lexer) quoted() string {
+	start := l.offset
+	l.offset++
+
+	for !l.eof() {
+		switch l.peek() {
+		case '\\':
+			l.offset++
+		case '"':
+			l.offset++
+
+			return string(l.source[start:l.offset])
+		}
+
+		l.offset++
+	}
+
+	l.errorAt(start, "string is never closed")
+	l.offset = len(l.source)
+
+	return string(l.source[start:])
+}
@@ -0,0 +1,64 @@
+package schemadiff
+
+import (
+	"strings"
+
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+// typesByName indexes the types of a schema by their name.
+func typesByName(s *schema.Schema) map[string]schema.Type {
+	types := make(map[string]schema.Type, len(s.Types))
+	for _, t := range s.Types {
+		types[t.TypeName()] = t
+	}
+
+	return types
+}
+
+// directivesByName indexes the directive definitions of a schema by their name.
+func directivesByName(s *schema.Schema) map[string]*schema.Directive {
+	directives := make(map[string]*schema.Directive, len(s.Directives))
+	for _, directive := range s.Directives {
+		directives[directive.Name] = directive
+	}
+
+	return directives
+}
+
+// fieldsOf returns the fields and the interfaces of an object or an interface type.
+func fieldsOf(t schema.Type) ([]*schema.Field, []string) {
+	switch t := t.(type) {
+	case *schema.ObjectType:
+		return t.Fields, t.Interfaces
+	case *schema.InterfaceType:
+		return t.Fields, t.Interfaces
+	default:
+		return nil, nil
+	}
+}
+
+func findField(fields []*schema.Field, name string) *schema.Field {
+	for _, field := range fields {
+		if field.Name == name {
+			return field
+		}
+	}
+
+	return nil
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// kindName is the kind of a type as it is written in SDL, for messages.
+func kindName(kind schema.Kind) string {
+	return strings.ReplaceAll(strings.ToLower(string(kind)), "_", " ")
+}
@@ -0,0 +1,436 @@
+package schemadiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+// Criticality is how a change affects the clients of a schema.
+type Criticality string
+
+const (
+	// Breaking changes break queries that were valid against the previous schema.
+	Breaking Criticality = "BREAKING"
+	// Dangerous changes keep queries valid but can change how clients behave,
+	// i.e. a new enum value a client doesn't handle.
+	Dangerous Criticality = "DANGEROUS"
+	// Safe changes can't affect existing clients.
+	Safe Criticality = "SAFE"
+)
+
+// ChangeType identifies the kind of a change, they follow graphql-js.
+type ChangeType string
+
+const (
+	TypeAdded                   ChangeType = "TYPE_ADDED"
+	TypeRemoved                 ChangeType = "TYPE_REMOVED"
+	TypeChangedKind             ChangeType = "TYPE_CHANGED_KIND"
+	TypeAddedToUnion            ChangeType = "TYPE_ADDED_TO_UNION"
+	TypeRemovedFromUnion        ChangeType = "TYPE_REMOVED_FROM_UNION"
+	ValueAddedToEnum            ChangeType = "VALUE_ADDED_TO_ENUM"
+	ValueRemovedFromEnum        ChangeType = "VALUE_REMOVED_FROM_ENUM"
+	ValueDeprecated             ChangeType = "VALUE_DEPRECATED"
+	ImplementedInterfaceAdded   ChangeType = "IMPLEMENTED_INTERFACE_ADDED"
+	ImplementedInterfaceRemoved ChangeType = "IMPLEMENTED_INTERFACE_REMOVED"
+	FieldAdded                  ChangeType = "FIELD_ADDED"
+	FieldRemoved                ChangeType = "FIELD_REMOVED"
+	FieldChangedKind            ChangeType = "FIELD_CHANGED_KIND"
+	FieldDeprecated             ChangeType = "FIELD_DEPRECATED"
+	RequiredInputFieldAdded     ChangeType = "REQUIRED_INPUT_FIELD_ADDED"
+	OptionalInputFieldAdded     ChangeType = "OPTIONAL_INPUT_FIELD_ADDED"
+	RequiredArgAdded            ChangeType = "REQUIRED_ARG_ADDED"
+	OptionalArgAdded            ChangeType = "OPTIONAL_ARG_ADDED"
+	ArgRemoved                  ChangeType = "ARG_REMOVED"
+	ArgChangedKind              ChangeType = "ARG_CHANGED_KIND"
+	ArgDefaultValueChange       ChangeType = "ARG_DEFAULT_VALUE_CHANGE"
+	DirectiveAdded              ChangeType = "DIRECTIVE_ADDED"
+	DirectiveRemoved            ChangeType = "DIRECTIVE_REMOVED"
+	DirectiveLocationRemoved    ChangeType = "DIRECTIVE_LOCATION_REMOVED"
+	DirectiveRepeatableRemoved  ChangeType = "DIRECTIVE_REPEATABLE_REMOVED"
+)
+
+// Change is a single difference between two schemas.
+type Change struct {
+	Criticality Criticality `json:"criticality"`
+	Type        ChangeType  `json:"type"`
+	// Path is the coordinate of what changed, i.e. User.email or Query.user(id:).
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Report lists the changes between two schemas, breaking changes first.
+type Report struct {
+	Changes []Change `json:"changes"`
+}
+
+// HasBreakingChanges returns whether any change breaks existing clients.
+func (r *Report) HasBreakingChanges() bool {
+	for _, change := range r.Changes {
+		if change.Criticality == Breaking {
+			return true
+		}
+	}
+
+	return false
+}
+
+// JSON returns the report in its machine readable form.
+func (r *Report) JSON() string {
+	changes := r.Changes
+	if changes == nil {
+		changes = []Change{}
+	}
+
+	out, _ := json.MarshalIndent(Report{Changes: changes}, "", "  ") //nolint: errchkjson
+
+	return string(out) + "\n"
+}
+
+// String returns the report with one change per line.
+func (r *Report) String() string {
+	if len(r.Changes) == 0 {
+		return "No changes.\n"
+	}
+
+	out := &strings.Builder{}
+
+	for _, change := range r.Changes {
+		out.WriteString(fmt.Sprintf("%-9s %s: %s\n", change.Criticality, change.Path, change.Message))
+	}
+
+	return out.String()
+}
+
+// CompareSDL parses two schemas written in SDL and compares them.
+func CompareSDL(previous string, next string) (*Report, error) {
+	previousSchema, err := Parse(previous)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the previous schema: %w", err)
+	}
+
+	nextSchema, err := Parse(next)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the new schema: %w", err)
+	}
+
+	return Compare(previousSchema, nextSchema), nil
+}
+
+// Compare finds the changes made to a schema between two versions. A field or an argument
+// that changes type is reported as safe when clients can't tell, i.e. a field becoming
+// non-null or an argument becoming nullable.
+func Compare(previous *schema.Schema, next *schema.Schema) *Report {
+	c := &comparison{}
+
+	previousTypes, nextTypes := typesByName(previous), typesByName(next)
+
+	for _, name := range sortedKeys(previousTypes) {
+		previousType := previousTypes[name]
+		nextType, ok := nextTypes[name]
+
+		switch {
+		case !ok:
+			c.add(Breaking, TypeRemoved, name, "Type %s was removed.", name)
+		case previousType.TypeKind() != nextType.TypeKind():
+			previousKind, nextKind := kindName(previousType.TypeKind()), kindName(nextType.TypeKind())
+			c.add(Breaking, TypeChangedKind, name, "Type %s changed from %s %s to %s %s.", name,
+				article(previousKind), previousKind, article(nextKind), nextKind)
+		default:
+			c.compareType(previousType, nextType)
+		}
+	}
+
+	for _, name := range sortedKeys(nextTypes) {
+		if _, ok := previousTypes[name]; !ok {
+			c.add(Safe, TypeAdded, name, "Type %s was added.", name)
+		}
+	}
+
+	previousDirectives, nextDirectives := directivesByName(previous), directivesByName(next)
+
+	for _, name := range sortedKeys(previousDirectives) {
+		if nextDirective, ok := nextDirectives[name]; ok {
+			c.compareDirective(previousDirectives[name], nextDirective)
+		} else {
+			c.add(Breaking, DirectiveRemoved, "@"+name, "Directive @%s was removed.", name)
+		}
+	}
+
+	for _, name := range sortedKeys(nextDirectives) {
+		if _, ok := previousDirectives[name]; !ok {
+			c.add(Safe, DirectiveAdded, "@"+name, "Directive @%s was added.", name)
+		}
+	}
+
+	order := map[Criticality]int{Breaking: 0, Dangerous: 1, Safe: 2}
+
+	sort.SliceStable(c.changes, func(i, j int) bool {
+		return order[c.changes[i].Criticality] < order[c.changes[j].Criticality]
+	})
+
+	return &Report{Changes: c.changes}
+}
+
+type comparison struct {
+	changes []Change
+}
+
+func (c *comparison) add(criticality Criticality, changeType ChangeType, path string, format string, args ...interface{}) {
+	c.changes = append(c.changes, Change{
+		Criticality: criticality,
+		Type:        changeType,
+		Path:        path,
+		Message:     fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *comparison) compareType(previous schema.Type, next schema.Type) {
+	switch previous := previous.(type) {
+	case *schema.ObjectType, *schema.InterfaceType:
+		c.compareFields(previous, next)
+		c.compareInterfaces(previous, next)
+	case *schema.InputObjectType:
+		c.compareInputFields(previous, next.(*schema.InputObjectType))
+	case *schema.EnumType:
+		c.compareEnumValues(previous, next.(*schema.EnumType))
+	case *schema.UnionType:
+		c.compareUnionMembers(previous, next.(*schema.UnionType))
+	}
+}
+
+func (c *comparison) compareFields(previous schema.Type, next schema.Type) {
+	previousFields, _ := fieldsOf(previous)
+	nextFields, _ := fieldsOf(next)
+
+	for _, previousField := range previousFields {
+		path := previous.TypeName() + "." + previousField.Name
+		nextField := findField(nextFields, previousField.Name)
+
+		if nextField == nil {
+			c.add(Breaking, FieldRemoved, path, "Field %s was removed.", path)
+
+			continue
+		}
+
+		switch {
+		case !isSafeOutputChange(previousField.Type, nextField.Type):
+			c.add(Breaking, FieldChangedKind, path, "Field %s changed type from %s to %s.", path, previousField.Type, nextField.Type)
+		case previousField.Type.String() != nextField.Type.String():
+			c.add(Safe, FieldChangedKind, path, "Field %s changed type from %s to %s.", path, previousField.Type, nextField.Type)
+		}
+
+		if !previousField.IsDeprecated() && nextField.IsDeprecated() {
+			c.add(Safe, FieldDeprecated, path, "Field %s was deprecated.", path)
+		}
+
+		c.compareArguments(path, previousField.Arguments, nextField.Arguments)
+	}
+
+	for _, nextField := range nextFields {
+		if findField(previousFields, nextField.Name) == nil {
+			path := next.TypeName() + "." + nextField.Name
+			c.add(Safe, FieldAdded, path, "Field %s was added.", path)
+		}
+	}
+}
+
+func (c *comparison) compareArguments(parentPath string, previous []*schema.InputValue, next []*schema.InputValue) {
+	for _, previousArgument := range previous {
+		path := fmt.Sprintf("%s(%s:)", parentPath, previousArgument.Name)
+		nextArgument := schema.FindInputValue(next, previousArgument.Name)
+
+		switch {
+		case nextArgument == nil:
+			c.add(Breaking, ArgRemoved, path, "Argument %s was removed.", path)
+		case !isSafeInputChange(previousArgument.Type, nextArgument.Type):
+			c.add(Breaking, ArgChangedKind, path, "Argument %s changed type from %s to %s.", path, previousArgument.Type, nextArgument.Type)
+		default:
+			if previousArgument.DefaultValue != nil && (nextArgument.DefaultValue == nil || *previousArgument.DefaultValue != *nextArgument.DefaultValue) {
+				c.add(Dangerous, ArgDefaultValueChange, path, "Argument %s has a new default value.", path)
+			}
+
+			if previousArgument.Type.String() != nextArgument.Type.String() {
+				c.add(Safe, ArgChangedKind, path, "Argument %s changed type from %s to %s.", path, previousArgument.Type, nextArgument.Type)
+			}
+		}
+	}
+
+	for _, nextArgument := range next {
+		if schema.FindInputValue(previous, nextArgument.Name) != nil {
+			continue
+		}
+
+		path := fmt.Sprintf("%s(%s:)", parentPath, nextArgument.Name)
+
+		if nextArgument.Type.NonNull && nextArgument.DefaultValue == nil {
+			c.add(Breaking, RequiredArgAdded, path, "Required argument %s was added.", path)
+		} else {
+			c.add(Dangerous, OptionalArgAdded, path, "Optional argument %s was added.", path)
+		}
+	}
+}
+
+func (c *comparison) compareInputFields(previous *schema.InputObjectType, next *schema.InputObjectType) {
+	for _, previousField := range previous.Fields {
+		path := previous.Name + "." + previousField.Name
+		nextField := schema.FindInputValue(next.Fields, previousField.Name)
+
+		switch {
+		case nextField == nil:
+			c.add(Breaking, FieldRemoved, path, "Field %s was removed.", path)
+		case !isSafeInputChange(previousField.Type, nextField.Type):
+			c.add(Breaking, FieldChangedKind, path, "Field %s changed type from %s to %s.", path, previousField.Type, nextField.Type)
+		case previousField.Type.String() != nextField.Type.String():
+			c.add(Safe, FieldChangedKind, path, "Field %s changed type from %s to %s.", path, previousField.Type, nextField.Type)
+		}
+	}
+
+	for _, nextField := range next.Fields {
+		if schema.FindInputValue(previous.Fields, nextField.Name) != nil {
+			continue
+		}
+
+		path := next.Name + "." + nextField.Name
+
+		if nextField.Type.NonNull && nextField.DefaultValue == nil {
+			c.add(Breaking, RequiredInputFieldAdded, path, "Required input field %s was added.", path)
+		} else {
+			c.add(Dangerous, OptionalInputFieldAdded, path, "Optional input field %s was added.", path)
+		}
+	}
+}
+
+func (c *comparison) compareInterfaces(previous schema.Type, next schema.Type) {
+	_, previousInterfaces := fieldsOf(previous)
+	_, nextInterfaces := fieldsOf(next)
+
+	for _, name := range previousInterfaces {
+		if !contains(nextInterfaces, name) {
+			c.add(Breaking, ImplementedInterfaceRemoved, previous.TypeName(), "%s no longer implements %s.", previous.TypeName(), name)
+		}
+	}
+
+	for _, name := range nextInterfaces {
+		if !contains(previousInterfaces, name) {
+			c.add(Dangerous, ImplementedInterfaceAdded, next.TypeName(), "%s now implements %s.", next.TypeName(), name)
+		}
+	}
+}
+
+func (c *comparison) compareEnumValues(previous *schema.EnumType, next *schema.EnumType) {
+	nextValues := map[string]*schema.EnumValue{}
+	for _, value := range next.Values {
+		nextValues[value.Name] = value
+	}
+
+	previousValues := map[string]bool{}
+
+	for _, value := range previous.Values {
+		path := previous.Name + "." + value.Name
+		previousValues[value.Name] = true
+
+		nextValue, ok := nextValues[value.Name]
+
+		switch {
+		case !ok:
+			c.add(Breaking, ValueRemovedFromEnum, path, "Enum value %s was removed.", path)
+		case !value.IsDeprecated() && nextValue.IsDeprecated():
+			c.add(Safe, ValueDeprecated, path, "Enum value %s was deprecated.", path)
+		}
+	}
+
+	for _, value := range next.Values {
+		if !previousValues[value.Name] {
+			path := next.Name + "." + value.Name
+			c.add(Dangerous, ValueAddedToEnum, path, "Enum value %s was added.", path)
+		}
+	}
+}
+
+func (c *comparison) compareUnionMembers(previous *schema.UnionType, next *schema.UnionType) {
+	for _, name := range previous.Members {
+		if !contains(next.Members, name) {
+			c.add(Breaking, TypeRemovedFromUnion, previous.Name, "%s was removed from union %s.", name, previous.Name)
+		}
+	}
+
+	for _, name := range next.Members {
+		if !contains(previous.Members, name) {
+			c.add(Dangerous, TypeAddedToUnion, next.Name, "%s was added to union %s.", name, next.Name)
+		}
+	}
+}
+
+func (c *comparison) compareDirective(previous *schema.Directive, next *schema.Directive) {
+	path := "@" + previous.Name
+
+	c.compareArguments(path, previous.Arguments, next.Arguments)
+
+	if previous.Repeatable && !next.Repeatable {
+		c.add(Breaking, DirectiveRepeatableRemoved, path, "Directive %s is no longer repeatable.", path)
+	}
+
+	for _, location := range previous.Locations {
+		if !contains(next.Locations, location) {
+			c.add(Breaking, DirectiveLocationRemoved, path, "Location %s was removed from directive %s.", location, path)
+		}
+	}
+}
+
+// isSafeOutputChange returns whether a field can change type without breaking
+// clients. Clients read fields so a field can only become stricter, i.e. non-null.
+func isSafeOutputChange(previous *schema.TypeRef, next *schema.TypeRef) bool {
+	switch {
+	case previous.NonNull:
+		return next.NonNull && isSafeOutputChange(previous.OfType, next.OfType)
+	case next.NonNull:
+		return isSafeOutputChange(previous, next.OfType)
+	case previous.List:
+		return next.List && isSafeOutputChange(previous.OfType, next.OfType)
+	default:
+		return !next.List && previous.Name == next.Name
+	}
+}
+
+// isSafeInputChange returns whether an argument or input field can change type
+// without breaking clients. Clients write them so they can only become looser, i.e.
+// nullable, as a value for a non-null argument that was nullable may be missing.
+func isSafeInputChange(previous *schema.TypeRef, next *schema.TypeRef) bool {
+	switch {
+	case previous.NonNull:
+		if next.NonNull {
+			return isSafeInputChange(previous.OfType, next.OfType)
+		}
+
+		return isSafeInputChange(previous.OfType, next)
+	case next.NonNull:
+		return false
+	case previous.List:
+		return next.List && isSafeInputChange(previous.OfType, next.OfType)
+	default:
+		return !next.List && previous.Name == next.Name
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func article(word string) string {
+	if strings.ContainsRune("aeiou", rune(word[0])) {
+		return "an"
+	}
+
+	return "a"
+}
@@ -0,0 +1,158 @@
+package schemadiff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	schemadiff "github.com/warpspeed-cloud/graphql-schema-generator/internal/schema-diff"
+)
+
+func TestCompareSDL(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous string
+		next     string
+		expected []schemadiff.Change
+	}{
+		{
+			name:     "No changes",
+			previous: "type User { id: String! }",
+			next:     "type User {\n  \"The ID\"\n  id: String!\n}",
+			expected: nil,
+		},
+		{
+			name:     "Field removed and added",
+			previous: "type User { id: String! email: String }",
+			next:     "type User { id: String! phone: String }",
+			expected: []schemadiff.Change{
+				{Criticality: schemadiff.Breaking, Type: schemadiff.FieldRemoved, Path: "User.email", Message: "Field User.email was removed."},
+				{Criticality: schemadiff.Safe, Type: schemadiff.FieldAdded, Path: "User.phone", Message: "Field User.phone was added."},
+			},
+		},
+		{
+			name:     "Field types",
+			previous: "type User { id: String! email: String roles: [String!]! age: Int tags: [String] }",
+			next:     "type User { id: String email: String! roles: [String!] age: Float tags: [String!]! }",
+			expected: []schemadiff.Change{
+				{Criticality: schemadiff.Breaking, Type: schemadiff.FieldChangedKind, Path: "User.id", Message: "Field User.id changed type from String! to String."},
+				{Criticality: schemadiff.Breaking, Type: schemadiff.FieldChangedKind, Path: "User.roles", Message: "Field User.roles changed type from [String!]! to [String!]."},
+				{Criticality: schemadiff.Breaking, Type: schemadiff.FieldChangedKind, Path: "User.age", Message: "Field User.age changed type from Int to Float."},
+				{Criticality: schemadiff.Safe, Type: schemadiff.FieldChangedKind, Path: "User.email", Message: "Field User.email changed type from String to String!."},
+				{Criticality: schemadiff.Safe, Type: schemadiff.FieldChangedKind, Path: "User.tags", Message: "Field User.tags changed type from [String] to [String!]!."},
+			},
+		},
+		{
+			name:     "Arguments",
+			previous: "type Query { users(first: Int, after: String, role: String = \"user\", ids: [ID!]!): [String] }",
+			next:     "type Query { users(first: Int!, after: String, role: String = \"admin\", ids: [ID!], last: Int, org: ID!): [String] }",
+			expected: []schemadiff.Change{
+				{Criticality: schemadiff.Breaking, Type: schemadiff.ArgChangedKind, Path: "Query.users(first:)", Message: "Argument Query.users(first:) changed type from Int to Int!."},
+				{Criticality: schemadiff.Breaking, Type: schemadiff.RequiredArgAdded, Path: "Query.users(org:)", Message: "Required argument Query.users(org:) was added."},
+				{Criticality: schemadiff.Dangerous, Type: schemadiff.ArgDefaultValueChange, Path: "Query.users(role:)", Message: "Argument Query.users(role:) has a new default value."},
+				{Criticality: schemadiff.Dangerous, Type: schemadiff.OptionalArgAdded, Path: "Query.users(last:)", Message: "Optional argument Query.users(last:) was added."},
+				{Criticality: schemadiff.Safe, Type: schemadiff.ArgChangedKind, Path: "Query.users(ids:)", Message: "Argument Query.users(ids:) changed type from [ID!]! to [ID!]."},
+			},
+		},
+		{
+			name:     "Types",
+			previous: "type User { id: ID! } type Project { id: ID! } scalar Date",
+			next:     "type User { id: ID! } enum Date { TODAY } type Team { id: ID! }",
+			expected: []schemadiff.Change{
+				{Criticality: schemadiff.Breaking, Type: schemadiff.TypeChangedKind, Path: "Date", Message: "Type Date changed from a scalar to an enum."},
+				{Criticality: schemadiff.Breaking, Type: schemadiff.TypeRemoved, Path: "Project", Message: "Type Project was removed."},
+				{Criticality: schemadiff.Safe, Type: schemadiff.TypeAdded, Path: "Team", Message: "Type Team was added."},
+			},
+		},
+		{
+			name:     "Enums, unions and interfaces",
+			previous: "enum Role { USER ADMIN } union Result = User | Team type User implements Node & Entity { id: ID! }",
+			next:     "enum Role { USER @deprecated(reason: \"Use MEMBER\") MEMBER } union Result = | User | Project type User implements Node & Actor { id: ID! }",
+			expected: []schemadiff.Change{
+				{Criticality: schemadiff.Breaking, Type: schemadiff.TypeRemovedFromUnion, Path: "Result", Message: "Team was removed from union Result."},
+				{Criticality: schemadiff.Breaking, Type: schemadiff.ValueRemovedFromEnum, Path: "Role.ADMIN", Message: "Enum value Role.ADMIN was removed."},
+				{Criticality: schemadiff.Breaking, Type: schemadiff.ImplementedInterfaceRemoved, Path: "User", Message: "User no longer implements Entity."},
+				{Criticality: schemadiff.Dangerous, Type: schemadiff.TypeAddedToUnion, Path: "Result", Message: "Project was added to union Result."},
+				{Criticality: schemadiff.Dangerous, Type: schemadiff.ValueAddedToEnum, Path: "Role.MEMBER", Message: "Enum value Role.MEMBER was added."},
+				{Criticality: schemadiff.Dangerous, Type: schemadiff.ImplementedInterfaceAdded, Path: "User", Message: "User now implements Actor."},
+				{Criticality: schemadiff.Safe, Type: schemadiff.ValueDeprecated, Path: "Role.USER", Message: "Enum value Role.USER was deprecated."},
+			},
+		},
+		{
+			name:     "Input objects",
+			previous: "input UserInput { name: String! email: String }",
+			next:     "input UserInput { name: String email: String! age: Int role: String! = \"user\" org: ID! }",
+			expected: []schemadiff.Change{
+				{Criticality: schemadiff.Breaking, Type: schemadiff.FieldChangedKind, Path: "UserInput.email", Message: "Field UserInput.email changed type from String to String!."},
+				{Criticality: schemadiff.Breaking, Type: schemadiff.RequiredInputFieldAdded, Path: "UserInput.org", Message: "Required input field UserInput.org was added."},
+				{Criticality: schemadiff.Dangerous, Type: schemadiff.OptionalInputFieldAdded, Path: "UserInput.age", Message: "Optional input field UserInput.age was added."},
+				{Criticality: schemadiff.Dangerous, Type: schemadiff.OptionalInputFieldAdded, Path: "UserInput.role", Message: "Optional input field UserInput.role was added."},
+				{Criticality: schemadiff.Safe, Type: schemadiff.FieldChangedKind, Path: "UserInput.name", Message: "Field UserInput.name changed type from String! to String."},
+			},
+		},
+		{
+			name:     "Directives",
+			previous: "directive @auth(role: String) repeatable on FIELD_DEFINITION | OBJECT directive @unique on FIELD_DEFINITION",
+			next:     "directive @auth(role: String!) on FIELD_DEFINITION directive @cost(weight: Int) on FIELD_DEFINITION",
+			expected: []schemadiff.Change{
+				{Criticality: schemadiff.Breaking, Type: schemadiff.ArgChangedKind, Path: "@auth(role:)", Message: "Argument @auth(role:) changed type from String to String!."},
+				{Criticality: schemadiff.Breaking, Type: schemadiff.DirectiveRepeatableRemoved, Path: "@auth", Message: "Directive @auth is no longer repeatable."},
+				{Criticality: schemadiff.Breaking, Type: schemadiff.DirectiveLocationRemoved, Path: "@auth", Message: "Location OBJECT was removed from directive @auth."},
+				{Criticality: schemadiff.Breaking, Type: schemadiff.DirectiveRemoved, Path: "@unique", Message: "Directive @unique was removed."},
+				{Criticality: schemadiff.Safe, Type: schemadiff.DirectiveAdded, Path: "@cost", Message: "Directive @cost was added."},
+			},
+		},
+		{
+			name:     "Field deprecated",
+			previous: "type User { name: String! }",
+			next:     "type User { name: String! @deprecated(reason: \"\"\"Use fullName\"\"\") }",
+			expected: []schemadiff.Change{
+				{Criticality: schemadiff.Safe, Type: schemadiff.FieldDeprecated, Path: "User.name", Message: "Field User.name was deprecated."},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := schemadiff.CompareSDL(test.previous, test.next)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, report.Changes)
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	report, err := schemadiff.CompareSDL("type User { id: ID! email: String }", "type User { id: ID! name: String }")
+	assert.NoError(t, err)
+	assert.True(t, report.HasBreakingChanges())
+
+	assert.Equal(t, `BREAKING  User.email: Field User.email was removed.
+SAFE      User.name: Field User.name was added.
+`, report.String())
+
+	assert.Equal(t, `{
+  "changes": [
+    {
+      "criticality": "BREAKING",
+      "type": "FIELD_REMOVED",
+      "path": "User.email",
+      "message": "Field User.email was removed."
+    },
+    {
+      "criticality": "SAFE",
+      "type": "FIELD_ADDED",
+      "path": "User.name",
+      "message": "Field User.name was added."
+    }
+  ]
+}
+`, report.JSON())
+
+	empty, err := schemadiff.CompareSDL("type User { id: ID! }", "type User { id: ID! }")
+	assert.NoError(t, err)
+	assert.False(t, empty.HasBreakingChanges())
+	assert.Equal(t, "No changes.\n", empty.String())
+	assert.Equal(t, "{\n  \"changes\": []\n}\n", empty.JSON())
+}
@@ -0,0 +1,922 @@
+package schemadiff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunctuator
+	tokenName
+	tokenNumber
+	tokenString
+)
+
+type token struct {
+	kind   tokenKind
+	value  string
+	line   int
+	column int
+}
+
+// lexer splits SDL into tokens, commas and comments are insignificant so they are skipped.
+type lexer struct {
+	source string
+	offset int
+	line   int
+	column int
+}
+
+// SyntaxError is an error in SDL with the position it was found at.
+type SyntaxError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at %d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+func (l *lexer) peekRune() rune {
+	if l.offset >= len(l.source) {
+		return utf8.RuneError
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.source[l.offset:])
+
+	return r
+}
+
+func (l *lexer) advance() rune {
+	r, size := utf8.DecodeRuneInString(l.source[l.offset:])
+	l.offset += size
+
+	if r == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+
+	return r
+}
+
+func isNameStart(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func isNameContinue(r rune) bool {
+	return isNameStart(r) || (r >= '0' && r <= '9')
+}
+
+func (l *lexer) next() (token, error) {
+	// Skip whitespace, commas and comments.
+	for l.offset < len(l.source) {
+		r := l.peekRune()
+
+		if r == '#' {
+			for l.offset < len(l.source) && l.peekRune() != '\n' {
+				l.advance()
+			}
+
+			continue
+		}
+
+		if r != ' ' && r != '\t' && r != '\n' && r != '\r' && r != ',' && r != '\uFEFF' {
+			break
+		}
+
+		l.advance()
+	}
+
+	t := token{line: l.line, column: l.column}
+
+	if l.offset >= len(l.source) {
+		t.kind = tokenEOF
+
+		return t, nil
+	}
+
+	r := l.peekRune()
+	start := l.offset
+
+	switch {
+	case strings.HasPrefix(l.source[l.offset:], "..."):
+		l.advance()
+		l.advance()
+		l.advance()
+
+		t.kind, t.value = tokenPunctuator, "..."
+	case strings.ContainsRune("!$&():=@[]{}|", r):
+		l.advance()
+
+		t.kind, t.value = tokenPunctuator, string(r)
+	case isNameStart(r):
+		for l.offset < len(l.source) && isNameContinue(l.peekRune()) {
+			l.advance()
+		}
+
+		t.kind, t.value = tokenName, l.source[start:l.offset]
+	case r == '-' || (r >= '0' && r <= '9'):
+		l.advance()
+
+		for l.offset < len(l.source) && strings.ContainsRune("0123456789.eE+-", l.peekRune()) {
+			l.advance()
+		}
+
+		t.kind, t.value = tokenNumber, l.source[start:l.offset]
+	case r == '"':
+		value, err := l.readString()
+		if err != nil {
+			return t, err
+		}
+
+		t.kind, t.value = tokenString, value
+	default:
+		return t, &SyntaxError{Line: t.line, Column: t.column, Message: fmt.Sprintf("unexpected character %q", r)}
+	}
+
+	return t, nil
+}
+
+// readString reads a string or a block string and returns its value.
+func (l *lexer) readString() (string, error) {
+	line, column := l.line, l.column
+
+	if strings.HasPrefix(l.source[l.offset:], `"""`) {
+		l.advance()
+		l.advance()
+		l.advance()
+
+		var value strings.Builder
+
+		for l.offset < len(l.source) {
+			switch {
+			case strings.HasPrefix(l.source[l.offset:], `\"""`):
+				value.WriteString(`"""`)
+
+				for i := 0; i < 4; i++ {
+					l.advance()
+				}
+			case strings.HasPrefix(l.source[l.offset:], `"""`):
+				l.advance()
+				l.advance()
+				l.advance()
+
+				return blockStringValue(value.String()), nil
+			default:
+				value.WriteRune(l.advance())
+			}
+		}
+
+		return "", &SyntaxError{Line: line, Column: column, Message: "unterminated block string"}
+	}
+
+	l.advance()
+
+	var value strings.Builder
+
+	for l.offset < len(l.source) {
+		r := l.advance()
+
+		switch r {
+		case '"':
+			return value.String(), nil
+		case '\n':
+			return "", &SyntaxError{Line: line, Column: column, Message: "unterminated string"}
+		case '\\':
+			escaped := l.advance()
+
+			switch escaped {
+			case 'n':
+				value.WriteRune('\n')
+			case 't':
+				value.WriteRune('\t')
+			case 'r':
+				value.WriteRune('\r')
+			case 'b':
+				value.WriteRune('\b')
+			case 'f':
+				value.WriteRune('\f')
+			case 'u':
+				if l.offset+4 > len(l.source) {
+					return "", &SyntaxError{Line: l.line, Column: l.column, Message: "invalid unicode escape"}
+				}
+
+				code, err := strconv.ParseUint(l.source[l.offset:l.offset+4], 16, 32)
+				if err != nil {
+					return "", &SyntaxError{Line: l.line, Column: l.column, Message: "invalid unicode escape"}
+				}
+
+				for i := 0; i < 4; i++ {
+					l.advance()
+				}
+
+				value.WriteRune(rune(code))
+			default:
+				value.WriteRune(escaped)
+			}
+		default:
+			value.WriteRune(r)
+		}
+	}
+
+	return "", &SyntaxError{Line: line, Column: column, Message: "unterminated string"}
+}
+
+// blockStringValue removes the common indentation and the leading
+// and trailing blank lines of a block string, as in the specification.
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	common := -1
+
+	for i, line := range lines {
+		if i == 0 {
+			continue
+		}
+
+		indentation := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indentation < len(line) && (common < 0 || indentation < common) {
+			common = indentation
+		}
+	}
+
+	for i := range lines {
+		if i > 0 && common > 0 {
+			if len(lines[i]) >= common {
+				lines[i] = lines[i][common:]
+			} else {
+				lines[i] = ""
+			}
+		}
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// parser builds a schema from the tokens of SDL.
+type parser struct {
+	lexer  *lexer
+	token  token
+	schema *schema.Schema
+}
+
+// Parse reads a schema written in SDL. Schema definitions, which only name
+// the root types, are skipped.
+func Parse(sdl string) (*schema.Schema, error) {
+	p := &parser{
+		lexer:  &lexer{source: sdl, line: 1, column: 1},
+		schema: &schema.Schema{},
+	}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	for p.token.kind != tokenEOF {
+		if err := p.parseDefinition(); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.schema, nil
+}
+
+func (p *parser) advance() error {
+	t, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+
+	p.token = t
+
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return errorAt(p.token, format, args...)
+}
+
+func errorAt(t token, format string, args ...interface{}) error {
+	return &SyntaxError{Line: t.line, Column: t.column, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) describeToken() string {
+	if p.token.kind == tokenEOF {
+		return "end of file"
+	}
+
+	return fmt.Sprintf("'%s'", p.token.value)
+}
+
+func (p *parser) is(value string) bool {
+	return (p.token.kind == tokenPunctuator || p.token.kind == tokenName) && p.token.value == value
+}
+
+// skip advances past the token if it is the given punctuator or keyword.
+func (p *parser) skip(value string) (bool, error) {
+	if !p.is(value) {
+		return false, nil
+	}
+
+	return true, p.advance()
+}
+
+func (p *parser) expect(value string) error {
+	if !p.is(value) {
+		return p.errorf("expected '%s', found %s", value, p.describeToken())
+	}
+
+	return p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.token.kind != tokenName {
+		return "", p.errorf("expected a name, found %s", p.describeToken())
+	}
+
+	name := p.token.value
+
+	return name, p.advance()
+}
+
+func (p *parser) parseDescription() (string, error) {
+	if p.token.kind != tokenString {
+		return "", nil
+	}
+
+	description := p.token.value
+
+	return description, p.advance()
+}
+
+var definitionKinds = map[string]schema.Kind{
+	"type":      schema.KindObject,
+	"interface": schema.KindInterface,
+	"input":     schema.KindInputObject,
+	"enum":      schema.KindEnum,
+	"scalar":    schema.KindScalar,
+	"union":     schema.KindUnion,
+}
+
+func (p *parser) parseDefinition() error {
+	description, err := p.parseDescription()
+	if err != nil {
+		return err
+	}
+
+	extend, err := p.skip("extend")
+	if err != nil {
+		return err
+	}
+
+	keywordAt := p.token
+
+	keyword, err := p.expectName()
+	if err != nil {
+		return err
+	}
+
+	switch keyword {
+	case "schema":
+		directives, err := p.parseDirectives()
+		if err != nil {
+			return err
+		}
+
+		if extend {
+			p.schema.ExtensionDirectives = append(p.schema.ExtensionDirectives, directives...)
+		}
+
+		return p.skipBlock()
+	case "directive":
+		return p.parseDirectiveDefinition(description)
+	}
+
+	kind, ok := definitionKinds[keyword]
+	if !ok {
+		return errorAt(keywordAt, "unexpected '%s'", keyword)
+	}
+
+	nameAt := p.token
+
+	name, err := p.expectName()
+	if err != nil {
+		return err
+	}
+
+	t := p.schema.Type(name)
+
+	switch {
+	case t == nil:
+		t = newType(kind, name, description)
+		p.schema.Types = append(p.schema.Types, t)
+	case !extend:
+		return errorAt(nameAt, "type '%s' is defined more than once", name)
+	case t.TypeKind() != kind:
+		return errorAt(nameAt, "'%s' is not %s %s", name, article(kindName(kind)), kindName(kind))
+	}
+
+	switch t := t.(type) {
+	case *schema.ObjectType:
+		return p.parseFields(&t.Interfaces, &t.Directives, &t.Fields)
+	case *schema.InterfaceType:
+		return p.parseFields(&t.Interfaces, &t.Directives, &t.Fields)
+	case *schema.InputObjectType:
+		return p.parseInputObject(t)
+	case *schema.EnumType:
+		return p.parseEnum(t)
+	case *schema.UnionType:
+		return p.parseUnion(t)
+	case *schema.ScalarType:
+		directives, err := p.parseDirectives()
+		t.Directives = append(t.Directives, directives...)
+
+		return err
+	}
+
+	return nil
+}
+
+func newType(kind schema.Kind, name string, description string) schema.Type {
+	switch kind {
+	case schema.KindObject:
+		return &schema.ObjectType{Name: name, Description: description}
+	case schema.KindInterface:
+		return &schema.InterfaceType{Name: name, Description: description}
+	case schema.KindInputObject:
+		return &schema.InputObjectType{Name: name, Description: description}
+	case schema.KindEnum:
+		return &schema.EnumType{Name: name, Description: description}
+	case schema.KindUnion:
+		return &schema.UnionType{Name: name, Description: description}
+	default:
+		return &schema.ScalarType{Name: name, Description: description}
+	}
+}
+
+// skipBlock skips a block between braces, if there is one.
+func (p *parser) skipBlock() error {
+	if !p.is("{") {
+		return nil
+	}
+
+	for depth := 0; ; {
+		switch {
+		case p.token.kind == tokenEOF:
+			return p.errorf("expected '}', found end of file")
+		case p.is("{"):
+			depth++
+		case p.is("}"):
+			depth--
+		}
+
+		if err := p.advance(); err != nil {
+			return err
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// parseFields parses the interfaces, directives and fields of an object or an interface type.
+func (p *parser) parseFields(interfaces *[]string, directives *[]*schema.AppliedDirective, fields *[]*schema.Field) error {
+	if ok, err := p.skip("implements"); err != nil {
+		return err
+	} else if ok {
+		if _, err := p.skip("&"); err != nil {
+			return err
+		}
+
+		for {
+			name, err := p.expectName()
+			if err != nil {
+				return err
+			}
+
+			*interfaces = append(*interfaces, name)
+
+			if ok, err := p.skip("&"); err != nil {
+				return err
+			} else if !ok {
+				break
+			}
+		}
+	}
+
+	applied, err := p.parseDirectives()
+	if err != nil {
+		return err
+	}
+
+	*directives = append(*directives, applied...)
+
+	if ok, err := p.skip("{"); err != nil || !ok {
+		return err
+	}
+
+	for !p.is("}") {
+		description, err := p.parseDescription()
+		if err != nil {
+			return err
+		}
+
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+
+		field := &schema.Field{Name: name, Description: description}
+
+		if p.is("(") {
+			if field.Arguments, err = p.parseInputValues("(", ")"); err != nil {
+				return err
+			}
+		}
+
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+
+		if field.Type, err = p.parseTypeRef(); err != nil {
+			return err
+		}
+
+		if field.Directives, err = p.parseDirectives(); err != nil {
+			return err
+		}
+
+		*fields = append(*fields, field)
+	}
+
+	return p.advance()
+}
+
+func (p *parser) parseInputObject(t *schema.InputObjectType) error {
+	directives, err := p.parseDirectives()
+	if err != nil {
+		return err
+	}
+
+	t.Directives = append(t.Directives, directives...)
+
+	if !p.is("{") {
+		return nil
+	}
+
+	fields, err := p.parseInputValues("{", "}")
+	if err != nil {
+		return err
+	}
+
+	t.Fields = append(t.Fields, fields...)
+
+	return nil
+}
+
+func (p *parser) parseEnum(t *schema.EnumType) error {
+	directives, err := p.parseDirectives()
+	if err != nil {
+		return err
+	}
+
+	t.Directives = append(t.Directives, directives...)
+
+	if ok, err := p.skip("{"); err != nil || !ok {
+		return err
+	}
+
+	for !p.is("}") {
+		description, err := p.parseDescription()
+		if err != nil {
+			return err
+		}
+
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+
+		value := &schema.EnumValue{Name: name, Description: description}
+
+		if value.Directives, err = p.parseDirectives(); err != nil {
+			return err
+		}
+
+		t.Values = append(t.Values, value)
+	}
+
+	return p.advance()
+}
+
+func (p *parser) parseUnion(t *schema.UnionType) error {
+	directives, err := p.parseDirectives()
+	if err != nil {
+		return err
+	}
+
+	t.Directives = append(t.Directives, directives...)
+
+	if ok, err := p.skip("="); err != nil || !ok {
+		return err
+	}
+
+	if _, err := p.skip("|"); err != nil {
+		return err
+	}
+
+	for {
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+
+		t.Members = append(t.Members, name)
+
+		if ok, err := p.skip("|"); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+	}
+}
+
+func (p *parser) parseDirectiveDefinition(description string) error {
+	if err := p.expect("@"); err != nil {
+		return err
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return err
+	}
+
+	directive := &schema.Directive{Name: name, Description: description}
+
+	if p.is("(") {
+		if directive.Arguments, err = p.parseInputValues("(", ")"); err != nil {
+			return err
+		}
+	}
+
+	if directive.Repeatable, err = p.skip("repeatable"); err != nil {
+		return err
+	}
+
+	if err := p.expect("on"); err != nil {
+		return err
+	}
+
+	if _, err := p.skip("|"); err != nil {
+		return err
+	}
+
+	for {
+		location, err := p.expectName()
+		if err != nil {
+			return err
+		}
+
+		directive.Locations = append(directive.Locations, location)
+
+		if ok, err := p.skip("|"); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+	}
+
+	p.schema.Directives = append(p.schema.Directives, directive)
+
+	return nil
+}
+
+// parseInputValues parses arguments or input fields between open and close.
+func (p *parser) parseInputValues(open string, close string) ([]*schema.InputValue, error) {
+	if err := p.expect(open); err != nil {
+		return nil, err
+	}
+
+	var values []*schema.InputValue
+
+	for !p.is(close) {
+		description, err := p.parseDescription()
+		if err != nil {
+			return nil, err
+		}
+
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+
+		value := &schema.InputValue{Name: name, Description: description}
+
+		if value.Type, err = p.parseTypeRef(); err != nil {
+			return nil, err
+		}
+
+		if ok, err := p.skip("="); err != nil {
+			return nil, err
+		} else if ok {
+			defaultValue, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+
+			value.DefaultValue = &defaultValue
+		}
+
+		if value.Directives, err = p.parseDirectives(); err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, p.advance()
+}
+
+func (p *parser) parseTypeRef() (*schema.TypeRef, error) {
+	var ref *schema.TypeRef
+
+	if ok, err := p.skip("["); err != nil {
+		return nil, err
+	} else if ok {
+		ofType, err := p.parseTypeRef()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+
+		ref = schema.ListOf(ofType)
+	} else {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		ref = schema.Named(name)
+	}
+
+	if ok, err := p.skip("!"); err != nil {
+		return nil, err
+	} else if ok {
+		ref = schema.NonNullOf(ref)
+	}
+
+	return ref, nil
+}
+
+// parseDirectives parses applied directives, i.e. @deprecated(reason: "...").
+func (p *parser) parseDirectives() ([]*schema.AppliedDirective, error) {
+	var directives []*schema.AppliedDirective
+
+	for p.is("@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		directive := &schema.AppliedDirective{Name: name}
+
+		if p.is("(") {
+			if directive.Arguments, err = p.parseArguments(); err != nil {
+				return nil, err
+			}
+		}
+
+		directives = append(directives, directive)
+	}
+
+	return directives, nil
+}
+
+func (p *parser) parseArguments() ([]*schema.Argument, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var arguments []*schema.Argument
+
+	for !p.is(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		arguments = append(arguments, &schema.Argument{Name: name, Value: value})
+	}
+
+	return arguments, p.advance()
+}
+
+// parseValue parses a value and returns it printed in a canonical form, so that
+// default values can be compared regardless of how they were formatted.
+func (p *parser) parseValue() (string, error) {
+	switch {
+	case p.is("$"):
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+
+		name, err := p.expectName()
+
+		return "$" + name, err
+	case p.is("["):
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+
+		var items []string
+
+		for !p.is("]") {
+			if p.token.kind == tokenEOF {
+				return "", p.errorf("expected ']', found end of file")
+			}
+
+			item, err := p.parseValue()
+			if err != nil {
+				return "", err
+			}
+
+			items = append(items, item)
+		}
+
+		return "[" + strings.Join(items, ", ") + "]", p.advance()
+	case p.is("{"):
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+
+		var fields []string
+
+		for !p.is("}") {
+			name, err := p.expectName()
+			if err != nil {
+				return "", err
+			}
+
+			if err := p.expect(":"); err != nil {
+				return "", err
+			}
+
+			value, err := p.parseValue()
+			if err != nil {
+				return "", err
+			}
+
+			fields = append(fields, name+": "+value)
+		}
+
+		return "{" + strings.Join(fields, ", ") + "}", p.advance()
+	case p.token.kind == tokenString:
+		value := fmt.Sprintf("%q", p.token.value)
+
+		return value, p.advance()
+	case p.token.kind == tokenName || p.token.kind == tokenNumber:
+		value := p.token.value
+
+		return value, p.advance()
+	default:
+		return "", p.errorf("expected a value, found %s", p.describeToken())
+	}
+}
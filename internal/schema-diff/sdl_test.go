@@ -0,0 +1,122 @@
+package schemadiff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	schemadiff "github.com/warpspeed-cloud/graphql-schema-generator/internal/schema-diff"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+func TestParse(t *testing.T) {
+	parsed, err := schemadiff.Parse(`
+# A comment
+extend schema @link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@key"])
+
+schema { query: Query }
+
+"""
+  A user.
+
+  Users sign in.
+"""
+type User implements & Node @key(fields: "id") {
+  "The ID"
+  id: ID!
+  projects(first: Int = 10, filter: ProjectFilter = {archived: false, tags: ["a", "b"]}): [Project!]!
+}
+
+extend type User {
+  email: String
+}
+
+input ProjectFilter {
+  archived: Boolean
+}
+
+union Result = User
+
+scalar Date @specifiedBy(url: "https://example.com/é")
+
+directive @key(fields: String!) repeatable on OBJECT | INTERFACE
+`)
+	assert.NoError(t, err)
+
+	user := parsed.Type("User").(*schema.ObjectType)
+	assert.Equal(t, "A user.\n\nUsers sign in.", user.Description)
+	assert.Equal(t, []string{"Node"}, user.Interfaces)
+	assert.Equal(t, []*schema.AppliedDirective{{Name: "key", Arguments: []*schema.Argument{{Name: "fields", Value: `"id"`}}}}, user.Directives)
+	assert.Len(t, user.Fields, 3)
+	assert.Equal(t, "The ID", user.Fields[0].Description)
+	assert.Equal(t, "[Project!]!", user.Fields[1].Type.String())
+	assert.Equal(t, "10", *user.Fields[1].Arguments[0].DefaultValue)
+	assert.Equal(t, `{archived: false, tags: ["a", "b"]}`, *user.Fields[1].Arguments[1].DefaultValue)
+	assert.Equal(t, "email", user.Fields[2].Name)
+
+	assert.Equal(t, "link", parsed.ExtensionDirectives[0].Name)
+	assert.Equal(t, `["@key"]`, parsed.ExtensionDirectives[0].Arguments[1].Value)
+
+	assert.Equal(t, schema.KindInputObject, parsed.Type("ProjectFilter").TypeKind())
+	assert.Equal(t, []string{"User"}, parsed.Type("Result").(*schema.UnionType).Members)
+	assert.Equal(t, schema.KindScalar, parsed.Type("Date").TypeKind())
+	assert.Equal(t, &schema.Directive{
+		Name:       "key",
+		Arguments:  []*schema.InputValue{{Name: "fields", Type: schema.NonNullOf(schema.Named("String"))}},
+		Locations:  []string{"OBJECT", "INTERFACE"},
+		Repeatable: true,
+	}, parsed.Directive("key"))
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		sdl  string
+		err  string
+	}{
+		{
+			name: "Unknown definition",
+			sdl:  "type User { id: ID }\nquery { user }",
+			err:  "syntax error at 2:1: unexpected 'query'",
+		},
+		{
+			name: "Missing type",
+			sdl:  "type User {\n  id:\n}",
+			err:  "syntax error at 3:1: expected a name, found '}'",
+		},
+		{
+			name: "Unterminated type",
+			sdl:  "type User {\n  id: ID",
+			err:  "syntax error at 2:9: expected a name, found end of file",
+		},
+		{
+			name: "Unterminated string",
+			sdl:  "\"A user\ntype User { id: ID }",
+			err:  "syntax error at 1:1: unterminated string",
+		},
+		{
+			name: "Type defined twice",
+			sdl:  "type User { id: ID }\ntype User { name: String }",
+			err:  "syntax error at 2:6: type 'User' is defined more than once",
+		},
+		{
+			name: "Extension of another kind",
+			sdl:  "type User { id: ID }\nextend enum User { ADMIN }",
+			err:  "syntax error at 2:13: 'User' is not an enum",
+		},
+		{
+			name: "Unexpected character",
+			sdl:  "type User { id: ID% }",
+			err:  "syntax error at 1:19: unexpected character '%'",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := schemadiff.Parse(test.sdl)
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
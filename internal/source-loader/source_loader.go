@@ -0,0 +1,159 @@
+package sourceloader
+
+import (
+	"bytes"
+	"fmt"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Packages are Go packages that were type checked from source.
+type Packages []*types.Package
+
+// Load resolves package patterns, i.e. ./models/..., with the go tool from the
+// given directory and type checks every matched package from source.
+func Load(dir string, patterns ...string) (Packages, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	importPaths, err := list(dir, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	imp, ok := importer.ForCompiler(token.NewFileSet(), "source", nil).(types.ImporterFrom)
+	if !ok {
+		return nil, fmt.Errorf("the source importer can't import relative to a directory")
+	}
+
+	packages := make(Packages, 0, len(importPaths))
+
+	for _, importPath := range importPaths {
+		pkg, err := imp.ImportFrom(importPath, dir, 0)
+		if err != nil {
+			return nil, fmt.Errorf("could not type check package '%s': %w", importPath, err)
+		}
+
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
+
+// list asks the go tool for the import paths matched by the patterns.
+func list(dir string, patterns []string) ([]string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("go", append([]string{"list", "-e", "-f", "{{if .Error}}{{.Error}}{{else}}{{.ImportPath}}{{end}}"}, patterns...)...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("could not list packages %s: %w: %s", patterns, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var importPaths []string
+
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		if strings.ContainsAny(line, " :") {
+			return nil, fmt.Errorf("could not list packages %s: %s", patterns, line)
+		}
+
+		importPaths = append(importPaths, line)
+	}
+
+	if len(importPaths) == 0 {
+		return nil, fmt.Errorf("no packages match %s", patterns)
+	}
+
+	return importPaths, nil
+}
+
+// Structs returns every exported struct type declared in the packages, sorted by name.
+func (p Packages) Structs() []*types.Named {
+	var structs []*types.Named
+
+	for _, pkg := range p {
+		scope := pkg.Scope()
+
+		for _, name := range scope.Names() {
+			if named := structType(scope.Lookup(name)); named != nil && named.Obj().Exported() {
+				structs = append(structs, named)
+			}
+		}
+	}
+
+	sort.SliceStable(structs, func(i, j int) bool {
+		return structs[i].Obj().Name() < structs[j].Obj().Name()
+	})
+
+	return structs
+}
+
+// LookupStruct finds a struct type declared in the packages. The name can be
+// qualified with the import path of its package, i.e. github.com/x/models.User,
+// which is required when more than one package declares a type by that name.
+func (p Packages) LookupStruct(name string) (*types.Named, error) {
+	pkgPath, typeName := "", name
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		pkgPath, typeName = name[:dot], name[dot+1:]
+	}
+
+	var found []*types.Named
+
+	for _, pkg := range p {
+		if pkgPath != "" && pkg.Path() != pkgPath {
+			continue
+		}
+
+		obj := pkg.Scope().Lookup(typeName)
+		if obj == nil {
+			continue
+		}
+
+		named := structType(obj)
+		if named == nil {
+			return nil, fmt.Errorf("'%s' in package '%s' is not a struct type", typeName, pkg.Path())
+		}
+
+		found = append(found, named)
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no struct type '%s' in the loaded packages", name)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("'%s' is declared in more than one package, qualify it with the package import path", name)
+	}
+}
+
+// structType returns the named type of an object if it is a non-generic struct type.
+func structType(obj types.Object) *types.Named {
+	typeName, ok := obj.(*types.TypeName)
+	if !ok || typeName.IsAlias() {
+		return nil
+	}
+
+	named, ok := typeName.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		return nil
+	}
+
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+
+	return named
+}
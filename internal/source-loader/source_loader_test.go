@@ -0,0 +1,59 @@
+package sourceloader_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sourceloader "github.com/warpspeed-cloud/graphql-schema-generator/internal/source-loader"
+)
+
+const testdata = "github.com/warpspeed-cloud/graphql-schema-generator/internal/source-loader/testdata/"
+
+func TestLoad(t *testing.T) {
+	packages, err := sourceloader.Load(".", "./testdata/auth", "./testdata/billing")
+	assert.NoError(t, err)
+
+	var paths []string
+	for _, pkg := range packages {
+		paths = append(paths, pkg.Path())
+	}
+
+	assert.Equal(t, []string{testdata + "auth", testdata + "billing"}, paths)
+
+	// Generic, unexported and non-struct types are left out.
+	var structs []string
+	for _, s := range packages.Structs() {
+		structs = append(structs, s.Obj().Pkg().Name()+"."+s.Obj().Name())
+	}
+
+	assert.Equal(t, []string{"auth.Account", "billing.Account", "billing.Invoice"}, structs)
+
+	_, err = sourceloader.Load(".", "./testdata/missing")
+	assert.Error(t, err)
+
+	// The go tool never matches testdata directories with a wildcard.
+	_, err = sourceloader.Load(".", "./testdata/...")
+	assert.EqualError(t, err, "no packages match [./testdata/...]")
+}
+
+func TestLookupStruct(t *testing.T) {
+	packages, err := sourceloader.Load(".", "./testdata/auth", "./testdata/billing")
+	assert.NoError(t, err)
+
+	invoice, err := packages.LookupStruct("Invoice")
+	assert.NoError(t, err)
+	assert.Equal(t, testdata+"billing.Invoice", invoice.String())
+
+	account, err := packages.LookupStruct(testdata + "auth.Account")
+	assert.NoError(t, err)
+	assert.Equal(t, testdata+"auth.Account", account.String())
+
+	_, err = packages.LookupStruct("Account")
+	assert.EqualError(t, err, "'Account' is declared in more than one package, qualify it with the package import path")
+
+	_, err = packages.LookupStruct("Currency")
+	assert.EqualError(t, err, "'Currency' in package '"+testdata+"billing' is not a struct type")
+
+	_, err = packages.LookupStruct("Order")
+	assert.EqualError(t, err, "no struct type 'Order' in the loaded packages")
+}
@@ -0,0 +1,5 @@
+package auth
+
+type Account struct {
+	Username string
+}
@@ -0,0 +1,21 @@
+package billing
+
+type Account struct {
+	IBAN string
+}
+
+type Invoice struct {
+	Account Account
+}
+
+type Currency string
+
+type Page[T any] struct {
+	Items []T
+}
+
+type invoiceLine struct {
+	Amount int
+}
+
+var _ = invoiceLine{}
@@ -0,0 +1,305 @@
+package textdiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Number of unchanged lines shown around each change.
+const contextLines = 3
+
+type operation int
+
+const (
+	equal operation = iota
+	insert
+	remove
+)
+
+type edit struct {
+	operation operation
+	line      string
+}
+
+// Unified returns a unified diff of two texts, line by line, or an empty string
+// when they are equal. The names are shown in the ---/+++ header of the diff.
+func Unified(fromName string, toName string, from string, to string) string {
+	if from == to {
+		return ""
+	}
+
+	edits := diffLines(splitLines(from), splitLines(to))
+
+	out := &strings.Builder{}
+	out.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", fromName, toName))
+
+	for _, h := range hunks(edits) {
+		h.write(out)
+	}
+
+	return out.String()
+}
+
+// splitLines splits text into lines that keep their line ending, so
+// that a missing newline at the end of the text shows up in the diff.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines finds the shortest edit script turning a into b with Myers' algorithm, in
+// linear space: rather than keeping the state of every round to walk the edits back, the
+// middle snake of the script splits the texts in two halves that are diffed on their own.
+func diffLines(a []string, b []string) []edit {
+	edits := appendDiff(make([]edit, 0, len(a)+len(b)), a, b)
+
+	// Removed lines come before the lines inserted in their place, as in the output of diff -u.
+	for start := 0; start < len(edits); start++ {
+		if edits[start].operation == equal {
+			continue
+		}
+
+		end := start
+		for end < len(edits) && edits[end].operation != equal {
+			end++
+		}
+
+		sort.SliceStable(edits[start:end], func(i, j int) bool {
+			return edits[start+i].operation == remove && edits[start+j].operation == insert
+		})
+
+		start = end
+	}
+
+	return edits
+}
+
+// appendDiff appends the shortest edit script turning a into b to edits.
+func appendDiff(edits []edit, a []string, b []string) []edit {
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		edits = append(edits, edit{equal, a[0]})
+		a, b = a[1:], b[1:]
+	}
+
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			edits = append(edits, edit{insert, line})
+		}
+	case len(b) == 0:
+		for _, line := range a {
+			edits = append(edits, edit{remove, line})
+		}
+	default:
+		// As the texts start and end with different lines, the script has at least two
+		// edits, so both halves are shorter scripts.
+		x, y, u, v := middleSnake(a, b)
+
+		edits = appendDiff(edits, a[:x], b[:y])
+		for _, line := range a[x:u] {
+			edits = append(edits, edit{equal, line})
+		}
+		edits = appendDiff(edits, a[u:], b[v:])
+	}
+
+	for _, line := range common {
+		edits = append(edits, edit{equal, line})
+	}
+
+	return edits
+}
+
+// middleSnake returns the run of equal lines, from (x, y) to (u, v), that the shortest edit
+// script turning a into b goes through halfway. It is found by looking for the script from
+// both ends at once, until the paths from the start and from the end overlap.
+func middleSnake(a []string, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+
+	// The furthest x reached on each diagonal k = x - y from the start, and on each diagonal
+	// of the reversed texts from the end, where the diagonal k is delta - k.
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+
+			y = x - k
+			u, v = x, y
+
+			for u < n && v < m && a[u] == b[v] {
+				u++
+				v++
+			}
+
+			forward[offset+k] = u
+
+			if c := delta - k; odd && c >= -(d-1) && c <= d-1 && u+backward[offset+c] >= n {
+				return x, y, u, v
+			}
+		}
+
+		for c := -d; c <= d; c += 2 {
+			if c == -d || (c != d && backward[offset+c-1] < backward[offset+c+1]) {
+				x = backward[offset+c+1]
+			} else {
+				x = backward[offset+c-1] + 1
+			}
+
+			y = x - c
+			u, v = x, y
+
+			for u < n && v < m && a[n-1-u] == b[m-1-v] {
+				u++
+				v++
+			}
+
+			backward[offset+c] = u
+
+			if k := delta - c; !odd && k >= -d && k <= d && forward[offset+k]+u >= n {
+				return n - u, m - v, n - x, m - y
+			}
+		}
+	}
+
+	panic("the paths from both ends of the texts don't overlap")
+}
+
+// hunk is a run of edits with the line it starts at in both texts.
+type hunk struct {
+	fromLine int
+	toLine   int
+	edits    []edit
+}
+
+// hunks groups the changes with their surrounding context, changes
+// separated by less than twice the context share a hunk.
+func hunks(edits []edit) []hunk {
+	var (
+		result   []hunk
+		fromLine = 1
+		toLine   = 1
+		start    = -1
+		end      = -1
+		startAt  [2]int
+	)
+
+	flush := func() {
+		if start >= 0 {
+			last := end + contextLines
+			if last >= len(edits) {
+				last = len(edits) - 1
+			}
+
+			result = append(result, hunk{fromLine: startAt[0], toLine: startAt[1], edits: edits[start : last+1]})
+		}
+	}
+
+	lines := make([][2]int, len(edits))
+
+	for i, e := range edits {
+		lines[i] = [2]int{fromLine, toLine}
+
+		if e.operation != insert {
+			fromLine++
+		}
+
+		if e.operation != remove {
+			toLine++
+		}
+	}
+
+	for i, e := range edits {
+		if e.operation == equal {
+			continue
+		}
+
+		if start >= 0 && i-end > 2*contextLines {
+			flush()
+			start = -1
+		}
+
+		if start < 0 {
+			start = i - contextLines
+			if start < 0 {
+				start = 0
+			}
+
+			startAt = lines[start]
+		}
+
+		end = i
+	}
+
+	flush()
+
+	return result
+}
+
+func (h hunk) write(out *strings.Builder) {
+	fromCount, toCount := 0, 0
+
+	for _, e := range h.edits {
+		if e.operation != insert {
+			fromCount++
+		}
+
+		if e.operation != remove {
+			toCount++
+		}
+	}
+
+	out.WriteString(fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(h.fromLine, fromCount), hunkRange(h.toLine, toCount)))
+
+	for _, e := range h.edits {
+		switch e.operation {
+		case equal:
+			out.WriteString(" ")
+		case insert:
+			out.WriteString("+")
+		case remove:
+			out.WriteString("-")
+		}
+
+		out.WriteString(e.line)
+
+		if !strings.HasSuffix(e.line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange prints the start and length of a hunk in one of the texts, an
+// empty range starts at the line before it as in the output of diff -u.
+func hunkRange(line int, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", line-1)
+	case 1:
+		return fmt.Sprintf("%d", line)
+	default:
+		return fmt.Sprintf("%d,%d", line, count)
+	}
+}
@@ -0,0 +1,228 @@
+package textdiff_test
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	textdiff "github.com/warpspeed-cloud/graphql-schema-generator/internal/text-diff"
+)
+
+func lines(from int, to int) string {
+	out := &strings.Builder{}
+	for i := from; i <= to; i++ {
+		out.WriteString("line " + string(rune('a'+i)) + "\n")
+	}
+
+	return out.String()
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected string
+	}{
+		{
+			name:     "Equal",
+			from:     "type User {\n}\n",
+			to:       "type User {\n}\n",
+			expected: "",
+		},
+		{
+			name: "Changed line",
+			from: "type User {\n  id: String!\n  name: String!\n}\n",
+			to:   "type User {\n  id: ID!\n  name: String!\n}\n",
+			expected: `--- a
++++ b
+@@ -1,4 +1,4 @@
+ type User {
+-  id: String!
++  id: ID!
+   name: String!
+ }
+`,
+		},
+		{
+			name: "From empty",
+			from: "",
+			to:   "type User {\n}\n",
+			expected: `--- a
++++ b
+@@ -0,0 +1,2 @@
++type User {
++}
+`,
+		},
+		{
+			name: "Missing newline at the end",
+			from: "scalar JSON",
+			to:   "scalar JSON\n",
+			expected: `--- a
++++ b
+@@ -1 +1 @@
+-scalar JSON
+\ No newline at end of file
++scalar JSON
+`,
+		},
+		{
+			name: "Separate hunks",
+			from: lines(0, 19),
+			to:   strings.Replace(strings.Replace(lines(0, 19), "line b\n", "", 1), "line r\n", "line R\n", 1),
+			expected: `--- a
++++ b
+@@ -1,5 +1,4 @@
+ line a
+-line b
+ line c
+ line d
+ line e
+@@ -15,6 +14,6 @@
+ line o
+ line p
+ line q
+-line r
++line R
+ line s
+ line t
+`,
+		},
+		{
+			name: "Close changes share a hunk",
+			from: lines(0, 9),
+			to:   strings.Replace(strings.Replace(lines(0, 9), "line c\n", "line C\n", 1), "line h\n", "line H\n", 1),
+			expected: `--- a
++++ b
+@@ -1,10 +1,10 @@
+ line a
+ line b
+-line c
++line C
+ line d
+ line e
+ line f
+ line g
+-line h
++line H
+ line i
+ line j
+`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.expected, textdiff.Unified("a", "b", test.from, test.to))
+		})
+	}
+}
+
+// patch applies a unified diff to a text, and returns the text and the number of lines changed.
+func patch(t *testing.T, from string, diff string) (string, int) {
+	t.Helper()
+
+	lines := strings.SplitAfter(from, "\n")
+	out := &strings.Builder{}
+	next, changed := 0, 0
+
+	for _, line := range strings.SplitAfter(diff, "\n")[2:] {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			var start, count int
+			if _, err := fmt.Sscanf(line, "@@ -%d,%d", &start, &count); err != nil || count > 0 {
+				start--
+			}
+
+			for ; next < start; next++ {
+				out.WriteString(lines[next])
+			}
+		case strings.HasPrefix(line, "+"):
+			out.WriteString(line[1:])
+			changed++
+		case strings.HasPrefix(line, "-"), strings.HasPrefix(line, " "):
+			assert.Equal(t, lines[next], line[1:])
+
+			if line[0] == ' ' {
+				out.WriteString(line[1:])
+			} else {
+				changed++
+			}
+
+			next++
+		}
+	}
+
+	for ; next < len(lines); next++ {
+		out.WriteString(lines[next])
+	}
+
+	return out.String(), changed
+}
+
+// longestCommon returns the number of lines of the longest common subsequence of two texts.
+func longestCommon(a []string, b []string) int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] > lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	return lengths[0][0]
+}
+
+func TestUnified_Shortest(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	text := func() (string, []string) {
+		lines := make([]string, random.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a'+random.Intn(4))) + "\n"
+		}
+
+		return strings.Join(lines, ""), lines
+	}
+
+	// The diff turns one text into the other with as few lines changed as possible.
+	for i := 0; i < 500; i++ {
+		from, fromLines := text()
+		to, toLines := text()
+
+		patched, changed := patch(t, from, textdiff.Unified("a", "b", from, to))
+		if from == to {
+			continue
+		}
+
+		assert.Equal(t, to, patched)
+		assert.Equal(t, len(fromLines)+len(toLines)-2*longestCommon(fromLines, toLines), changed)
+	}
+}
+
+func TestUnified_Large(t *testing.T) {
+	from, to := &strings.Builder{}, &strings.Builder{}
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(from, "from %d\n", i)
+		fmt.Fprintf(to, "to %d\n", i)
+	}
+
+	// Texts without a line in common are diffed without keeping a state per edit.
+	diff := textdiff.Unified("a", "b", from.String(), to.String())
+	assert.Equal(t, 10000+3, strings.Count(diff, "\n"))
+}
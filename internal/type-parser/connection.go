@@ -0,0 +1,81 @@
+package typeparser
+
+import (
+	"fmt"
+
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+const (
+	connectionTemplate = "%sConnection"
+	edgeTemplate       = "%sEdge"
+	pageInfoName       = "PageInfo"
+)
+
+// connectionArguments are the arguments of a field that is a Relay connection.
+func connectionArguments() []*schema.InputValue {
+	return []*schema.InputValue{
+		{Name: "first", Type: schema.Named("Int")},
+		{Name: "after", Type: schema.Named("String")},
+		{Name: "last", Type: schema.Named("Int")},
+		{Name: "before", Type: schema.Named("String")},
+	}
+}
+
+// connection turns a slice field tagged with connection into a Relay connection,
+// i.e. projects(first: Int, after: String, last: Int, before: String): ProjectConnection!,
+// and adds the connection and edge types of its elements the first time they are used.
+// Edges only exist for the elements there are so their node is never null, even for
+// a slice of pointers.
+func (b *schemaBuilder) connection(parent string, field TypeDescriptor, output *schema.Field) {
+	if !field.IsSlice {
+		panic(fmt.Sprintf("connection can only be used on slices, '%s.%s' is a '%s'", parent, *field.Name, output.Type))
+	}
+
+	name := output.Type.NamedType()
+	connectionName := fmt.Sprintf(connectionTemplate, name)
+	edgeName := fmt.Sprintf(edgeTemplate, name)
+
+	output.Arguments = connectionArguments()
+	output.Type = schema.Named(connectionName)
+
+	if !field.IsPointer {
+		output.Type = schema.NonNullOf(output.Type)
+	}
+
+	if b.connectionNames[connectionName] {
+		return
+	}
+
+	b.connectionNames[connectionName] = true
+
+	b.connections = append(b.connections,
+		&schema.ObjectType{
+			Name: connectionName,
+			Fields: []*schema.Field{
+				{Name: "edges", Type: schema.NonNullOf(schema.ListOf(schema.NonNullOf(schema.Named(edgeName))))},
+				{Name: "pageInfo", Type: schema.NonNullOf(schema.Named(pageInfoName))},
+			},
+		},
+		&schema.ObjectType{
+			Name: edgeName,
+			Fields: []*schema.Field{
+				{Name: "cursor", Type: schema.NonNullOf(schema.Named("String"))},
+				{Name: "node", Type: schema.NonNullOf(schema.Named(name))},
+			},
+		},
+	)
+}
+
+// pageInfo is the type shared by every connection to page through its edges.
+func pageInfo() *schema.ObjectType {
+	return &schema.ObjectType{
+		Name: pageInfoName,
+		Fields: []*schema.Field{
+			{Name: "hasNextPage", Type: schema.NonNullOf(schema.Named("Boolean"))},
+			{Name: "hasPreviousPage", Type: schema.NonNullOf(schema.Named("Boolean"))},
+			{Name: "startCursor", Type: schema.Named("String")},
+			{Name: "endCursor", Type: schema.Named("String")},
+		},
+	}
+}
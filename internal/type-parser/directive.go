@@ -0,0 +1,21 @@
+package typeparser
+
+import "github.com/warpspeed-cloud/graphql-schema-generator/schema"
+
+// addDirectives adds the registered directive definitions to a schema, along with the custom
+// scalars only their arguments refer to. Validate reports the definitions that are invalid.
+func (b *schemaBuilder) addDirectives(s *schema.Schema) {
+	for _, directive := range b.options.Directives {
+		for _, argument := range directive.Arguments {
+			name := argument.Type.NamedType()
+
+			// A custom scalar only used by directives isn't declared yet.
+			if !schema.IsBuiltinScalar(name) && s.Type(name) == nil && b.isCustomScalar(name) {
+				s.Types = append(s.Types, &schema.ScalarType{Name: name})
+			}
+		}
+
+		definition := *directive
+		s.Directives = append(s.Directives, &definition)
+	}
+}
@@ -0,0 +1,151 @@
+package typeparser
+
+import (
+	"strconv"
+	"strings"
+
+	tagparser "github.com/warpspeed-cloud/graphql-schema-generator/internal/graphql-tag-parser"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+const (
+	// federationURL is the Apollo Federation specification subgraphs link to.
+	federationURL = "https://specs.apollo.dev/federation/v2.3"
+
+	entityUnionName = "_Entity"
+	anyScalarName   = "_Any"
+	serviceTypeName = "_Service"
+)
+
+// federationDirectives are the directives imported from the federation specification.
+var federationDirectives = []string{"key", "shareable", "external", "requires", "provides"}
+
+// typeFederationDirectives returns the @key and @shareable directives of a struct. Keys are
+// given with the key option of the blank field, i.e. key=id or key=[id, sku upc] for several
+// keys, and by tagging fields with key, which together make up one more key.
+func typeFederationDirectives(s Struct) []*schema.AppliedDirective {
+	tag := s.ParsedTag
+	if tag == nil {
+		tag = &tagparser.Tag{}
+	}
+
+	keys := append([]string{}, tag.Keys...)
+
+	var keyFields []string
+
+	if s.Fields != nil {
+		for _, field := range *s.Fields {
+			if field.IncludeInOutput && field.ParsedTag != nil && field.ParsedTag.Key {
+				keyFields = append(keyFields, *field.Name)
+			}
+		}
+	}
+
+	if len(keyFields) > 0 {
+		keys = append(keys, strings.Join(keyFields, " "))
+	}
+
+	directives := make([]*schema.AppliedDirective, 0, len(keys)+1)
+
+	for _, key := range keys {
+		directives = append(directives, fieldSetDirective("key", key))
+	}
+
+	if tag.Shareable {
+		directives = append(directives, &schema.AppliedDirective{Name: "shareable"})
+	}
+
+	if len(directives) == 0 {
+		return nil
+	}
+
+	return directives
+}
+
+// fieldFederationDirectives returns the federation directives given in the tag of a field,
+// the shareable and external flags and the requires and provides options, i.e. requires=weight.
+func fieldFederationDirectives(tag *tagparser.Tag) []*schema.AppliedDirective {
+	if tag == nil {
+		return nil
+	}
+
+	var directives []*schema.AppliedDirective
+
+	if tag.Shareable {
+		directives = append(directives, &schema.AppliedDirective{Name: "shareable"})
+	}
+
+	if tag.External {
+		directives = append(directives, &schema.AppliedDirective{Name: "external"})
+	}
+
+	if tag.Requires != "" {
+		directives = append(directives, fieldSetDirective("requires", tag.Requires))
+	}
+
+	if tag.Provides != "" {
+		directives = append(directives, fieldSetDirective("provides", tag.Provides))
+	}
+
+	return directives
+}
+
+func fieldSetDirective(name string, fields string) *schema.AppliedDirective {
+	return &schema.AppliedDirective{
+		Name:      name,
+		Arguments: []*schema.Argument{{Name: "fields", Value: strconv.Quote(fields)}},
+	}
+}
+
+// addFederation links a schema to the federation specification and adds the fields the
+// gateway queries a subgraph with: _service, and _entities when there are entities, the
+// types with a @key, along with the _Entity union of the entities and the _Any scalar.
+func addFederation(s *schema.Schema) {
+	imports := make([]string, len(federationDirectives))
+	for i, name := range federationDirectives {
+		imports[i] = strconv.Quote("@" + name)
+	}
+
+	s.ExtensionDirectives = append(s.ExtensionDirectives, &schema.AppliedDirective{
+		Name: "link",
+		Arguments: []*schema.Argument{
+			{Name: "url", Value: strconv.Quote(federationURL)},
+			{Name: "import", Value: "[" + strings.Join(imports, ", ") + "]"},
+		},
+	})
+
+	var entities []string
+
+	for _, t := range s.Types {
+		if object, ok := t.(*schema.ObjectType); ok && schema.FindDirective(object.Directives, "key") != nil {
+			entities = append(entities, object.Name)
+		}
+	}
+
+	query := queryType(s)
+
+	if len(entities) > 0 {
+		s.Types = append(s.Types,
+			&schema.UnionType{Name: entityUnionName, Members: entities},
+			&schema.ScalarType{Name: anyScalarName},
+		)
+
+		query.Fields = append(query.Fields, &schema.Field{
+			Name: "_entities",
+			Arguments: []*schema.InputValue{
+				{Name: "representations", Type: schema.NonNullOf(schema.ListOf(schema.NonNullOf(schema.Named(anyScalarName))))},
+			},
+			Type: schema.NonNullOf(schema.ListOf(schema.Named(entityUnionName))),
+		})
+	}
+
+	s.Types = append(s.Types, &schema.ObjectType{
+		Name:   serviceTypeName,
+		Fields: []*schema.Field{{Name: "sdl", Type: schema.NonNullOf(schema.Named("String"))}},
+	})
+
+	query.Fields = append(query.Fields, &schema.Field{
+		Name: "_service",
+		Type: schema.NonNullOf(schema.Named(serviceTypeName)),
+	})
+}
@@ -0,0 +1,228 @@
+package typeparser
+
+import (
+	"go/types"
+	"reflect"
+	"strings"
+)
+
+// goType is the part of reflect.Type the parser needs to discover types. It is
+// implemented for reflected types and for types loaded from source with go/types,
+// so that both are walked by exactly the same code.
+type goType interface {
+	Kind() reflect.Kind
+	Name() string
+	PkgPath() string
+	// PkgName returns the name of the package a named type is declared in, i.e. models.
+	PkgName() string
+	Elem() goType
+	Key() goType
+	NumField() int
+	Field(i int) goField
+	// HasMethod returns whether the type or a pointer to it has the method.
+	HasMethod(name string) bool
+	// Identity returns a comparable value that is the same for identical types.
+	Identity() any
+}
+
+// goField is the part of reflect.StructField the parser needs.
+type goField struct {
+	Name     string
+	Tag      reflect.StructTag
+	Type     goType
+	Exported bool
+}
+
+// reflectType is a goType backed by a reflect.Type.
+type reflectType struct {
+	reflect.Type
+}
+
+func (r reflectType) PkgName() string {
+	if r.Type.PkgPath() == "" {
+		return ""
+	}
+
+	// A named type is printed qualified by the name of its package, i.e. models.User.
+	name, _, _ := strings.Cut(r.Type.String(), ".")
+
+	return name
+}
+
+func (r reflectType) Elem() goType {
+	return reflectType{r.Type.Elem()}
+}
+
+func (r reflectType) Key() goType {
+	return reflectType{r.Type.Key()}
+}
+
+func (r reflectType) Field(i int) goField {
+	field := r.Type.Field(i)
+
+	return goField{
+		Name:     field.Name,
+		Tag:      field.Tag,
+		Type:     reflectType{field.Type},
+		Exported: field.IsExported(),
+	}
+}
+
+func (r reflectType) HasMethod(name string) bool {
+	_, ok := reflect.PtrTo(r.Type).MethodByName(name)
+
+	return ok
+}
+
+func (r reflectType) Identity() any {
+	return r.Type
+}
+
+// sourceType is a goType backed by a type checked from source.
+type sourceType struct {
+	types.Type
+}
+
+// basicKinds maps the basic go/types kinds to their reflect equivalent.
+var basicKinds = map[types.BasicKind]reflect.Kind{
+	types.Bool:          reflect.Bool,
+	types.Int:           reflect.Int,
+	types.Int8:          reflect.Int8,
+	types.Int16:         reflect.Int16,
+	types.Int32:         reflect.Int32,
+	types.Int64:         reflect.Int64,
+	types.Uint:          reflect.Uint,
+	types.Uint8:         reflect.Uint8,
+	types.Uint16:        reflect.Uint16,
+	types.Uint32:        reflect.Uint32,
+	types.Uint64:        reflect.Uint64,
+	types.Uintptr:       reflect.Uintptr,
+	types.Float32:       reflect.Float32,
+	types.Float64:       reflect.Float64,
+	types.Complex64:     reflect.Complex64,
+	types.Complex128:    reflect.Complex128,
+	types.String:        reflect.String,
+	types.UnsafePointer: reflect.UnsafePointer,
+}
+
+func (s sourceType) Kind() reflect.Kind {
+	switch u := s.Type.Underlying().(type) {
+	case *types.Basic:
+		return basicKinds[u.Kind()]
+	case *types.Pointer:
+		return reflect.Ptr
+	case *types.Slice:
+		return reflect.Slice
+	case *types.Array:
+		return reflect.Array
+	case *types.Map:
+		return reflect.Map
+	case *types.Struct:
+		return reflect.Struct
+	case *types.Chan:
+		return reflect.Chan
+	case *types.Signature:
+		return reflect.Func
+	default:
+		return reflect.Interface
+	}
+}
+
+// Name follows reflect, so a named type gives its name, including the type
+// arguments of a generic instantiation, and a predeclared type gives its keyword.
+func (s sourceType) Name() string {
+	switch t := s.Type.(type) {
+	case *types.Named:
+		name := t.Obj().Name()
+
+		if t.TypeArgs().Len() > 0 {
+			args := make([]string, t.TypeArgs().Len())
+			for i := range args {
+				args[i] = types.TypeString(t.TypeArgs().At(i), nil)
+			}
+
+			name += "[" + strings.Join(args, ",") + "]"
+		}
+
+		return name
+	case *types.Basic:
+		return t.Name()
+	default:
+		return ""
+	}
+}
+
+func (s sourceType) PkgPath() string {
+	if named, ok := s.Type.(*types.Named); ok && named.Obj().Pkg() != nil {
+		return named.Obj().Pkg().Path()
+	}
+
+	return ""
+}
+
+func (s sourceType) PkgName() string {
+	if named, ok := s.Type.(*types.Named); ok && named.Obj().Pkg() != nil {
+		return named.Obj().Pkg().Name()
+	}
+
+	return ""
+}
+
+func (s sourceType) Elem() goType {
+	switch u := s.Type.Underlying().(type) {
+	case *types.Pointer:
+		return sourceType{u.Elem()}
+	case *types.Slice:
+		return sourceType{u.Elem()}
+	case *types.Array:
+		return sourceType{u.Elem()}
+	case *types.Map:
+		return sourceType{u.Elem()}
+	case *types.Chan:
+		return sourceType{u.Elem()}
+	default:
+		panic("Elem of invalid type " + s.Type.String())
+	}
+}
+
+func (s sourceType) Key() goType {
+	if u, ok := s.Type.Underlying().(*types.Map); ok {
+		return sourceType{u.Key()}
+	}
+
+	panic("Key of non-map type " + s.Type.String())
+}
+
+func (s sourceType) structType() *types.Struct {
+	if u, ok := s.Type.Underlying().(*types.Struct); ok {
+		return u
+	}
+
+	panic("Field of non-struct type " + s.Type.String())
+}
+
+func (s sourceType) NumField() int {
+	return s.structType().NumFields()
+}
+
+func (s sourceType) Field(i int) goField {
+	structType := s.structType()
+	field := structType.Field(i)
+
+	return goField{
+		Name:     field.Name(),
+		Tag:      reflect.StructTag(structType.Tag(i)),
+		Type:     sourceType{field.Type()},
+		Exported: field.Exported(),
+	}
+}
+
+func (s sourceType) HasMethod(name string) bool {
+	return types.NewMethodSet(types.NewPointer(s.Type)).Lookup(nil, name) != nil
+}
+
+// Identity is the type qualified by the path of its packages, go/types doesn't
+// guarantee identical types are the same value, i.e. two instantiations of a generic type.
+func (s sourceType) Identity() any {
+	return types.TypeString(s.Type, nil)
+}
@@ -0,0 +1,264 @@
+package typeparser
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// jsonSchemaDialect is the JSON Schema draft documents are written in.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonTypes maps the Go kinds found by the parser, and the built-in scalars
+// they can be given as, to their JSON Schema type.
+var jsonTypes = map[string]string{
+	"bool":    "boolean",
+	"string":  "string",
+	"int":     "integer",
+	"int8":    "integer",
+	"int16":   "integer",
+	"int32":   "integer",
+	"int64":   "integer",
+	"uint":    "integer",
+	"uint8":   "integer",
+	"uint16":  "integer",
+	"uint32":  "integer",
+	"uint64":  "integer",
+	"float32": "number",
+	"float64": "number",
+	"Boolean": "boolean",
+	"String":  "string",
+	"Int":     "integer",
+	"Float":   "number",
+}
+
+// JSONSchema is a JSON Schema document, or a schema inside one. Type is
+// either a type name, i.e. string, or a list of them, i.e. [string, null].
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 any                    `json:"type,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Deprecated           bool                   `json:"deprecated,omitempty"`
+	Enum                 []any                  `json:"enum,omitempty"`
+	Properties           JSONProperties         `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*JSONSchema `json:"$defs,omitempty"`
+}
+
+// JSONProperty is a property of an object schema.
+type JSONProperty struct {
+	Name   string
+	Schema *JSONSchema
+}
+
+// JSONProperties are the properties of an object schema, kept in the order of the fields.
+type JSONProperties []JSONProperty
+
+func (p JSONProperties) MarshalJSON() ([]byte, error) {
+	out := &bytes.Buffer{}
+	out.WriteString("{")
+
+	for i, property := range p {
+		if i > 0 {
+			out.WriteString(",")
+		}
+
+		name, err := json.Marshal(property.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(property.Schema)
+		if err != nil {
+			return nil, err
+		}
+
+		out.Write(name)
+		out.WriteString(":")
+		out.Write(value)
+	}
+
+	out.WriteString("}")
+
+	return out.Bytes(), nil
+}
+
+// JSONSchema returns the types found so far as a JSON Schema document with a definition
+// in $defs for every struct, enum and custom scalar, and every map whose values are the
+// map itself, i.e. type Tree map[string]Tree. Other maps are written inline. It describes the JSON the types are
+// encoded as: properties are named after the json tags and the fields that are neither
+// pointers nor tagged with omitempty are required. Slices and maps can be nil so they are
+// nullable. Custom scalars can't be described so their definitions are empty.
+func (t *TypeParser) JSONSchema() *JSONSchema {
+	t = t.Snapshot()
+
+	b := &jsonSchemaBuilder{
+		maps:    map[string]Map{},
+		enums:   map[string]bool{},
+		structs: map[string]bool{},
+		defs:    map[string]*JSONSchema{},
+	}
+
+	if t.Maps != nil {
+		for _, m := range *t.Maps {
+			b.maps[m.Name] = m
+		}
+	}
+
+	b.recursive = recursiveMaps(b.maps)
+
+	if t.Enums != nil {
+		for _, e := range *t.Enums {
+			b.enums[e.Name] = true
+			b.defs[e.Name] = enumJSONSchema(e)
+		}
+	}
+
+	if t.Structs != nil {
+		for _, s := range *t.Structs {
+			b.structs[s.Name] = true
+		}
+
+		for _, s := range *t.Structs {
+			b.defs[s.Name] = b.structSchema(s)
+		}
+	}
+
+	for name := range b.recursive {
+		b.defs[name] = &JSONSchema{Type: "object", AdditionalProperties: b.fieldSchema(b.maps[name].Val)}
+	}
+
+	return &JSONSchema{Schema: jsonSchemaDialect, Defs: b.defs}
+}
+
+// jsonSchemaBuilder turns the types found by a parser into JSON Schema definitions.
+type jsonSchemaBuilder struct {
+	// Maps, enums and structs found by the parser, by name.
+	maps    map[string]Map
+	enums   map[string]bool
+	structs map[string]bool
+	// Names of the maps that are defined as they refer to themselves.
+	recursive map[string]bool
+
+	defs map[string]*JSONSchema
+}
+
+func (b *jsonSchemaBuilder) structSchema(s Struct) *JSONSchema {
+	object := &JSONSchema{
+		Type:        "object",
+		Description: stringValue(s.Description),
+		Properties:  JSONProperties{},
+	}
+
+	if s.Fields == nil {
+		return object
+	}
+
+	for _, field := range *s.Fields {
+		if !field.IncludeInOutput {
+			continue
+		}
+
+		name := field.jsonName()
+
+		property := b.fieldSchema(field)
+		property.Description = stringValue(field.GetDescription())
+		property.Deprecated = field.GetDeprecation() != nil
+
+		object.Properties = append(object.Properties, JSONProperty{Name: name, Schema: property})
+
+		if !field.IsPointer && !field.OmitEmpty {
+			object.Required = append(object.Required, name)
+		}
+	}
+
+	return object
+}
+
+// fieldSchema returns the schema of a field or of the values of a map.
+func (b *jsonSchemaBuilder) fieldSchema(field TypeDescriptor) *JSONSchema {
+	m, isMap := b.maps[field.Type]
+
+	var value *JSONSchema
+
+	switch {
+	case isMap && b.recursive[field.Type]:
+		value = &JSONSchema{Ref: "#/$defs/" + field.Type}
+	case isMap:
+		value = &JSONSchema{Type: "object", AdditionalProperties: b.fieldSchema(m.Val)}
+	case field.IsMap:
+		// A map that wasn't found by its name can still be described as an object.
+		value = &JSONSchema{Type: "object"}
+		isMap = true
+	default:
+		value = b.namedSchema(field.Type)
+	}
+
+	if field.IsSlice {
+		if field.IsSliceOfPointers {
+			value = nullableJSONSchema(value)
+		}
+
+		value = &JSONSchema{Type: "array", Items: value}
+	}
+
+	// Slices and maps are nil when they are empty, which is encoded as null.
+	if field.IsPointer || field.IsSlice || isMap {
+		value = nullableJSONSchema(value)
+	}
+
+	return value
+}
+
+// namedSchema returns the schema of a type by its name, a reference to its definition
+// unless it is a JSON type. IDs can be either strings or integers.
+func (b *jsonSchemaBuilder) namedSchema(name string) *JSONSchema {
+	if jsonType, ok := jsonTypes[name]; ok {
+		return &JSONSchema{Type: jsonType}
+	}
+
+	if name == "ID" {
+		return &JSONSchema{Type: []string{"string", "integer"}}
+	}
+
+	if !b.structs[name] && !b.enums[name] {
+		if _, ok := b.defs[name]; !ok {
+			b.defs[name] = &JSONSchema{}
+		}
+	}
+
+	return &JSONSchema{Ref: "#/$defs/" + name}
+}
+
+func enumJSONSchema(e Enum) *JSONSchema {
+	enum := &JSONSchema{Description: stringValue(e.Description)}
+
+	for _, value := range e.Values {
+		if value.Value == nil {
+			enum.Enum = append(enum.Enum, value.Key)
+		} else {
+			enum.Enum = append(enum.Enum, value.Value)
+		}
+	}
+
+	return enum
+}
+
+// nullableJSONSchema returns a schema that also allows null.
+func nullableJSONSchema(s *JSONSchema) *JSONSchema {
+	switch t := s.Type.(type) {
+	case string:
+		s.Type = []string{t, "null"}
+
+		return s
+	case []string:
+		s.Type = append(t, "null")
+
+		return s
+	}
+
+	return &JSONSchema{AnyOf: []*JSONSchema{s, {Type: "null"}}}
+}
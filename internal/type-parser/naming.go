@@ -0,0 +1,224 @@
+package typeparser
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+// FieldNaming is a naming convention for field names.
+type FieldNaming string
+
+const (
+	// FieldNamingJSON keeps the name from the json tag, or the Go field name without one.
+	FieldNamingJSON FieldNaming = ""
+	// FieldNamingCamelCase converts names to camelCase, i.e. ThumbURL becomes thumbUrl.
+	FieldNamingCamelCase FieldNaming = "camelCase"
+	// FieldNamingSnakeCase converts names to snake_case, i.e. ThumbURL becomes thumb_url.
+	FieldNamingSnakeCase FieldNaming = "snake_case"
+)
+
+// FieldNamings are the field naming conventions that can be configured.
+var FieldNamings = []FieldNaming{FieldNamingJSON, FieldNamingCamelCase, FieldNamingSnakeCase}
+
+func (n FieldNaming) convert(name string) string {
+	switch n {
+	case FieldNamingCamelCase:
+		words := splitWords(name)
+		for i, word := range words {
+			if i == 0 {
+				words[i] = strings.ToLower(word)
+			} else {
+				words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+			}
+		}
+
+		return strings.Join(words, "")
+	case FieldNamingSnakeCase:
+		return strings.ToLower(strings.Join(splitWords(name), "_"))
+	default:
+		return name
+	}
+}
+
+// splitWords splits a name into words on underscores, dashes, spaces and changes of
+// case, keeping initialisms together, i.e. ThumbURLPath is Thumb, URL and Path.
+func splitWords(name string) []string {
+	var (
+		words   []string
+		current []rune
+	)
+
+	runes := []rune(name)
+
+	for i, r := range runes {
+		if r == '_' || r == '-' || r == ' ' {
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+
+			continue
+		}
+
+		if len(current) > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				words = append(words, string(current))
+				current = nil
+			}
+		}
+
+		current = append(current, r)
+	}
+
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+
+	return words
+}
+
+// DefaultGenericNaming is the template generic instantiations are named with by default,
+// the names of the type arguments followed by the name of the generic type, i.e. Page[User]
+// is named UserPage.
+const DefaultGenericNaming GenericNaming = `{{join .Args ""}}{{.Type}}`
+
+// GenericNaming is a text/template naming the instantiations of generic types, as the Go
+// name of Page[github.com/x/models.User] isn't a valid GraphQL name. It is given the name of
+// the generic type as .Type and the names of its type arguments as .Args, which can be joined
+// with join, i.e. {{.Type}}_{{join .Args "_"}} names Page[User] Page_User. The type arguments
+// are named as they are in the schema, with their alias or prefix. An empty template is
+// DefaultGenericNaming.
+type GenericNaming string
+
+// genericName is what a GenericNaming template is executed with.
+type genericName struct {
+	Type string
+	Args []string
+}
+
+// Validate returns an error when the template can't be parsed or doesn't give a valid GraphQL name.
+func (n GenericNaming) Validate() error {
+	_, err := n.name("Page", []string{"User"})
+
+	return err
+}
+
+// name names an instantiation of a generic type from the names of its type arguments.
+func (n GenericNaming) name(typeName string, args []string) (string, error) {
+	if n == "" {
+		n = DefaultGenericNaming
+	}
+
+	tmpl, err := template.New("generic").Funcs(template.FuncMap{"join": strings.Join}).Parse(string(n))
+	if err != nil {
+		return "", fmt.Errorf("invalid generic naming template: %w", err)
+	}
+
+	var name strings.Builder
+	if err := tmpl.Execute(&name, genericName{Type: typeName, Args: args}); err != nil {
+		return "", fmt.Errorf("invalid generic naming template: %w", err)
+	}
+
+	if !schema.IsValidName(name.String()) {
+		return "", fmt.Errorf("the generic naming template names %s[%s] '%s', which is not a valid GraphQL name",
+			typeName, strings.Join(args, ","), name.String())
+	}
+
+	return name.String(), nil
+}
+
+// splitTypeArgs splits the name of a generic instantiation into the name of the generic
+// type and its type arguments, as they are written by reflect, i.e. Pair[string,[]int] is
+// Pair with string and []int. It returns false for a type that isn't generic.
+func splitTypeArgs(name string) (string, []string, bool) {
+	open := strings.Index(name, "[")
+	if open <= 0 || !strings.HasSuffix(name, "]") {
+		return name, nil, false
+	}
+
+	var (
+		args  []string
+		depth int
+		start = open + 1
+	)
+
+	for i := start; i < len(name)-1; i++ {
+		switch name[i] {
+		case '[', '{', '(':
+			depth++
+		case ']', '}', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(name[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	args = append(args, strings.TrimSpace(name[start:len(name)-1]))
+
+	return name[:open], args, true
+}
+
+// exportedName capitalizes a name and drops the characters that can't be in a GraphQL name,
+// i.e. string is String and interface {} is Interface.
+func exportedName(name string) string {
+	var b strings.Builder
+
+	for _, r := range name {
+		if r == '_' || (r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))) {
+			if b.Len() == 0 {
+				r = unicode.ToUpper(r)
+			}
+
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// namedTypeArgs adds the named types in the type arguments of a generic instantiation to
+// names, through pointers, slices, arrays and map values and in nested instantiations, i.e.
+// github.com/x/models.User for Page[[]*github.com/x/models.User].
+func namedTypeArgs(name string, names map[string]bool) {
+	_, args, _ := splitTypeArgs(name)
+
+	for _, arg := range args {
+		for {
+			arg = strings.TrimLeft(arg, "*")
+
+			if !strings.HasPrefix(arg, "[") && !strings.HasPrefix(arg, "map[") {
+				break
+			}
+
+			// The element follows the bracket closing the first one, i.e. T in []T and map[K]T.
+			depth := 0
+
+			for i := strings.Index(arg, "["); i < len(arg); i++ {
+				if arg[i] == '[' {
+					depth++
+				} else if arg[i] == ']' {
+					depth--
+				}
+
+				if depth == 0 {
+					arg = arg[i+1:]
+
+					break
+				}
+			}
+		}
+
+		names[arg] = true
+		namedTypeArgs(arg, names)
+	}
+}
@@ -0,0 +1,58 @@
+package typeparser
+
+import "github.com/warpspeed-cloud/graphql-schema-generator/schema"
+
+const (
+	nodeInterfaceName = "Node"
+	queryTypeName     = "Query"
+)
+
+// nodeInterface is the interface every Relay node implements.
+func nodeInterface() *schema.InterfaceType {
+	return &schema.InterfaceType{
+		Name:        nodeInterfaceName,
+		Description: "An object with a global ID.",
+		Fields: []*schema.Field{
+			{Name: "id", Description: "The global ID of the object.", Type: schema.NonNullOf(schema.Named("ID"))},
+		},
+	}
+}
+
+// nodeFields are the fields of the Query type to fetch nodes by their global ID.
+func nodeFields() []*schema.Field {
+	return []*schema.Field{
+		{
+			Name:        "node",
+			Description: "Fetches an object given its global ID.",
+			Arguments:   []*schema.InputValue{{Name: "id", Type: schema.NonNullOf(schema.Named("ID"))}},
+			Type:        schema.Named(nodeInterfaceName),
+		},
+		{
+			Name:        "nodes",
+			Description: "Fetches objects given their global IDs.",
+			Arguments:   []*schema.InputValue{{Name: "ids", Type: schema.NonNullOf(schema.ListOf(schema.NonNullOf(schema.Named("ID"))))}},
+			Type:        schema.NonNullOf(schema.ListOf(schema.Named(nodeInterfaceName))),
+		},
+	}
+}
+
+// addNodes adds the Node interface to a schema with Relay nodes, along with
+// the node and nodes fields of the Query type, which is added if there is none.
+func addNodes(s *schema.Schema) {
+	s.Types = append(s.Types, nodeInterface())
+
+	query := queryType(s)
+	query.Fields = append(query.Fields, nodeFields()...)
+}
+
+// queryType returns the Query type of a schema, which is added if there is none.
+func queryType(s *schema.Schema) *schema.ObjectType {
+	if query, ok := s.Type(queryTypeName).(*schema.ObjectType); ok {
+		return query
+	}
+
+	query := &schema.ObjectType{Name: queryTypeName}
+	s.Types = append(s.Types, query)
+
+	return query
+}
@@ -1,9 +1,16 @@
 package typeparser
 
 import (
+	"errors"
 	"fmt"
+	"go/types"
+	"path"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 
+	docparser "github.com/warpspeed-cloud/graphql-schema-generator/internal/doc-parser"
 	tagparser "github.com/warpspeed-cloud/graphql-schema-generator/internal/graphql-tag-parser"
 	jsontagparser "github.com/warpspeed-cloud/graphql-schema-generator/internal/json-tag-parser"
 )
@@ -11,83 +18,914 @@ import (
 const (
 	unnamedMapTemplate    = "Map%d"
 	unnamedStructTemplate = "Struct%d"
+
+	// nodeMethod is the method that marks a struct as a Relay node.
+	nodeMethod = "IsNode"
+	// nodeIDField is the Go field that is the ID of a struct marked as a node by its method.
+	nodeIDField = "ID"
+	// typeOptionsField is the blank field whose tag holds the options of its struct.
+	typeOptionsField = "_"
 )
 
 type TypeDescriptor struct {
-	Name            *string
-	Type            string
-	IsSlice         bool
-	IsPointer       bool
-	IsStruct        bool
-	IsMap           bool
-	IsEnum          bool
-	IncludeInOutput bool
-	ParsedTag       *tagparser.Tag
+	Name *string
+	// GoName is the name of the Go field, i.e. CreatedAt.
+	GoName    string
+	Type      string
+	IsSlice   bool
+	IsPointer bool
+	// IsSliceOfPointers is set when the elements of a slice are pointers, i.e. []*T.
+	IsSliceOfPointers bool
+	IsStruct          bool
+	IsMap             bool
+	IsEnum            bool
+	IncludeInOutput   bool
+	ParsedTag         *tagparser.Tag
+
+	// OmitEmpty is set when the json tag of the field has the omitempty option.
+	OmitEmpty bool
+	// JSONName is the name of the field in JSON, only when FieldNaming gave it another Name.
+	JSONName *string
+
+	// Description is the Go doc comment of the field, it is only populated
+	// when the parser was created with ParseDocComments.
+	Description *string
+	// Deprecation is the reason given in the Deprecated: paragraph of the Go doc
+	// comment of the field, which isn't part of its Description.
+	Deprecation *string
+}
+
+// GetDescription returns the description of the field. A description given in
+// the graphql tag wins over the Go doc comment.
+func (d TypeDescriptor) GetDescription() *string {
+	if d.ParsedTag != nil && d.ParsedTag.Description != nil {
+		return d.ParsedTag.Description
+	}
+
+	return d.Description
+}
+
+// GetDeprecation returns the reason the field is deprecated, or nil when it isn't.
+// The deprecated option of the graphql tag wins over the Go doc comment.
+func (d TypeDescriptor) GetDeprecation() *string {
+	if d.ParsedTag != nil && d.ParsedTag.Deprecated != nil {
+		return d.ParsedTag.Deprecated
+	}
+
+	return d.Deprecation
+}
+
+// jsonName returns the name of the field in JSON.
+func (d TypeDescriptor) jsonName() string {
+	if d.JSONName != nil {
+		return *d.JSONName
+	}
+
+	return *d.Name
 }
 
 type Struct struct {
 	Name   string
 	Fields *[]TypeDescriptor
+
+	// GoName is the Go type the struct was found from, qualified by the name
+	// of its package, i.e. models.User. It is empty for an anonymous struct.
+	GoName string
+	// PkgPath is the import path of the package of the Go type, i.e. github.com/x/models.
+	PkgPath string
+
+	// Description is the Go doc comment of the struct, it is only populated
+	// when the parser was created with ParseDocComments.
+	Description *string
+
+	// IsNode is set for a Relay node, a struct with an IsNode method or whose ID
+	// field is tagged with node. The ID field of a node has the ID type.
+	IsNode bool
+
+	// ParsedTag is the graphql tag of the blank field of the struct, i.e.
+	// _ struct{} `graphql:"key=id"`, as Go types themselves can't be tagged.
+	ParsedTag *tagparser.Tag
 }
 
 type Map struct {
 	Name string
 	Key  TypeDescriptor
 	Val  TypeDescriptor
+	// GoName and PkgPath are the Go type of a named map, i.e. models.Labels, and are empty
+	// for a map that is only named after where it is found.
+	GoName  string
+	PkgPath string
+}
+
+// recursiveMaps returns the names of the maps whose values are the map itself, directly or
+// through other maps, i.e. type Tree map[string]Tree. The outputs that write maps inline
+// can't write them out, so they refer to them by name instead.
+func recursiveMaps(maps map[string]Map) map[string]bool {
+	recursive := map[string]bool{}
+
+	for name := range maps {
+		seen := map[string]bool{}
+
+		for next := maps[name].Val.Type; !seen[next]; next = maps[next].Val.Type {
+			if next == name {
+				recursive[name] = true
+
+				break
+			}
+
+			if _, ok := maps[next]; !ok {
+				break
+			}
+
+			seen[next] = true
+		}
+	}
+
+	return recursive
 }
 
 type EnumKeyPairOptions struct {
 	Key         string
 	Value       interface{}
 	Description *string
+	// Deprecation is the reason the value is deprecated, an empty reason deprecates it without one.
+	Deprecation *string
 }
 
 type Enum struct {
-	Name   string
-	Values []EnumKeyPairOptions
+	Name        string
+	Values      []EnumKeyPairOptions
+	Description *string
 }
 
+// TypeParser discovers the types of a schema from Go types. Types can be added from several
+// goroutines at once, i.e. from the init functions of packages tested in parallel, and are
+// printed from a Snapshot. The types are then added in the order the calls get the parser in.
 type TypeParser struct {
+	// Structs, Maps and Enums are the types found so far. They can only be read directly
+	// when no types are being added, otherwise they are read from a Snapshot.
 	Structs *[]Struct
 	Maps    *[]Map
 	Enums   *[]Enum
 
-	// Keep a list of types that are pending being added to the schema.
-	// This is used to prevent infinite recursion when a struct has a field that is a pointer to itself
-	// or a slice of itself or when structs have circular references.
-	pendingStructTypeNames *[]string
+	options *TypeParserOptions
+
+	// mu is held by the calls that add types, and while a snapshot is taken.
+	mu sync.Mutex
+
+	// frozen is set on a snapshot, which types can't be added to.
+	frozen bool
+
+	// Doc comments loaded so far, keyed by package path.
+	docs map[string]*docparser.Package
+
+	// The graph of the types visited by the AddStruct or AddMap call in progress, keyed by
+	// their identity. A type found again, i.e. through a pointer to itself or a cycle of maps,
+	// slices and anonymous structs, is given the name it got the first time rather than being
+	// walked again. It is nil between calls, types added by earlier calls are found by name.
+	visited map[any]*typeNode
+
+	// Problems found in the tags of the types added so far.
+	warnings []string
+
+	// Syntax errors in the tags of the types added so far, which Validate returns.
+	tagErrors []*tagparser.SyntaxError
+}
+
+// typeNode is a struct or a map visited by the AddStruct or AddMap call in progress.
+type typeNode struct {
+	// name is the name of the type in the schema.
+	name string
+	// pending is set until every type the type refers to has been visited.
+	pending bool
+	// s is the struct found from the type, nil for a map.
+	s *Struct
+	// m is the map found from the type, nil for a struct.
+	m *Map
+	// fields are the fields of the struct found so far.
+	fields *[]TypeDescriptor
 }
 
+// unnamedMap keys an unnamed map by its name in the visited types.
+type unnamedMap string
+
 type AddStructOptions struct {
 	Name *string
 }
 
-func NewTypeParser(_ *interface{}) *TypeParser {
-	return &TypeParser{}
+type TypeParserOptions struct {
+	// ParseDocComments reads the Go source of each discovered struct and uses the
+	// doc comments of the struct and its fields as their descriptions.
+	ParseDocComments bool
+
+	// Scalars maps Go types to the GraphQL scalar they are output as. Keys are either
+	// a kind, i.e. int64, or a named type qualified by its package path, i.e. time.Time.
+	// Structs mapped to a scalar aren't walked.
+	Scalars map[string]string
+
+	// FieldNaming is the convention field names are converted to, by default the
+	// name in the json tag is used as is, falling back to the name of the Go field.
+	FieldNaming FieldNaming
+
+	// ExcludeTypes are types that are left out of the schema along with every
+	// field of their type. Types are given by name, optionally qualified by their
+	// package path.
+	ExcludeTypes []string
+
+	// IDFields are the fields output as the ID scalar, when they are strings or
+	// integers. They are patterns matched against both the Go and the output name
+	// of fields, i.e. ID matches the ID field and *ID matches UserID too.
+	IDFields []string
+
+	// GenericNaming is the template the instantiations of generic types are named
+	// with, DefaultGenericNaming when it is empty.
+	GenericNaming GenericNaming
+
+	// TypeAliases are the names structs are given in the schema instead of their Go
+	// name, keyed by the Go type qualified by its package path, i.e. github.com/x/billing.Account.
+	TypeAliases map[string]string
+
+	// NameCollisions is what happens when two Go types, i.e. billing.Account and
+	// auth.Account, or a Go type and an enum would have the same name in the schema,
+	// NameCollisionError by default. Named structs and maps are told apart alike.
+	NameCollisions NameCollision
 }
 
-// A function that returns a boolean whether a struct exists by this name or is pending.
-func (t *TypeParser) structExistsAndIsntPending(name string) bool {
-	if t.pendingStructTypeNames != nil {
-		for _, pendingName := range *t.pendingStructTypeNames {
-			if pendingName == name {
-				return true
+// NameCollision is what happens when two Go types would have the same name in the schema.
+type NameCollision string
+
+const (
+	// NameCollisionError panics, reporting both Go types, so that one of them is given an alias.
+	NameCollisionError NameCollision = "error"
+	// NameCollisionPrefix prefixes the name of every Go type of the same name with the name of
+	// its package, i.e. billing.Account is BillingAccount and auth.Account is AuthAccount, so
+	// that the names don't depend on the order the types are found in.
+	NameCollisionPrefix NameCollision = "prefix"
+)
+
+// NameCollisions are the name collision policies that can be configured, an empty policy is NameCollisionError.
+var NameCollisions = []NameCollision{"", NameCollisionError, NameCollisionPrefix}
+
+func NewTypeParser(options *TypeParserOptions) *TypeParser {
+	return &TypeParser{
+		options: options,
+	}
+}
+
+// packageDocs returns the doc comments of the package a type is declared in,
+// or nil when doc comments are disabled or the source can't be found.
+func (t *TypeParser) packageDocs(m goType) *docparser.Package {
+	if t.options == nil || !t.options.ParseDocComments || m.PkgPath() == "" {
+		return nil
+	}
+
+	if t.docs == nil {
+		t.docs = map[string]*docparser.Package{}
+	}
+
+	if docs, ok := t.docs[m.PkgPath()]; ok {
+		return docs
+	}
+
+	// The source isn't always available, i.e. when running a compiled binary
+	// away from the module, in which case descriptions come from tags only.
+	docs, err := docparser.Load(m.PkgPath())
+	if err != nil {
+		docs = nil
+	}
+
+	t.docs[m.PkgPath()] = docs
+
+	return docs
+}
+
+// optionalDoc returns nil for an empty doc comment so that undocumented
+// types and fields don't get an empty description.
+func optionalDoc(doc string) *string {
+	if doc == "" {
+		return nil
+	}
+
+	return &doc
+}
+
+// qualifiedName returns the name of a named type prefixed with its package path, i.e. time.Time.
+func qualifiedName(m goType) string {
+	if m.PkgPath() == "" {
+		return m.Name()
+	}
+
+	return m.PkgPath() + "." + m.Name()
+}
+
+// typeName returns the name of a named type in the schema, the instantiations of
+// generic types are named with the GenericNaming template.
+func (t *TypeParser) typeName(m goType) string {
+	return t.instantiationName(m.Name())
+}
+
+// instantiationName names a generic instantiation, as its name is written by reflect,
+// i.e. Page[github.com/x/models.User] is UserPage. Other names are returned as is.
+func (t *TypeParser) instantiationName(name string) string {
+	typeName, args, isGeneric := splitTypeArgs(name)
+	if !isGeneric {
+		return name
+	}
+
+	argNames := make([]string, len(args))
+	for i, arg := range args {
+		argNames[i] = t.typeArgName(arg)
+	}
+
+	var naming GenericNaming
+	if t.options != nil {
+		naming = t.options.GenericNaming
+	}
+
+	generated, err := naming.name(typeName, argNames)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return generated
+}
+
+// typeArgName returns the name of a type argument in the name of a generic instantiation,
+// i.e. github.com/x/models.User is User, []string is StringList and map[string]int is StringIntMap.
+func (t *TypeParser) typeArgName(arg string) string {
+	switch {
+	case strings.HasPrefix(arg, "*"):
+		return t.typeArgName(arg[1:])
+	case strings.HasPrefix(arg, "map["):
+		// The key ends at the bracket closing map[.
+		depth := 0
+
+		for i := len("map"); i < len(arg); i++ {
+			switch arg[i] {
+			case '[':
+				depth++
+			case ']':
+				depth--
 			}
+
+			if depth == 0 {
+				return t.typeArgName(arg[len("map["):i]) + t.typeArgName(arg[i+1:]) + "Map"
+			}
+		}
+	case strings.HasPrefix(arg, "["):
+		// A slice, []T, or an array, [4]T.
+		if end := strings.Index(arg, "]"); end >= 0 {
+			return t.typeArgName(arg[end+1:]) + "List"
+		}
+	}
+
+	// A type argument that was found is named as it is in the schema, with its alias or
+	// the prefix of its package, i.e. AuthAccount for github.com/x/auth.Account.
+	for name, found := range t.goTypes() {
+		if found.qualifiedGoName() == arg {
+			return name
+		}
+	}
+
+	if t.options != nil {
+		if alias, ok := t.options.TypeAliases[arg]; ok {
+			return alias
+		}
+	}
+
+	// The package path is dropped, i.e. github.com/x/models.User is User, and as the
+	// path can have dots, gopkg.in/yaml.v3.Node is Node.
+	name, typeArgs := arg, ""
+	if open := strings.Index(arg, "["); open >= 0 {
+		name, typeArgs = arg[:open], arg[open:]
+	}
+
+	name = name[strings.LastIndex(name, ".")+1:]
+
+	return exportedName(t.instantiationName(name + typeArgs))
+}
+
+// goName returns the name of a named type qualified by the name of its package, i.e.
+// models.User, or an empty string for an unnamed type.
+func goName(m goType) string {
+	if m.Name() == "" || m.PkgName() == "" {
+		return m.Name()
+	}
+
+	return m.PkgName() + "." + m.Name()
+}
+
+// scalarFor returns the scalar a type was mapped to with the Scalars option, if any.
+func (t *TypeParser) scalarFor(m goType) (string, bool) {
+	if t.options == nil || t.options.Scalars == nil {
+		return "", false
+	}
+
+	if m.Name() != "" {
+		if scalar, ok := t.options.Scalars[qualifiedName(m)]; ok {
+			return scalar, true
+		}
+	}
+
+	scalar, ok := t.options.Scalars[m.Kind().String()]
+
+	return scalar, ok
+}
+
+// isExcluded returns whether a named type was excluded with the ExcludeTypes option.
+func (t *TypeParser) isExcluded(m goType) bool {
+	if t.options == nil || m.Name() == "" {
+		return false
+	}
+
+	for _, excluded := range t.options.ExcludeTypes {
+		if excluded == m.Name() || excluded == qualifiedName(m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isIDField returns whether a field matches one of the IDFields patterns,
+// by its Go name or its name in the schema.
+func (t *TypeParser) isIDField(goName string, name string) bool {
+	if t.options == nil {
+		return false
+	}
+
+	for _, pattern := range t.options.IDFields {
+		if matched, _ := path.Match(pattern, goName); matched {
+			return true
+		}
+
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// structNamed returns the struct, added or pending, with a name in the schema, or nil if there is none.
+func (t *TypeParser) structNamed(name string) *Struct {
+	for _, node := range t.visited {
+		if node.s != nil && node.s.Name == name {
+			return node.s
 		}
 	}
 
 	if t.Structs != nil {
-		for _, s := range *t.Structs {
-			if s.Name == name {
-				return true
+		for i := range *t.Structs {
+			if (*t.Structs)[i].Name == name {
+				return &(*t.Structs)[i]
 			}
 		}
 	}
 
+	return nil
+}
+
+// mapNamed returns the map, added or pending, with a name in the schema, or nil if there is none.
+func (t *TypeParser) mapNamed(name string) *Map {
+	for _, node := range t.visited {
+		if node.m != nil && node.m.Name == name {
+			return node.m
+		}
+	}
+
+	if t.Maps != nil {
+		for i := range *t.Maps {
+			if (*t.Maps)[i].Name == name {
+				return &(*t.Maps)[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// namedType is the type that has a name in the schema, which the name of a Go type is checked
+// against. The Go name and package path are empty for the types that weren't found from a named
+// Go type: anonymous structs, unnamed maps and enums.
+type namedType struct {
+	kind    string
+	goName  string
+	pkgPath string
+}
+
+// typeNamed returns the struct, map or enum with a name in the schema, if there is one.
+func (t *TypeParser) typeNamed(name string) (namedType, bool) {
+	if s := t.structNamed(name); s != nil {
+		return namedType{kind: "anonymous struct", goName: s.GoName, pkgPath: s.PkgPath}, true
+	}
+
+	if m := t.mapNamed(name); m != nil {
+		return namedType{kind: "unnamed map", goName: m.GoName, pkgPath: m.PkgPath}, true
+	}
+
+	if t.Enums != nil {
+		for _, e := range *t.Enums {
+			if e.Name == name {
+				return namedType{kind: "enum"}, true
+			}
+		}
+	}
+
+	return namedType{}, false
+}
+
+// isGoType returns whether the type was found from a Go type.
+func (n namedType) isGoType(m goType) bool {
+	return n.goName != "" && n.goName == goName(m) && n.pkgPath == m.PkgPath()
+}
+
+// qualifiedGoName returns the Go type qualified by its package path, i.e. github.com/x/models.User.
+func (n namedType) qualifiedGoName() string {
+	_, name, _ := strings.Cut(n.goName, ".")
+
+	return n.pkgPath + "." + name
+}
+
+// String describes the type in the messages of name collisions, i.e. the Go type 'github.com/x/models.User'.
+func (n namedType) String() string {
+	if n.goName == "" {
+		return "the " + n.kind
+	}
+
+	return fmt.Sprintf("the Go type '%s'", n.qualifiedGoName())
+}
+
+// prefixedName returns a name prefixed with the name of the package of the type, i.e. BillingInvoice.
+func (n namedType) prefixedName(name string) string {
+	pkgName, _, _ := strings.Cut(n.goName, ".")
+
+	return exportedName(pkgName) + name
+}
+
+// anonymousStructName returns the name of an anonymous struct found in a struct or a map:
+// the name of its parent followed by Struct and its depth, i.e. UserStruct1. When another
+// anonymous struct already has that name it is the name of its parent and its field instead.
+func (t *TypeParser) anonymousStructName(parent string, field string, m goType, depth int) string {
+	if m.Name() != "" {
+		return ""
+	}
+
+	name := parent + fmt.Sprintf(unnamedStructTemplate, depth)
+	if t.structNamed(name) != nil && t.visited[m.Identity()] == nil {
+		return parent + field
+	}
+
+	return name
+}
+
+// anonymousDepth returns the depth of an anonymous struct found in a field of a struct at a
+// depth. It is counted from the nearest named struct, so that an anonymous struct has the same
+// name whichever struct its named parent was found from, i.e. UserDocumentStruct1.
+func anonymousDepth(parent goType, depth int) int {
+	if parent.Name() != "" {
+		return 1
+	}
+
+	return depth + 1
+}
+
+// goTypeName returns the name of a named struct or map in the schema: its alias, or its Go
+// name. When another Go type has that name too, with NameCollisionPrefix both are prefixed
+// with the name of their package whatever order they are found in, otherwise the parser
+// panics as the types can't be told apart. A type keeps its alias, only the other one is
+// prefixed, and a Go type named like an enum or a type named after where it is found is
+// prefixed, as they can't be.
+func (t *TypeParser) goTypeName(m goType) string {
+	if m.Name() == "" {
+		return ""
+	}
+
+	t.addTypeArgs(m)
+	name := t.typeName(m)
+	aliased := false
+
+	if t.options != nil {
+		if alias, ok := t.options.TypeAliases[qualifiedName(m)]; ok {
+			name, aliased = alias, true
+		}
+	}
+
+	other, taken := t.typeNamed(name)
+	if taken && other.isGoType(m) {
+		return name
+	}
+
+	if t.options != nil && t.options.NameCollisions == NameCollisionPrefix {
+		// The type found first was given the name before the collision was known.
+		if taken && other.goName != "" && !t.isAliased(other) {
+			t.renameType(name, other.prefixedName(name))
+			taken = false
+		} else if !taken && !t.isPrefixed(name) {
+			return name
+		}
+
+		if aliased {
+			if !taken {
+				return name
+			}
+		} else {
+			prefixed := namedType{goName: goName(m)}.prefixedName(name)
+
+			if other, taken = t.typeNamed(prefixed); !taken || other.isGoType(m) {
+				return prefixed
+			}
+
+			name = prefixed
+		}
+	} else if !taken {
+		return name
+	}
+
+	if other.goName == "" {
+		panic(fmt.Sprintf("%s and the Go type '%s' are both named '%s' in the schema, give the Go type an alias "+
+			"or prefix it with the name of its package", capitalized(other.String()), qualifiedName(m), name))
+	}
+
+	hint := "give one of them an alias or prefix them with the name of their package"
+	if _, _, isGeneric := splitTypeArgs(m.Name()); isGeneric {
+		hint = "give one of them an alias or change the GenericNaming template"
+	}
+
+	panic(fmt.Sprintf("The Go types '%s' and '%s' are both named '%s' in the schema, %s",
+		other.qualifiedGoName(), qualifiedName(m), name, hint))
+}
+
+// addTypeArgs walks the named structs and maps that are type arguments of a generic
+// instantiation and that its fields, or its value for a map, refer to, so that it is named
+// after their names in the schema, i.e. AuthAccountPage for Page[auth.Account] when
+// auth.Account is prefixed.
+func (t *TypeParser) addTypeArgs(m goType) {
+	args := map[string]bool{}
+	if namedTypeArgs(m.Name(), args); len(args) == 0 {
+		return
+	}
+
+	var argTypes []goType
+
+	switch m.Kind() {
+	case reflect.Struct:
+		for i := 0; i < m.NumField(); i++ {
+			field := m.Field(i)
+
+			// A field excluded or overridden by its tag isn't walked.
+			if tag, _ := tagparser.Parse(field.Tag.Get("graphql"), field.Name); tag == nil || (!tag.Exclude && tag.Type == "") {
+				argTypes = append(argTypes, field.Type)
+			}
+		}
+	case reflect.Map:
+		argTypes = append(argTypes, m.Elem())
+	}
+
+	for _, argType := range argTypes {
+		for kind := argType.Kind(); kind == reflect.Ptr || kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map; kind = argType.Kind() {
+			argType = argType.Elem()
+		}
+
+		if _, isScalar := t.scalarFor(argType); isScalar || !args[qualifiedName(argType)] || t.isExcluded(argType) {
+			continue
+		}
+
+		switch argType.Kind() {
+		case reflect.Struct:
+			t.internalAddStruct(argType, "", 1)
+		case reflect.Map:
+			t.internalAddMap(t.goTypeName(argType), argType, 0)
+		}
+	}
+}
+
+// capitalized returns a message with its first letter in upper case.
+func capitalized(message string) string {
+	return strings.ToUpper(message[:1]) + message[1:]
+}
+
+// isAliased returns whether a Go type was named with TypeAliases.
+func (t *TypeParser) isAliased(n namedType) bool {
+	if t.options == nil {
+		return false
+	}
+
+	_, ok := t.options.TypeAliases[n.qualifiedGoName()]
+
+	return ok
+}
+
+// isPrefixed returns whether a Go type was prefixed with the name of its package because
+// another one had the same name, so that every Go type of that name is prefixed.
+func (t *TypeParser) isPrefixed(name string) bool {
+	for typeName, found := range t.goTypes() {
+		_, goTypeName, _ := strings.Cut(found.goName, ".")
+
+		if typeName == found.prefixedName(name) && t.instantiationName(goTypeName) == name {
+			return true
+		}
+	}
+
 	return false
 }
 
-func (t *TypeParser) internalAddMap(name string, m reflect.Type, depth int) *TypeParser {
+// goTypes returns the structs and maps found from Go types, added or pending, by their name in the schema.
+func (t *TypeParser) goTypes() map[string]namedType {
+	found := map[string]namedType{}
+
+	add := func(name string, goName string, pkgPath string) {
+		if goName != "" {
+			found[name] = namedType{goName: goName, pkgPath: pkgPath}
+		}
+	}
+
+	for _, node := range t.visited {
+		if node.s != nil {
+			add(node.s.Name, node.s.GoName, node.s.PkgPath)
+		}
+
+		if node.m != nil {
+			add(node.m.Name, node.m.GoName, node.m.PkgPath)
+		}
+	}
+
+	if t.Structs != nil {
+		for _, s := range *t.Structs {
+			add(s.Name, s.GoName, s.PkgPath)
+		}
+	}
+
+	if t.Maps != nil {
+		for _, m := range *t.Maps {
+			add(m.Name, m.GoName, m.PkgPath)
+		}
+	}
+
+	return found
+}
+
+// renameType renames a struct or a map along with the anonymous structs and unnamed maps named
+// after it, i.e. InvoiceStruct1, and every reference to them. The fields of the structs that
+// were added are copied rather than changed, as snapshots share them.
+func (t *TypeParser) renameType(from string, to string) {
+	if other, taken := t.typeNamed(to); taken {
+		renamed, _ := t.typeNamed(from)
+
+		panic(fmt.Sprintf("%s and %s are both named '%s' in the schema, give one of them an alias",
+			capitalized(other.String()), renamed, to))
+	}
+
+	// The generic instantiations named after the type, i.e. AccountPage for Page[auth.Account],
+	// are renamed with it, unless they have an alias.
+	renamed, _ := t.typeNamed(from)
+	instantiations := map[string]string{}
+
+	for name, found := range t.goTypes() {
+		_, goTypeName, _ := strings.Cut(found.goName, ".")
+		args := map[string]bool{}
+		namedTypeArgs(goTypeName, args)
+
+		if renamed.goName != "" && args[renamed.qualifiedGoName()] && t.instantiationName(goTypeName) == name {
+			instantiations[name] = goTypeName
+		}
+	}
+
+	defer func() {
+		for name, goTypeName := range instantiations {
+			if newName := t.instantiationName(goTypeName); newName != name {
+				t.renameType(name, newName)
+			}
+		}
+	}()
+
+	renames := map[string]string{from: to}
+	t.renameDerived(from, to, renames)
+
+	renameFields := func(fields []TypeDescriptor) []TypeDescriptor {
+		var renamed []TypeDescriptor
+
+		for i, field := range fields {
+			if name, ok := renames[field.Type]; ok {
+				if renamed == nil {
+					renamed = append([]TypeDescriptor(nil), fields...)
+				}
+
+				renamed[i].Type = name
+			}
+		}
+
+		return renamed
+	}
+
+	for _, node := range t.visited {
+		if name, ok := renames[node.name]; ok {
+			node.name = name
+		}
+
+		if node.s != nil {
+			if name, ok := renames[node.s.Name]; ok {
+				node.s.Name = name
+			}
+		}
+
+		if node.m != nil {
+			if name, ok := renames[node.m.Name]; ok {
+				node.m.Name = name
+			}
+		}
+
+		// The fields of a pending struct aren't shared yet.
+		if node.pending && node.fields != nil {
+			if renamed := renameFields(*node.fields); renamed != nil {
+				copy(*node.fields, renamed)
+			}
+		}
+	}
+
+	if t.Structs != nil {
+		for i := range *t.Structs {
+			s := &(*t.Structs)[i]
+
+			if name, ok := renames[s.Name]; ok {
+				s.Name = name
+			}
+
+			if s.Fields != nil {
+				if renamed := renameFields(*s.Fields); renamed != nil {
+					s.Fields = &renamed
+				}
+			}
+		}
+	}
+
+	if t.Maps != nil {
+		for i := range *t.Maps {
+			m := &(*t.Maps)[i]
+
+			if name, ok := renames[m.Name]; ok {
+				m.Name = name
+			}
+
+			if name, ok := renames[m.Val.Type]; ok {
+				m.Val.Type = name
+			}
+		}
+	}
+}
+
+// renameDerived adds the anonymous structs and unnamed maps named after a type to the
+// renames, which are the ones its fields, or its value for a map, refer to.
+func (t *TypeParser) renameDerived(from string, to string, renames map[string]string) {
+	derive := func(name string) {
+		if _, ok := renames[name]; ok || name == from || !strings.HasPrefix(name, from) {
+			return
+		}
+
+		renames[name] = to + name[len(from):]
+		t.renameDerived(name, renames[name], renames)
+	}
+
+	isUnnamed := func(name string) bool {
+		if s := t.structNamed(name); s != nil {
+			return s.GoName == ""
+		}
+
+		m := t.mapNamed(name)
+
+		return m != nil && m.GoName == ""
+	}
+
+	for _, field := range t.fieldsOf(from) {
+		if isUnnamed(field.Type) {
+			derive(field.Type)
+		}
+	}
+
+	if m := t.mapNamed(from); m != nil && isUnnamed(m.Val.Type) {
+		derive(m.Val.Type)
+	}
+}
+
+// fieldsOf returns the fields of a struct, the ones found so far for a pending struct.
+func (t *TypeParser) fieldsOf(name string) []TypeDescriptor {
+	for _, node := range t.visited {
+		if node.s != nil && node.s.Name == name && node.fields != nil {
+			return *node.fields
+		}
+	}
+
+	if s := t.structNamed(name); s != nil && s.Fields != nil {
+		return *s.Fields
+	}
+
+	return nil
+}
+
+// internalAddMap adds a map and the types of its value, and returns the name of the map in
+// the schema. A named map type is visited once, so that a map can refer to itself through
+// its value. An unnamed map is named after where it is found and is added once per name.
+func (t *TypeParser) internalAddMap(name string, m goType, depth int) string {
 	var mapValueTypeName string
 
 	key := TypeDescriptor{}
@@ -105,6 +943,39 @@ func (t *TypeParser) internalAddMap(name string, m reflect.Type, depth int) *Typ
 		panic(fmt.Sprintf("AddMap must be called with a map type, '%s' is a '%s'", name, m.Kind().String()))
 	}
 
+	// An unnamed map is visited by its name, as the same Go type is a different map in
+	// every field it is found in.
+	var identity any = unnamedMap(name)
+	if m.Name() != "" {
+		identity = m.Identity()
+	}
+
+	if visited, ok := t.visited[identity]; ok {
+		return visited.name
+	}
+
+	newMap := Map{Name: name}
+	if m.Name() != "" {
+		newMap.GoName = goName(m)
+		newMap.PkgPath = m.PkgPath()
+	}
+
+	// A map added by an earlier call isn't added again, but another map can't have its name.
+	if added := t.mapNamed(name); added != nil {
+		if added.GoName == newMap.GoName && added.PkgPath == newMap.PkgPath {
+			return name
+		}
+
+		other, _ := t.typeNamed(name)
+		found := namedType{kind: "unnamed map", goName: newMap.GoName, pkgPath: newMap.PkgPath}
+
+		panic(fmt.Sprintf("%s and %s are both named '%s' in the schema, give one of them an alias",
+			capitalized(other.String()), found, name))
+	}
+
+	node := &typeNode{name: name, pending: true, m: &newMap}
+	t.visited[identity] = node
+
 	mapKeyType := m.Key()
 	mapValueType := m.Elem()
 
@@ -128,42 +999,58 @@ func (t *TypeParser) internalAddMap(name string, m reflect.Type, depth int) *Typ
 	// If the elem is a map then we need to add that map too
 	// At this point we know that the type is a map so we also
 	// increase the depth counter and generate a new name for the map.
-	switch mapValueType.Kind() {
-	case reflect.Map:
-		mapName := fmt.Sprintf(unnamedMapTemplate, depth+1)
-		mapValueTypeName = fmt.Sprintf("%s%s", name, mapName)
+	scalar, isScalar := t.scalarFor(mapValueType)
+
+	switch {
+	case isScalar:
+		mapValueTypeName = scalar
+	case mapValueType.Kind() == reflect.Map:
+		valueName := t.goTypeName(mapValueType)
+		if valueName == "" {
+			valueName = name + fmt.Sprintf(unnamedMapTemplate, depth+1)
+		}
 
-		t.internalAddMap(fmt.Sprintf("%s%s", name, mapName), mapValueType, depth+1)
-	case reflect.Struct:
-		mapValueTypeName = mapValueType.Name()
-		t.internalAddStruct(mapValueType, 0)
+		mapValueTypeName = t.internalAddMap(valueName, mapValueType, depth+1)
+	case mapValueType.Kind() == reflect.Struct:
+		structName := t.anonymousStructName(name, "Value", mapValueType, depth+1)
+		mapValueTypeName = t.internalAddStruct(mapValueType, structName, depth+1)
 	default:
 		mapValueTypeName = mapValueType.Kind().String()
 	}
 
-	key.Type = mapKeyType.Kind().String()
+	if scalar, ok := t.scalarFor(mapKeyType); ok {
+		key.Type = scalar
+	} else {
+		key.Type = mapKeyType.Kind().String()
+	}
 	val.Type = mapValueTypeName
 
 	if t.Maps == nil {
 		t.Maps = &[]Map{}
 	}
 
+	// The map may have been renamed while its value was walked.
 	*t.Maps = append(*t.Maps, Map{
-		Name: name,
-		Key:  key,
-		Val:  val,
+		Name:    newMap.Name,
+		Key:     key,
+		Val:     val,
+		GoName:  newMap.GoName,
+		PkgPath: newMap.PkgPath,
 	})
 
-	return t
+	node.pending = false
+
+	return newMap.Name
 }
 
 // getStructField returns a TypeDescriptor for a struct field.
 
 // internalAddStruct loops over each field in the struct and add it to the schema
-// recursively. It will unroll pointers and slices to find the underlying type
-// automatically.
-func (t *TypeParser) internalAddStruct(m reflect.Type, depth int) {
-	newStruct := Struct{}
+// recursively, and returns the name of the struct in the schema. It will unroll
+// pointers and slices to find the underlying type automatically. The name is the
+// one of an anonymous struct, a named struct is named after its Go type.
+func (t *TypeParser) internalAddStruct(m goType, name string, depth int) string {
+	newStruct := Struct{Name: name}
 
 	if m.Kind() == reflect.Ptr {
 		m = m.Elem()
@@ -173,26 +1060,45 @@ func (t *TypeParser) internalAddStruct(m reflect.Type, depth int) {
 		panic(fmt.Sprintf("AddStruct must be called with a struct type, '%s' is a '%s'", m.Name(), m.Kind().String()))
 	}
 
-	if m.Name() == "" {
-		newStruct.Name = fmt.Sprintf(unnamedStructTemplate, depth)
-	} else {
-		newStruct.Name = m.Name()
+	// A struct that was already visited, even if it is still pending, isn't walked again.
+	if visited, ok := t.visited[m.Identity()]; ok {
+		return visited.name
 	}
 
-	// If the struct is already in the schema or is pending then we don't need to add it again.
-	if t.structExistsAndIsntPending(newStruct.Name) {
-		return
+	if m.Name() != "" {
+		newStruct.Name = t.goTypeName(m)
+		newStruct.GoName = goName(m)
+		newStruct.PkgPath = m.PkgPath()
 	}
 
-	// Add the struct name to the pending list so that we don't add it again.
-	*t.pendingStructTypeNames = append(*t.pendingStructTypeNames, newStruct.Name)
+	// A struct added by an earlier call isn't added again.
+	if t.structNamed(newStruct.Name) != nil {
+		return newStruct.Name
+	}
 
 	// Create a new slice to hold the fields for this struct.
 	var fields []TypeDescriptor
 
+	node := &typeNode{name: newStruct.Name, pending: true, s: &newStruct, fields: &fields}
+	t.visited[m.Identity()] = node
+
+	docs := t.packageDocs(m)
+	// Doc comments are on the generic type, i.e. on Page rather than Page[User].
+	docName, _, _ := splitTypeArgs(m.Name())
+	newStruct.Description = optionalDoc(docs.TypeDoc(docName))
+
+	hasNodeMethod := m.HasMethod(nodeMethod)
+
 	// Loop over each field in the struct and add it to the schema.
 	for i := 0; i < m.NumField(); i++ {
 		field := m.Field(i)
+
+		if field.Name == typeOptionsField {
+			newStruct.ParsedTag = t.parseTag(field.Tag.Get("graphql"), newStruct.Name)
+
+			continue
+		}
+
 		jsonTag := jsontagparser.Parse(field.Tag.Get("json"))
 
 		var fieldName string
@@ -202,10 +1108,30 @@ func (t *TypeParser) internalAddStruct(m reflect.Type, depth int) {
 			fieldName = jsonTag.Name
 		}
 
-		graphqlTag := tagparser.ParseTag(m.Field(i).Tag.Get("graphql"), fieldName)
+		jsonName := fieldName
+
+		if t.options != nil {
+			fieldName = t.options.FieldNaming.convert(fieldName)
+		}
+
+		description, deprecation := docparser.SplitDeprecation(docs.FieldDoc(docName, field.Name))
+
+		graphqlTag := t.parseTag(field.Tag.Get("graphql"), newStruct.Name+"."+field.Name)
+		if graphqlTag != nil && graphqlTag.Name != "" {
+			fieldName = graphqlTag.Name
+		}
+
 		newField := TypeDescriptor{
-			Name:      &fieldName,
-			ParsedTag: graphqlTag,
+			Name:        &fieldName,
+			GoName:      field.Name,
+			ParsedTag:   graphqlTag,
+			Description: optionalDoc(description),
+			Deprecation: deprecation,
+			OmitEmpty:   jsonTag != nil && jsonTag.OmitEmpty,
+		}
+
+		if jsonName != fieldName {
+			newField.JSONName = &jsonName
 		}
 
 		fieldType := field.Type
@@ -216,63 +1142,102 @@ func (t *TypeParser) internalAddStruct(m reflect.Type, depth int) {
 			newField.IsPointer = true
 		}
 
-		// Now we check if the field is a slice and if so, we unroll it.
+		// Now we check if the field is a slice and if so, we unroll it
+		// along with the pointer to each element, if any.
 		if fieldType.Kind() == reflect.Slice {
 			fieldType = fieldType.Elem()
 			newField.IsSlice = true
+
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+				newField.IsSliceOfPointers = true
+			}
 		}
 
 		fieldKind := fieldType.Kind()
+		scalar, isScalar := t.scalarFor(fieldType)
+		typeOverride, hasTypeOverride := "", false
+		tagExcluded := false
+
+		if graphqlTag != nil {
+			typeOverride, hasTypeOverride = graphqlTag.Type, graphqlTag.Type != ""
+			tagExcluded = graphqlTag.Exclude
+		}
+
+		excluded := tagExcluded || (!hasTypeOverride && t.isExcluded(fieldType))
 
 		// If the field is a struct then we need to add that struct too
 		// At this point we know that the type is a struct so we also
 		// increase the depth counter and generate a new name for the struct.
-		switch fieldKind { //nolint: exhaustive
-		case reflect.Struct:
-			if fieldType.Name() == "" {
-				newField.Type = fmt.Sprintf("%s%s", newStruct.Name, fmt.Sprintf(unnamedStructTemplate, depth+1))
-			} else {
-				newField.Type = fieldType.Name()
-			}
-			t.internalAddStruct(fieldType, depth+1)
+		switch {
+		case tagExcluded:
+			// A field excluded by its tag isn't walked, whatever its type.
+			newField.Type = t.typeName(fieldType)
+		case hasTypeOverride:
+			// The Go type is replaced so it isn't walked, the type is checked
+			// to exist once every type is known, when the schema is built.
+			newField.Type = typeOverride
+		case isIDKind(fieldKind) && t.isIDField(field.Name, fieldName):
+			newField.Type = "ID"
+		case isScalar:
+			newField.Type = scalar
+		case excluded:
+			newField.Type = t.typeName(fieldType)
+		case fieldKind == reflect.Struct:
+			structDepth := anonymousDepth(m, depth)
+			structName := t.anonymousStructName(newStruct.Name, field.Name, fieldType, structDepth)
+			newField.Type = t.internalAddStruct(fieldType, structName, structDepth)
 
 			if !newField.IsSlice {
 				newField.IsStruct = true
 			}
-		case reflect.Map:
-			newFieldTypeName := fieldType.Name()
-			if newFieldTypeName == "" {
-				newFieldTypeName = fmt.Sprintf("%s%s", newStruct.Name, field.Name)
+		case fieldKind == reflect.Map:
+			mapName := t.goTypeName(fieldType)
+			if mapName == "" {
+				mapName = newStruct.Name + field.Name
 			}
 
-			t.internalAddMap(fmt.Sprintf("%s%s", newFieldTypeName, fieldType.Name()), fieldType, 0)
-
-			newField.Type = newFieldTypeName
+			newField.Type = t.internalAddMap(mapName, fieldType, 0)
 			newField.IsMap = true
 		default:
 			newField.Type = fieldType.Kind().String()
 		}
 
-		newField.IncludeInOutput = field.IsExported() && (jsonTag == nil || !jsonTag.Private)
+		newField.IncludeInOutput = field.Exported && (jsonTag == nil || !jsonTag.Private) && !excluded
+
+		if (graphqlTag != nil && graphqlTag.Node) || (hasNodeMethod && field.Name == nodeIDField) {
+			if newField.IsPointer || newField.IsSlice || !isIDKind(fieldKind) {
+				panic(fmt.Sprintf("The ID of node '%s' must be a string or an integer, '%s' isn't", newStruct.Name, field.Name))
+			}
+
+			newField.Type = "ID"
+			newStruct.IsNode = true
+		}
 
 		fields = append(fields, newField)
 	}
 
+	if hasNodeMethod && !newStruct.IsNode {
+		panic(fmt.Sprintf("Node '%s' has an %s method but no %s field", newStruct.Name, nodeMethod, nodeIDField))
+	}
+
 	if t.Structs == nil {
 		t.Structs = &[]Struct{}
 	}
 
 	*t.Structs = append(*t.Structs, Struct{
-		Name:   newStruct.Name,
-		Fields: &fields,
+		Name:        newStruct.Name,
+		Fields:      &fields,
+		GoName:      newStruct.GoName,
+		PkgPath:     newStruct.PkgPath,
+		Description: newStruct.Description,
+		IsNode:      newStruct.IsNode,
+		ParsedTag:   newStruct.ParsedTag,
 	})
 
-	// Remove the struct name from the pending list.
-	for i, pendingName := range *t.pendingStructTypeNames {
-		if pendingName == newStruct.Name {
-			*t.pendingStructTypeNames = append((*t.pendingStructTypeNames)[:i], (*t.pendingStructTypeNames)[i+1:]...)
-		}
-	}
+	node.pending = false
+
+	return newStruct.Name
 }
 
 // AddMap adds a map to the schema and recursively adds any discovered types
@@ -287,7 +1252,11 @@ func (t *TypeParser) internalAddStruct(m reflect.Type, depth int) {
 func (t *TypeParser) AddMap(name string, m interface{}) *TypeParser {
 	mapType := reflect.TypeOf(m)
 
-	return t.internalAddMap(name, mapType, 0)
+	t.walk(func() {
+		t.internalAddMap(name, reflectType{mapType}, 0)
+	})
+
+	return t
 }
 
 // AddStruct adds a struct to the schema and recursively adds any discovered types
@@ -300,35 +1269,172 @@ func (t *TypeParser) AddMap(name string, m interface{}) *TypeParser {
 // depth is calculated automatically.
 //
 // If you don't pass a struct type in (say a map, reflect.Type, etc.) then it will panic.
-func (t *TypeParser) AddStruct(s any, options *AddStructOptions) *TypeParser {
-	if options == nil {
-		options = &AddStructOptions{}
-	}
+func (t *TypeParser) AddStruct(s any, _ *AddStructOptions) *TypeParser {
+	return t.addStruct(reflectType{reflect.TypeOf(s)})
+}
 
-	structType := reflect.TypeOf(s)
+// AddSourceStruct is AddStruct for a struct type that was type checked from
+// source rather than reflected, i.e. by a code generator that can't import
+// the packages it generates a schema for.
+func (t *TypeParser) AddSourceStruct(s types.Type, _ *AddStructOptions) *TypeParser {
+	return t.addStruct(sourceType{s})
+}
 
+func (t *TypeParser) addStruct(structType goType) *TypeParser {
 	if structType.Kind() == reflect.Ptr {
 		structType = structType.Elem()
 	}
 
 	if structType.Kind() != reflect.Struct {
-		panic(fmt.Sprintf("AddStruct must be called with a struct type, '%s' is a '%s'", *options.Name, structType.Kind().String()))
+		panic(fmt.Sprintf("AddStruct must be called with a struct type, '%s' is a '%s'", structType.Name(), structType.Kind().String()))
 	}
 
-	if t.pendingStructTypeNames == nil {
-		t.pendingStructTypeNames = &[]string{}
-	}
+	t.walk(func() {
+		t.internalAddStruct(structType, fmt.Sprintf(unnamedStructTemplate, 0), 0)
+	})
 
-	t.internalAddStruct(structType, 0)
+	return t
+}
 
-	// Check there are no pending structs left and if so, panic because that means
-	// something went wrong and not all structs were added to the schema.
-	// If it's empty then we're good and we can clear the pending structs list entirely.
-	if len(*t.pendingStructTypeNames) > 0 {
-		panic(fmt.Sprintf("There are still pending structs to be added to the schema: %s", *t.pendingStructTypeNames))
-	} else {
-		t.pendingStructTypeNames = nil
+// walk runs an AddStruct, AddMap or AddEnum call, holding the lock of the parser, with an
+// empty graph of visited types. Once it is done there must be no pending types left, otherwise
+// something went wrong and not every type was added to the schema. The call works on copies
+// of the types, so when it panics, i.e. on a name collision, the parser is left as it was.
+func (t *TypeParser) walk(add func()) {
+	t.lock()
+	defer t.mu.Unlock()
+
+	structs, maps, enums := t.Structs, t.Maps, t.Enums
+	warnings, tagErrors := t.warnings, t.tagErrors
+	t.Structs, t.Maps, t.Enums = frozenCopy(structs), frozenCopy(maps), frozenCopy(enums)
+
+	t.visited = map[any]*typeNode{}
+	defer func() {
+		t.visited = nil
+
+		if r := recover(); r != nil {
+			t.Structs, t.Maps, t.Enums = structs, maps, enums
+			t.warnings, t.tagErrors = warnings, tagErrors
+
+			panic(r)
+		}
+	}()
+
+	add()
+
+	var pending []string
+
+	for _, node := range t.visited {
+		if node.pending {
+			pending = append(pending, node.name)
+		}
+	}
+
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		panic(fmt.Sprintf("There are still pending types to be added to the schema: %s", pending))
 	}
+}
+
+// AddEnum adds an enum to the schema. Go doesn't have enum types, only named
+// constants, so enums can't be discovered and have to be added by hand. When a Go
+// type already has the name of the enum, with NameCollisionPrefix it is prefixed with
+// the name of its package unless it has an alias, otherwise the parser panics.
+func (t *TypeParser) AddEnum(e Enum) *TypeParser {
+	t.walk(func() {
+		if other, taken := t.typeNamed(e.Name); taken {
+			prefix := t.options != nil && t.options.NameCollisions == NameCollisionPrefix
+			if !prefix || other.goName == "" || t.isAliased(other) {
+				panic(fmt.Sprintf("The enum '%s' and %s are both named '%s' in the schema, give one of them an alias "+
+					"or prefix it with the name of its package", e.Name, other, e.Name))
+			}
+
+			t.renameType(e.Name, other.prefixedName(e.Name))
+		}
+
+		if t.Enums == nil {
+			t.Enums = &[]Enum{}
+		}
+
+		*t.Enums = append(*t.Enums, e)
+	})
 
 	return t
 }
+
+// parseTag parses the graphql tag of a field and keeps its warnings. When the tag isn't valid
+// the error is kept for Validate, and as much of the tag as could be parsed is used.
+func (t *TypeParser) parseTag(tag string, field string) *tagparser.Tag {
+	parsed, err := tagparser.Parse(tag, field)
+
+	var syntaxError *tagparser.SyntaxError
+	if errors.As(err, &syntaxError) {
+		t.tagErrors = append(t.tagErrors, syntaxError)
+	}
+
+	if parsed != nil {
+		t.warnings = append(t.warnings, parsed.Warnings...)
+	}
+
+	return parsed
+}
+
+// Warnings returns the problems found in the types added so far that don't prevent
+// the schema from being built, i.e. unknown options in graphql tags.
+func (t *TypeParser) Warnings() []string {
+	return t.Snapshot().warnings
+}
+
+// lock takes the lock of a parser to add types to it, which panics on a snapshot.
+func (t *TypeParser) lock() {
+	if t.frozen {
+		panic("Types can't be added to a snapshot of a TypeParser, add them to the parser it was taken from")
+	}
+
+	t.mu.Lock()
+}
+
+// Snapshot returns the types found so far as a parser that can't be changed: types added
+// to the parser afterwards aren't in the snapshot, and adding types to the snapshot panics.
+// The schema and the other outputs are built from a snapshot, so they can be printed while
+// other goroutines keep adding types. The snapshot shares the fields of the types with the
+// parser, which are never changed once a type is added, so they must not be modified.
+func (t *TypeParser) Snapshot() *TypeParser {
+	if t.frozen {
+		return t
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return &TypeParser{
+		Structs:   frozenCopy(t.Structs),
+		Maps:      frozenCopy(t.Maps),
+		Enums:     frozenCopy(t.Enums),
+		options:   t.options,
+		frozen:    true,
+		warnings:  append([]string(nil), t.warnings...),
+		tagErrors: append([]*tagparser.SyntaxError(nil), t.tagErrors...),
+	}
+}
+
+// frozenCopy copies the types of a parser, for a snapshot or for a walk to work on, so that changing one doesn't change the other.
+func frozenCopy[T any](types *[]T) *[]T {
+	if types == nil {
+		return nil
+	}
+
+	copied := append([]T(nil), *types...)
+
+	return &copied
+}
+
+// isIDKind returns whether a Go kind can hold an ID, which is serialized as a string.
+func isIDKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
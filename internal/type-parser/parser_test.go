@@ -1,15 +1,27 @@
 package typeparser_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	tagparser "github.com/warpspeed-cloud/graphql-schema-generator/internal/graphql-tag-parser"
 	"github.com/warpspeed-cloud/graphql-schema-generator/internal/ptr"
+	sourceloader "github.com/warpspeed-cloud/graphql-schema-generator/internal/source-loader"
 	typeparser "github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser"
+	"github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser/testdata/billing"
+	"github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser/testdata/library"
+	"github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser/testdata/shipping"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
 )
 
+const pkgPath = "github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser_test"
+
 type Test struct {
 	name     string
 	actual   *typeparser.TypeParser
@@ -156,133 +168,129 @@ type UserDocument struct {
 
 func TestBuilderStructSuite(t *testing.T) {
 	expectedMeta := typeparser.Struct{
-		Name: "Struct1",
+		Name: "UserDocumentStruct1",
 		Fields: &[]typeparser.TypeDescriptor{
 			{
 				Name:            ptr.Of("author"),
+				GoName:          "Author",
 				Type:            "string",
 				IncludeInOutput: true,
 				ParsedTag: &tagparser.Tag{
-					Options: map[string]string{
-						"description": "The author of the document",
-					},
+					Description: ptr.Of("The author of the document"),
 				},
 			},
 			{
 				Name:            ptr.Of("lastModified"),
+				GoName:          "LastModified",
 				Type:            "string",
 				IncludeInOutput: true,
 				ParsedTag: &tagparser.Tag{
-					Options: map[string]string{
-						"description": "The last time the document was modified",
-					},
+					Description: ptr.Of("The last time the document was modified"),
 				},
 			},
 		},
 	}
 	expectedUserDocument := typeparser.Struct{
-		Name: "UserDocument",
+		Name:    "UserDocument",
+		GoName:  "typeparser_test.UserDocument",
+		PkgPath: pkgPath,
 		Fields: &[]typeparser.TypeDescriptor{
 			{
 				Name:            ptr.Of("id"),
+				GoName:          "ID",
 				Type:            "string",
 				IncludeInOutput: true,
 				ParsedTag: &tagparser.Tag{
-					Options: map[string]string{
-						"description": "The ID of the document",
-					},
+					Description: ptr.Of("The ID of the document"),
 				},
 			},
 			{
 				Name:            ptr.Of("name"),
+				GoName:          "Name",
 				Type:            "string",
 				IncludeInOutput: true,
 				ParsedTag: &tagparser.Tag{
-					Options: map[string]string{
-						"description": "The name of the document",
-					},
+					Description: ptr.Of("The name of the document"),
 				},
 			},
 			{
 				Name:            ptr.Of("editors"),
+				GoName:          "Editors",
 				Type:            "User",
 				IsPointer:       false,
 				IsSlice:         true,
 				IncludeInOutput: true,
 				ParsedTag: &tagparser.Tag{
-					Options: map[string]string{
-						"description": "The editors of the document",
-					},
+					Description: ptr.Of("The editors of the document"),
 				},
 			},
 			{
 				Name:            ptr.Of("meta"),
+				GoName:          "Meta",
 				Type:            "UserDocumentStruct1",
 				IsPointer:       false,
 				IncludeInOutput: true,
 				IsStruct:        true,
 				ParsedTag: &tagparser.Tag{
-					Options: map[string]string{
-						"description": "The meta data of the document",
-					},
+					Description: ptr.Of("The meta data of the document"),
 				},
 			},
 		},
 	}
 	expectedUser := typeparser.Struct{
-		Name: "User",
+		Name:    "User",
+		GoName:  "typeparser_test.User",
+		PkgPath: pkgPath,
 		Fields: &[]typeparser.TypeDescriptor{
 			{
 				Name:            ptr.Of("id"),
+				GoName:          "ID",
 				Type:            "string",
 				IncludeInOutput: true,
 				ParsedTag: &tagparser.Tag{
-					Options: map[string]string{
-						"description": "The ID of the user",
-					},
+					Description: ptr.Of("The ID of the user"),
 				},
 			},
 			{
 				Name:            ptr.Of("username"),
+				GoName:          "Username",
 				Type:            "string",
 				IncludeInOutput: true,
 				ParsedTag: &tagparser.Tag{
-					Options: map[string]string{
-						"description": "The username of the user",
-						"decorators":  "[+unique()]",
-					},
+					Description: ptr.Of("The username of the user"),
+					Directives:  []tagparser.Directive{{Name: "unique"}},
 				},
 			},
 			{
 				Name:            ptr.Of("Password"),
+				GoName:          "Password",
 				Type:            "string",
 				IncludeInOutput: false,
 				ParsedTag:       nil,
 			},
 			{
 				Name:            ptr.Of("email"),
+				GoName:          "Email",
 				Type:            "string",
 				IsPointer:       true,
 				IncludeInOutput: true,
 				ParsedTag: &tagparser.Tag{
-					Options: map[string]string{
-						"description": "The email of the user",
-					},
+					Description: ptr.Of("The email of the user"),
 				},
 			},
 			{
 				Name:            ptr.Of("phone"),
+				GoName:          "Phone",
 				Type:            "string",
 				IsPointer:       true,
 				IncludeInOutput: true,
 				ParsedTag: &tagparser.Tag{
-					Options: map[string]string{
-						"description": "The phone number of the user",
-					},
+					Description: ptr.Of("The phone number of the user"),
 				},
 			},
 			{
-				Name: ptr.Of("roles"),
+				Name:   ptr.Of("roles"),
+				GoName: "Roles",
 				// This is a string because we cannot get the named type
 				// of a "enum" like type in Go (it's just a string.)
 				Type:            "string",
@@ -290,21 +298,18 @@ func TestBuilderStructSuite(t *testing.T) {
 				IsSlice:         true,
 				IncludeInOutput: true,
 				ParsedTag: &tagparser.Tag{
-					Options: map[string]string{
-						"description": "The roles of the user",
-					},
+					Description: ptr.Of("The roles of the user"),
 				},
 			},
 			{
 				Name:            ptr.Of("documents"),
+				GoName:          "Documents",
 				Type:            "UserDocument",
 				IsPointer:       false,
 				IsSlice:         true,
 				IncludeInOutput: true,
 				ParsedTag: &tagparser.Tag{
-					Options: map[string]string{
-						"description": "The documents of the user",
-					},
+					Description: ptr.Of("The documents of the user"),
 				},
 			},
 		},
@@ -347,37 +352,36 @@ func TestBuilder_Map(t *testing.T) {
 			expected: &typeparser.TypeParser{
 				Structs: &[]typeparser.Struct{
 					{
-						Name: "Project",
+						Name:    "Project",
+						GoName:  "typeparser_test.Project",
+						PkgPath: pkgPath,
 						Fields: &[]typeparser.TypeDescriptor{
 							{
 								Name:            ptr.Of("id"),
+								GoName:          "ID",
 								Type:            "string",
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The ID of the project",
-									},
+									Description: ptr.Of("The ID of the project"),
 								},
 							},
 							{
 								Name:            ptr.Of("name"),
+								GoName:          "Name",
 								Type:            "string",
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The name of the project",
-									},
+									Description: ptr.Of("The name of the project"),
 								},
 							},
 							{
 								Name:            ptr.Of("meta"),
+								GoName:          "Meta",
 								Type:            "ProjectMeta",
 								IsMap:           true,
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The meta data of the project",
-									},
+									Description: ptr.Of("The meta data of the project"),
 								},
 							},
 						},
@@ -448,64 +452,62 @@ func TestBuilder_ComplexDVDStore(t *testing.T) {
 			expected: &typeparser.TypeParser{
 				Structs: &[]typeparser.Struct{
 					{
-						Name: "Title",
+						Name:    "Title",
+						GoName:  "typeparser_test.Title",
+						PkgPath: pkgPath,
 						Fields: &[]typeparser.TypeDescriptor{
 							{
 								Name:            ptr.Of("id"),
+								GoName:          "ID",
 								Type:            "string",
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The ID of the title",
-									},
+									Description: ptr.Of("The ID of the title"),
 								},
 							},
 							{
 								Name:            ptr.Of("name"),
+								GoName:          "Name",
 								Type:            "string",
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The name of the title",
-									},
+									Description: ptr.Of("The name of the title"),
 								},
 							},
 							{
 								Name:            ptr.Of("rentalPrice"),
+								GoName:          "RentalPrice",
 								Type:            "int",
 								IsPointer:       true,
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The rental price of the title",
-									},
+									Description: ptr.Of("The rental price of the title"),
 								},
 							},
 							{
 								Name:            ptr.Of("buyPrice"),
+								GoName:          "BuyPrice",
 								Type:            "int",
 								IsPointer:       true,
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The buy price of the title",
-									},
+									Description: ptr.Of("The buy price of the title"),
 								},
 							},
 							{
 								Name:            ptr.Of("credits"),
+								GoName:          "Credits",
 								Type:            "TitleCredits",
 								IsPointer:       true,
 								IsMap:           true,
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The credits of the title",
-									},
+									Description: ptr.Of("The credits of the title"),
 								},
 							},
 							{
 								Name:            ptr.Of("headOfficeReference"),
+								GoName:          "headOfficeReference",
 								Type:            "string",
 								IncludeInOutput: false,
 								ParsedTag:       nil,
@@ -513,57 +515,54 @@ func TestBuilder_ComplexDVDStore(t *testing.T) {
 						},
 					},
 					{
-						Name: "DvdStore",
+						Name:    "DvdStore",
+						GoName:  "typeparser_test.DvdStore",
+						PkgPath: pkgPath,
 						Fields: &[]typeparser.TypeDescriptor{
 							{
 								Name:            ptr.Of("id"),
+								GoName:          "ID",
 								Type:            "string",
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The ID of the DVD store",
-									},
+									Description: ptr.Of("The ID of the DVD store"),
 								},
 							},
 							{
 								Name:            ptr.Of("name"),
+								GoName:          "Name",
 								Type:            "string",
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The name of the DVD store",
-									},
+									Description: ptr.Of("The name of the DVD store"),
 								},
 							},
 							{
 								Name:            ptr.Of("address"),
+								GoName:          "Address",
 								Type:            "string",
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The address of the DVD store",
-									},
+									Description: ptr.Of("The address of the DVD store"),
 								},
 							},
 							{
 								Name:            ptr.Of("phoneNumber"),
+								GoName:          "PhoneNumber",
 								Type:            "string",
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The phone number of the DVD store",
-									},
+									Description: ptr.Of("The phone number of the DVD store"),
 								},
 							},
 							{
 								Name:            ptr.Of("availableTitles"),
+								GoName:          "AvailableTitles",
 								Type:            "DvdStoreAvailableTitles",
 								IsMap:           true,
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The available titles of the DVD store",
-									},
+									Description: ptr.Of("The available titles of the DVD store"),
 								},
 							},
 						},
@@ -658,138 +657,140 @@ func TestEcommerceStore(t *testing.T) {
 			expected: &typeparser.TypeParser{
 				Structs: &[]typeparser.Struct{
 					{
-						Name: "EcommerceStore",
+						Name:    "EcommerceStore",
+						GoName:  "typeparser_test.EcommerceStore",
+						PkgPath: pkgPath,
 						Fields: &[]typeparser.TypeDescriptor{
 							{
 								Name:            ptr.Of("id"),
+								GoName:          "ID",
 								Type:            "string",
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The ID of the ecommerce store",
-									},
+									Description: ptr.Of("The ID of the ecommerce store"),
 								},
 							},
 							{
 								Name:            ptr.Of("name"),
+								GoName:          "Name",
 								Type:            "string",
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The name of the ecommerce store",
-									},
+									Description: ptr.Of("The name of the ecommerce store"),
 								},
 							},
 							{
 								Name:            ptr.Of("address"),
+								GoName:          "Address",
 								Type:            "string",
 								IsPointer:       true,
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The address of the ecommerce store",
-									},
+									Description: ptr.Of("The address of the ecommerce store"),
 								},
 							},
 							{
 								Name:            ptr.Of("phoneNumber"),
+								GoName:          "PhoneNumber",
 								Type:            "string",
 								IsPointer:       true,
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The phone number of the ecommerce store",
-									},
+									Description: ptr.Of("The phone number of the ecommerce store"),
 								},
 							},
 							{
 								Name:            ptr.Of("products"),
+								GoName:          "Products",
 								Type:            "Product",
 								IsSlice:         true,
 								IsPointer:       true,
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The products of the ecommerce store",
-									},
+									Description: ptr.Of("The products of the ecommerce store"),
 								},
 							},
 						},
 					},
 					{
-						Name: "ProductVariant",
+						Name:    "ProductVariant",
+						GoName:  "typeparser_test.ProductVariant",
+						PkgPath: pkgPath,
 						Fields: &[]typeparser.TypeDescriptor{
 							{
 								Name:            ptr.Of("id"),
+								GoName:          "ID",
 								Type:            "string",
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The ID of the product variant",
-									},
+									Description: ptr.Of("The ID of the product variant"),
 								},
 							},
 							{
 								Name:            ptr.Of("priceExTax"),
+								GoName:          "PriceExTax",
 								Type:            "int",
 								IsPointer:       true,
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The price of the product variant excluding tax",
-									},
+									Description: ptr.Of("The price of the product variant excluding tax"),
 								},
 							},
 							{
 								Name:            ptr.Of("availableRegions"),
+								GoName:          "AvailableRegions",
 								Type:            "PlatformRegions",
 								IsMap:           true,
 								IsPointer:       true,
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The available regions of the product",
-									},
+									Description: ptr.Of("The available regions of the product"),
 								},
 							},
 							{
 								Name:            ptr.Of("images"),
+								GoName:          "Images",
 								Type:            "ProductImage",
 								IsSlice:         true,
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The images of the product variant",
-									},
+									Description: ptr.Of("The images of the product variant"),
 								},
 							},
 						},
 					},
 					{
-						Name: "ProductImage",
+						Name:    "ProductImage",
+						GoName:  "typeparser_test.ProductImage",
+						PkgPath: pkgPath,
 						Fields: &[]typeparser.TypeDescriptor{
 							{
 								Name:            ptr.Of("ThumbURL"),
+								GoName:          "ThumbURL",
 								Type:            "string",
 								IncludeInOutput: true,
 							},
 							{
 								Name:            ptr.Of("Featured"),
+								GoName:          "Featured",
 								Type:            "bool",
 								IncludeInOutput: true,
 							},
 							{
 								Name:            ptr.Of("ThumbWidth"),
+								GoName:          "ThumbWidth",
 								Type:            "int",
 								IncludeInOutput: true,
 							},
 							{
 								Name:            ptr.Of("ThumbHeight"),
+								GoName:          "ThumbHeight",
 								Type:            "int",
 								IncludeInOutput: true,
 							},
 							{
 								Name:            ptr.Of("AltText"),
+								GoName:          "AltText",
 								Type:            "string",
 								IncludeInOutput: true,
 							},
@@ -801,93 +802,87 @@ func TestEcommerceStore(t *testing.T) {
 						},
 					},
 					{
-						Name: "Product",
+						Name:    "Product",
+						GoName:  "typeparser_test.Product",
+						PkgPath: pkgPath,
 						Fields: &[]typeparser.TypeDescriptor{
 							{
 								Name:            ptr.Of("id"),
+								GoName:          "ID",
 								Type:            "string",
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The ID of the product",
-									},
+									Description: ptr.Of("The ID of the product"),
 								},
 							},
 							{
 								Name:            ptr.Of("name"),
+								GoName:          "Name",
 								Type:            "string",
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The name of the product",
-									},
+									Description: ptr.Of("The name of the product"),
 								},
 							},
 							{
 								Name:            ptr.Of("description"),
+								GoName:          "Description",
 								Type:            "string",
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The description of the product",
-									},
+									Description: ptr.Of("The description of the product"),
 								},
 							},
 							{
 								Name:            ptr.Of("priceExTax"),
+								GoName:          "PriceExTax",
 								Type:            "int",
 								IsPointer:       true,
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The price of the product excluding tax",
-									},
+									Description: ptr.Of("The price of the product excluding tax"),
 								},
 							},
 							{
 								Name:            ptr.Of("availableRegions"),
+								GoName:          "AvailableRegions",
 								Type:            "ProductAvailableRegions",
 								IsMap:           true,
 								IsPointer:       true,
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The available regions of the product",
-									},
+									Description: ptr.Of("The available regions of the product"),
 								},
 							},
 							{
 								Name:            ptr.Of("active"),
+								GoName:          "Active",
 								Type:            "bool",
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The active status of the product",
-									},
+									Description: ptr.Of("The active status of the product"),
 								},
 							},
 							{
 								Name:            ptr.Of("variants"),
+								GoName:          "Variants",
 								Type:            "ProductVariant",
 								IsSlice:         true,
 								IsPointer:       true,
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The variants of the product",
-									},
+									Description: ptr.Of("The variants of the product"),
 								},
 							},
 							{
 								Name:            ptr.Of("images"),
+								GoName:          "Images",
 								Type:            "ProductImage",
 								IsSlice:         true,
 								IsPointer:       true,
 								IncludeInOutput: true,
 								ParsedTag: &tagparser.Tag{
-									Options: map[string]string{
-										"description": "The images of the product",
-									},
+									Description: ptr.Of("The images of the product"),
 								},
 							},
 						},
@@ -934,3 +929,1639 @@ func TestEcommerceStore(t *testing.T) {
 		})
 	}
 }
+
+// Invoice is sent to a customer.
+//
+// It is generated at the end of every billing period.
+type Invoice struct {
+	// The ID of the invoice.
+	ID string `json:"id"`
+	// The total of the invoice,
+	// including tax.
+	Total int  `json:"total" graphql:"description=The total in cents"`
+	Paid  bool `json:"paid"`
+}
+
+func TestDocComments(t *testing.T) {
+	parser := typeparser.NewTypeParser(&typeparser.TypeParserOptions{ParseDocComments: true}).AddStruct(Invoice{}, nil)
+
+	// The parser itself holds its options and loaded docs, so only the discovered structs are compared.
+	assert.Equal(t, &[]typeparser.Struct{
+		{
+			Name:        "Invoice",
+			GoName:      "typeparser_test.Invoice",
+			PkgPath:     pkgPath,
+			Description: ptr.Of("Invoice is sent to a customer.\n\nIt is generated at the end of every billing period."),
+			Fields: &[]typeparser.TypeDescriptor{
+				{
+					Name:            ptr.Of("id"),
+					GoName:          "ID",
+					Type:            "string",
+					IncludeInOutput: true,
+					Description:     ptr.Of("The ID of the invoice."),
+				},
+				{
+					Name:            ptr.Of("total"),
+					GoName:          "Total",
+					Type:            "int",
+					IncludeInOutput: true,
+					Description:     ptr.Of("The total of the invoice, including tax."),
+					ParsedTag: &tagparser.Tag{
+						Description: ptr.Of("The total in cents"),
+					},
+				},
+				{
+					Name:            ptr.Of("paid"),
+					GoName:          "Paid",
+					Type:            "bool",
+					IncludeInOutput: true,
+				},
+			},
+		},
+	}, parser.Structs)
+
+	fields := *(*parser.Structs)[0].Fields
+	assert.Equal(t, ptr.Of("The ID of the invoice."), fields[0].GetDescription())
+	assert.Equal(t, ptr.Of("The total in cents"), fields[1].GetDescription())
+	assert.Nil(t, fields[2].GetDescription())
+}
+
+func TestAddSourceStruct(t *testing.T) {
+	packages, err := sourceloader.Load(".", "./testdata/library")
+	assert.NoError(t, err)
+
+	book, err := packages.LookupStruct("Book")
+	assert.NoError(t, err)
+
+	fromSource := typeparser.NewTypeParser(nil).AddSourceStruct(book, nil)
+
+	assert.Equal(t, typeparser.NewTypeParser(nil).AddStruct(library.Book{}, nil), fromSource)
+	assert.True(t, (*fromSource.Structs)[len(*fromSource.Structs)-1].IsNode)
+}
+
+func TestAddSourceStruct_Generics(t *testing.T) {
+	packages, err := sourceloader.Load(".", "./testdata/documents")
+	assert.NoError(t, err)
+
+	document, err := packages.LookupStruct("Document")
+	assert.NoError(t, err)
+
+	// The path of the package of the type argument, .../testdata/yaml.v3, has dots.
+	s := typeparser.NewTypeParser(nil).AddSourceStruct(document, nil).Schema(nil)
+
+	var names []string
+	for _, t := range s.Types {
+		names = append(names, t.TypeName())
+	}
+
+	assert.Equal(t, []string{"Node", "NodeBox", "Document"}, names)
+}
+
+type Secret struct {
+	Value string
+}
+
+type Account struct {
+	AccountID  string               `json:"account_id"`
+	ThumbURL   string               `json:"ThumbURL"`
+	Balance    int64                `json:"balance"`
+	CreatedAt  time.Time            `json:"createdAt"`
+	Secret     *Secret              `json:"secret"`
+	LastLogins map[string]time.Time `json:"lastLogins"`
+}
+
+func TestTypeParserOptions(t *testing.T) {
+	parser := typeparser.NewTypeParser(&typeparser.TypeParserOptions{
+		Scalars:      map[string]string{"time.Time": "DateTime", "int64": "BigInt"},
+		FieldNaming:  typeparser.FieldNamingSnakeCase,
+		ExcludeTypes: []string{"Secret"},
+	}).AddStruct(Account{}, nil)
+
+	// Neither time.Time nor the excluded Secret are walked.
+	assert.Equal(t, &[]typeparser.Struct{
+		{
+			Name:    "Account",
+			GoName:  "typeparser_test.Account",
+			PkgPath: pkgPath,
+			Fields: &[]typeparser.TypeDescriptor{
+				{Name: ptr.Of("account_id"), GoName: "AccountID", Type: "string", IncludeInOutput: true},
+				{Name: ptr.Of("thumb_url"), GoName: "ThumbURL", Type: "string", IncludeInOutput: true, JSONName: ptr.Of("ThumbURL")},
+				{Name: ptr.Of("balance"), GoName: "Balance", Type: "BigInt", IncludeInOutput: true},
+				{Name: ptr.Of("created_at"), GoName: "CreatedAt", Type: "DateTime", IncludeInOutput: true, JSONName: ptr.Of("createdAt")},
+				{Name: ptr.Of("secret"), GoName: "Secret", Type: "Secret", IsPointer: true, IncludeInOutput: false},
+				{Name: ptr.Of("last_logins"), GoName: "LastLogins", Type: "AccountLastLogins", IsMap: true, IncludeInOutput: true, JSONName: ptr.Of("lastLogins")},
+			},
+		},
+	}, parser.Structs)
+
+	assert.Equal(t, &[]typeparser.Map{
+		{
+			Name: "AccountLastLogins",
+			Key:  typeparser.TypeDescriptor{Type: "string"},
+			Val:  typeparser.TypeDescriptor{Type: "DateTime"},
+		},
+	}, parser.Maps)
+}
+
+func TestFieldNaming(t *testing.T) {
+	tests := []struct {
+		name       string
+		naming     typeparser.FieldNaming
+		fieldNames []string
+	}{
+		{
+			name:       "JSON",
+			naming:     typeparser.FieldNamingJSON,
+			fieldNames: []string{"account_id", "ThumbURL", "balance", "createdAt", "secret", "lastLogins"},
+		},
+		{
+			name:       "Camel case",
+			naming:     typeparser.FieldNamingCamelCase,
+			fieldNames: []string{"accountId", "thumbUrl", "balance", "createdAt", "secret", "lastLogins"},
+		},
+		{
+			name:       "Snake case",
+			naming:     typeparser.FieldNamingSnakeCase,
+			fieldNames: []string{"account_id", "thumb_url", "balance", "created_at", "secret", "last_logins"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := typeparser.NewTypeParser(&typeparser.TypeParserOptions{
+				FieldNaming:  test.naming,
+				ExcludeTypes: []string{"Secret"},
+				Scalars:      map[string]string{"time.Time": "DateTime"},
+			}).AddStruct(Account{}, nil)
+
+			var fieldNames []string
+			for _, field := range *(*parser.Structs)[0].Fields {
+				fieldNames = append(fieldNames, *field.Name)
+			}
+
+			assert.Equal(t, test.fieldNames, fieldNames)
+		})
+	}
+}
+
+type Team struct {
+	Name    string            `json:"name" graphql:"description=The name of the team,decorators=[+auth(role: \"admin\", level: 2), +unique]"`
+	Members []*Account        `json:"members"`
+	Labels  map[string]string `json:"labels"`
+}
+
+func TestSchema(t *testing.T) {
+	parser := typeparser.NewTypeParser(&typeparser.TypeParserOptions{
+		Scalars:      map[string]string{"time.Time": "DateTime", "int64": "BigInt"},
+		ExcludeTypes: []string{"Secret"},
+	}).AddStruct(Team{}, nil).AddEnum(typeparser.Enum{
+		Name:   "Role",
+		Values: []typeparser.EnumKeyPairOptions{{Key: "ADMIN", Value: 0, Description: ptr.Of("Can do anything")}},
+	})
+
+	account := &schema.ObjectType{
+		Name: "Account",
+		Fields: []*schema.Field{
+			{Name: "account_id", Type: schema.NonNullOf(schema.Named("String"))},
+			{Name: "ThumbURL", Type: schema.NonNullOf(schema.Named("String"))},
+			{Name: "balance", Type: schema.NonNullOf(schema.Named("BigInt"))},
+			{Name: "createdAt", Type: schema.NonNullOf(schema.Named("DateTime"))},
+			{Name: "lastLogins", Type: schema.NonNullOf(schema.ListOf(schema.NonNullOf(schema.Named("AccountLastLogins"))))},
+		},
+	}
+	team := &schema.ObjectType{
+		Name: "Team",
+		Fields: []*schema.Field{
+			{
+				Name:        "name",
+				Description: "The name of the team",
+				Type:        schema.NonNullOf(schema.Named("String")),
+				Directives: []*schema.AppliedDirective{
+					{Name: "auth", Arguments: []*schema.Argument{{Name: "role", Value: `"admin"`}, {Name: "level", Value: "2"}}},
+					{Name: "unique"},
+				},
+			},
+			{Name: "members", Type: schema.NonNullOf(schema.ListOf(schema.Named("Account")))},
+			{Name: "labels", Type: schema.NonNullOf(schema.ListOf(schema.NonNullOf(schema.Named("TeamLabels"))))},
+		},
+	}
+	role := &schema.EnumType{
+		Name:   "Role",
+		Values: []*schema.EnumValue{{Name: "ADMIN", Description: "Can do anything"}},
+	}
+	directives := []*schema.Directive{
+		{
+			Name: "auth",
+			Arguments: []*schema.InputValue{
+				{Name: "role", Type: schema.NonNullOf(schema.Named("String"))},
+				{Name: "level", Type: schema.Named("Int")},
+			},
+			Locations: []string{"FIELD_DEFINITION"},
+		},
+		{Name: "unique", Locations: []string{"FIELD_DEFINITION"}},
+	}
+
+	assert.Equal(t, &schema.Schema{
+		Types: []schema.Type{
+			account,
+			team,
+			&schema.ObjectType{
+				Name: "AccountLastLogins",
+				Fields: []*schema.Field{
+					{Name: "key", Type: schema.NonNullOf(schema.Named("String"))},
+					{Name: "value", Type: schema.NonNullOf(schema.Named("DateTime"))},
+				},
+			},
+			&schema.ObjectType{
+				Name: "TeamLabels",
+				Fields: []*schema.Field{
+					{Name: "key", Type: schema.NonNullOf(schema.Named("String"))},
+					{Name: "value", Type: schema.NonNullOf(schema.Named("String"))},
+				},
+			},
+			role,
+			&schema.ScalarType{Name: "BigInt"},
+			&schema.ScalarType{Name: "DateTime"},
+		},
+		Directives: directives,
+	}, parser.Schema(&typeparser.SchemaOptions{Directives: directives}))
+
+	// With a map scalar maps aren't types of their own.
+	scalarMaps := parser.Schema(&typeparser.SchemaOptions{MapScalar: "JSON", Directives: directives})
+	assert.Len(t, scalarMaps.Types, 6)
+	assert.Equal(t, "JSON!", scalarMaps.Type("Team").(*schema.ObjectType).Field("labels").Type.String())
+	assert.Equal(t, &schema.ScalarType{Name: "JSON"}, scalarMaps.Type("JSON"))
+}
+
+type Member struct {
+	Email string `json:"email"`
+}
+
+type Board struct {
+	Owners   []Member  `json:"owners" graphql:"description=Who owns the board, connection"`
+	Members  *[]Member `json:"members" graphql:"connection"`
+	Labels   []string  `json:"labels" graphql:"connection"`
+	Archived []*Board  `json:"archived"`
+}
+
+func TestSchema_Connections(t *testing.T) {
+	s := typeparser.NewTypeParser(nil).AddStruct(Board{}, nil).Schema(nil)
+
+	var names []string
+	for _, t := range s.Types {
+		names = append(names, t.TypeName())
+	}
+
+	assert.Equal(t, []string{"Member", "Board", "MemberConnection", "MemberEdge", "StringConnection", "StringEdge", "PageInfo"}, names)
+
+	board := s.Type("Board").(*schema.ObjectType)
+	assert.Equal(t, &schema.Field{
+		Name:        "owners",
+		Description: "Who owns the board",
+		Arguments: []*schema.InputValue{
+			{Name: "first", Type: schema.Named("Int")},
+			{Name: "after", Type: schema.Named("String")},
+			{Name: "last", Type: schema.Named("Int")},
+			{Name: "before", Type: schema.Named("String")},
+		},
+		Type: schema.NonNullOf(schema.Named("MemberConnection")),
+	}, board.Field("owners"))
+	assert.Equal(t, "MemberConnection", board.Field("members").Type.String())
+	assert.Equal(t, "StringConnection!", board.Field("labels").Type.String())
+	assert.Equal(t, "[Board]!", board.Field("archived").Type.String())
+
+	assert.Equal(t, &schema.ObjectType{
+		Name: "MemberConnection",
+		Fields: []*schema.Field{
+			{Name: "edges", Type: schema.NonNullOf(schema.ListOf(schema.NonNullOf(schema.Named("MemberEdge"))))},
+			{Name: "pageInfo", Type: schema.NonNullOf(schema.Named("PageInfo"))},
+		},
+	}, s.Type("MemberConnection"))
+	assert.Equal(t, &schema.ObjectType{
+		Name: "MemberEdge",
+		Fields: []*schema.Field{
+			{Name: "cursor", Type: schema.NonNullOf(schema.Named("String"))},
+			{Name: "node", Type: schema.NonNullOf(schema.Named("Member"))},
+		},
+	}, s.Type("MemberEdge"))
+	assert.Equal(t, "String", s.Type("PageInfo").(*schema.ObjectType).Field("endCursor").Type.String())
+}
+
+type Invalid struct {
+	Name string `json:"name" graphql:"connection"`
+}
+
+func TestSchema_InvalidConnection(t *testing.T) {
+	parser := typeparser.NewTypeParser(nil).AddStruct(Invalid{}, nil)
+
+	assert.PanicsWithValue(t, "connection can only be used on slices, 'Invalid.name' is a 'String!'", func() {
+		parser.Schema(nil)
+	})
+}
+
+type Post struct {
+	Slug  string `json:"slug" graphql:"node"`
+	Title string `json:"title"`
+}
+
+type Comment struct {
+	ID   int64  `json:"id"`
+	Post *Post  `json:"post"`
+	Body string `json:"body"`
+}
+
+func (Comment) IsNode() {}
+
+type Query struct {
+	Posts []Post `json:"posts"`
+}
+
+func TestSchema_Nodes(t *testing.T) {
+	s := typeparser.NewTypeParser(nil).AddStruct(Comment{}, nil).Schema(nil)
+
+	var names []string
+	for _, t := range s.Types {
+		names = append(names, t.TypeName())
+	}
+
+	assert.Equal(t, []string{"Post", "Comment", "Node", "Query"}, names)
+	assert.Equal(t, []string{"Node"}, s.Type("Post").(*schema.ObjectType).Interfaces)
+	assert.Equal(t, "ID!", s.Type("Post").(*schema.ObjectType).Field("slug").Type.String())
+	assert.Equal(t, []string{"Node"}, s.Type("Comment").(*schema.ObjectType).Interfaces)
+	assert.Equal(t, "ID!", s.Type("Comment").(*schema.ObjectType).Field("id").Type.String())
+
+	assert.Equal(t, &schema.InterfaceType{
+		Name:        "Node",
+		Description: "An object with a global ID.",
+		Fields: []*schema.Field{
+			{Name: "id", Description: "The global ID of the object.", Type: schema.NonNullOf(schema.Named("ID"))},
+		},
+	}, s.Type("Node"))
+
+	assert.Equal(t, &schema.ObjectType{
+		Name: "Query",
+		Fields: []*schema.Field{
+			{
+				Name:        "node",
+				Description: "Fetches an object given its global ID.",
+				Arguments:   []*schema.InputValue{{Name: "id", Type: schema.NonNullOf(schema.Named("ID"))}},
+				Type:        schema.Named("Node"),
+			},
+			{
+				Name:        "nodes",
+				Description: "Fetches objects given their global IDs.",
+				Arguments:   []*schema.InputValue{{Name: "ids", Type: schema.NonNullOf(schema.ListOf(schema.NonNullOf(schema.Named("ID"))))}},
+				Type:        schema.NonNullOf(schema.ListOf(schema.Named("Node"))),
+			},
+		},
+	}, s.Type("Query"))
+
+	// The node fields are added to an existing Query type.
+	s = typeparser.NewTypeParser(nil).AddStruct(Query{}, nil).Schema(nil)
+	query := s.Type("Query").(*schema.ObjectType)
+	assert.Len(t, query.Fields, 3)
+	assert.Equal(t, "posts", query.Fields[0].Name)
+	assert.Equal(t, "nodes", query.Fields[2].Name)
+
+	// Without nodes there is neither a Node interface nor a Query type.
+	s = typeparser.NewTypeParser(nil).AddStruct(Member{}, nil).Schema(nil)
+	assert.Nil(t, s.Type("Node"))
+	assert.Nil(t, s.Type("Query"))
+}
+
+type BadID struct {
+	ID *string `json:"id" graphql:"node"`
+}
+
+type MissingID struct {
+	Name string `json:"name"`
+}
+
+func (MissingID) IsNode() {}
+
+func TestNodes_Invalid(t *testing.T) {
+	assert.PanicsWithValue(t, "The ID of node 'BadID' must be a string or an integer, 'ID' isn't", func() {
+		typeparser.NewTypeParser(nil).AddStruct(BadID{}, nil)
+	})
+
+	assert.PanicsWithValue(t, "Node 'MissingID' has an IsNode method but no ID field", func() {
+		typeparser.NewTypeParser(nil).AddStruct(MissingID{}, nil)
+	})
+}
+
+type Order struct {
+	ID         string    `json:"id"`
+	CustomerID *int64    `json:"customerId"`
+	ItemIDs    []string  `json:"itemIds"`
+	Reference  string    `json:"reference" graphql:"type=ID"`
+	Status     int       `json:"status" graphql:"type=OrderStatus"`
+	PlacedAt   time.Time `json:"placedAt" graphql:"type=DateTime"`
+	Invoice    Secret    `json:"invoice" graphql:"type=String"`
+	Identity   Member    `json:"identity"`
+}
+
+func TestTypeOverrides(t *testing.T) {
+	parser := typeparser.NewTypeParser(&typeparser.TypeParserOptions{
+		Scalars:  map[string]string{"time.Time": "DateTime"},
+		IDFields: []string{"ID", "*ID", "*IDs", "identity"},
+	}).AddStruct(Order{}, nil).AddEnum(typeparser.Enum{
+		Name:   "OrderStatus",
+		Values: []typeparser.EnumKeyPairOptions{{Key: "PLACED"}},
+	})
+
+	order := parser.Schema(nil).Type("Order").(*schema.ObjectType)
+
+	var types []string
+	for _, field := range order.Fields {
+		types = append(types, field.Name+": "+field.Type.String())
+	}
+
+	// Identity isn't a string or an integer so it isn't an ID. Neither
+	// time.Time nor Secret are walked as their type is given by the tag.
+	assert.Equal(t, []string{
+		"id: ID!",
+		"customerId: ID",
+		"itemIds: [ID!]!",
+		"reference: ID!",
+		"status: OrderStatus!",
+		"placedAt: DateTime!",
+		"invoice: String!",
+		"identity: Member!",
+	}, types)
+	if assert.Len(t, *parser.Structs, 2) {
+		assert.Equal(t, []string{"Member", "Order"}, []string{(*parser.Structs)[0].Name, (*parser.Structs)[1].Name})
+	}
+
+	// Without the IDFields option IDs stay strings and integers.
+	order = typeparser.NewTypeParser(&typeparser.TypeParserOptions{
+		Scalars: map[string]string{"time.Time": "DateTime"},
+	}).AddStruct(Order{}, nil).AddEnum(typeparser.Enum{Name: "OrderStatus"}).
+		Schema(nil).Type("Order").(*schema.ObjectType)
+	assert.Equal(t, "String!", order.Field("id").Type.String())
+	assert.Equal(t, "Int", order.Field("customerId").Type.String())
+	assert.Equal(t, "ID!", order.Field("reference").Type.String())
+}
+
+func TestTypeOverrides_UnknownType(t *testing.T) {
+	parser := typeparser.NewTypeParser(nil).AddStruct(Order{}, nil)
+
+	// Every unknown type is reported, not only the first one.
+	assert.EqualError(t, parser.Validate(parser.Schema(nil)), "invalid schema: 2 errors\n"+
+		"  Order.status: the type OrderStatus isn't defined (Go field typeparser_test.Order.Status)\n"+
+		"  Order.placedAt: the type DateTime isn't defined (Go field typeparser_test.Order.PlacedAt)")
+}
+
+// Shipment is sent to the warehouse.
+type Shipment struct {
+	ID        string            `json:"id"`
+	Reference *string           `json:"reference" graphql:"description=Our reference"`
+	Weight    float64           `json:"weight,omitempty"`
+	Status    int               `json:"status" graphql:"type=ShipmentStatus"`
+	Parcels   []*Parcel         `json:"parcels"`
+	Labels    map[string]string `json:"labels"`
+	ShippedAt time.Time         `json:"shippedAt"`
+	Internal  string            `json:"-"`
+}
+
+type Parcel struct {
+	Fragile bool `json:"fragile"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	parser := typeparser.NewTypeParser(&typeparser.TypeParserOptions{
+		Scalars:     map[string]string{"time.Time": "DateTime"},
+		FieldNaming: typeparser.FieldNamingSnakeCase,
+		IDFields:    []string{"id"},
+	}).AddStruct(Shipment{}, nil).AddEnum(typeparser.Enum{
+		Name:        "ShipmentStatus",
+		Description: ptr.Of("Where a shipment is."),
+		Values:      []typeparser.EnumKeyPairOptions{{Key: "PENDING", Value: 0}, {Key: "SHIPPED", Value: 1}, {Key: "LOST"}},
+	})
+
+	document, err := json.Marshal(parser.JSONSchema())
+	assert.NoError(t, err)
+
+	// Properties keep the json names rather than the snake_case ones and
+	// neither pointers nor fields tagged with omitempty are required.
+	assert.JSONEq(t, `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$defs": {
+    "DateTime": {},
+    "Parcel": {
+      "type": "object",
+      "properties": {"fragile": {"type": "boolean"}},
+      "required": ["fragile"]
+    },
+    "Shipment": {
+      "type": "object",
+      "properties": {
+        "id": {"type": ["string", "integer"]},
+        "reference": {"type": ["string", "null"], "description": "Our reference"},
+        "weight": {"type": "number"},
+        "status": {"$ref": "#/$defs/ShipmentStatus"},
+        "parcels": {"type": ["array", "null"], "items": {"anyOf": [{"$ref": "#/$defs/Parcel"}, {"type": "null"}]}},
+        "labels": {"type": ["object", "null"], "additionalProperties": {"type": "string"}},
+        "shippedAt": {"$ref": "#/$defs/DateTime"}
+      },
+      "required": ["id", "status", "parcels", "labels", "shippedAt"]
+    },
+    "ShipmentStatus": {"description": "Where a shipment is.", "enum": [0, 1, "LOST"]}
+  }
+}`, string(document))
+}
+
+type Receipt struct {
+	Number  string             `json:"number" graphql:"description=The number on the invoice"`
+	Lines   []*ReceiptLine     `json:"lines"`
+	Notes   *string            `json:"notes,omitempty"`
+	Totals  map[string]float64 `json:"totals"`
+	IssueAt time.Time          `json:"issued-at"`
+	Status  string             `json:"status" graphql:"type=InvoiceStatus"`
+}
+
+type ReceiptLine struct {
+	Quantity int `json:"quantity"`
+}
+
+func TestTypeScript(t *testing.T) {
+	parser := typeparser.NewTypeParser(&typeparser.TypeParserOptions{
+		Scalars: map[string]string{"time.Time": "DateTime"},
+	}).AddStruct(Receipt{}, nil).AddEnum(typeparser.Enum{
+		Name:        "InvoiceStatus",
+		Description: ptr.Of("Whether an invoice was paid."),
+		Values: []typeparser.EnumKeyPairOptions{
+			{Key: "OPEN", Value: "open"},
+			{Key: "PAID", Description: ptr.Of("Paid in full.\nNothing is owed.")},
+		},
+	})
+
+	assert.Equal(t, `export interface ReceiptLine {
+  quantity: number;
+}
+
+export interface Receipt {
+  /** The number on the invoice */
+  number: string;
+  lines: (ReceiptLine | null)[] | null;
+  notes?: string | null;
+  totals: Record<string, number> | null;
+  "issued-at": DateTime;
+  status: InvoiceStatus;
+}
+
+/** Whether an invoice was paid. */
+export type InvoiceStatus = "open" | "PAID";
+
+export type DateTime = unknown;
+`, parser.TypeScript(nil))
+
+	assert.Contains(t, parser.TypeScript(&typeparser.TypeScriptOptions{Enums: typeparser.TypeScriptEnumEnum}), `/** Whether an invoice was paid. */
+export enum InvoiceStatus {
+  OPEN = "open",
+  /**
+   * Paid in full.
+   * Nothing is owed.
+   */
+  PAID = "PAID",
+}
+`)
+}
+
+type Profile struct {
+	// The name shown to other users.
+	//
+	// Deprecated: Use displayName.
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	// Deprecated: Use displayName.
+	Nickname string `json:"nickname" graphql:"deprecated=\"Nicknames are gone\""`
+	Avatar   string `json:"avatar" graphql:"deprecated"`
+}
+
+func TestDeprecation(t *testing.T) {
+	parser := typeparser.NewTypeParser(&typeparser.TypeParserOptions{ParseDocComments: true}).
+		AddStruct(Profile{}, nil).
+		AddEnum(typeparser.Enum{
+			Name: "Visibility",
+			Values: []typeparser.EnumKeyPairOptions{
+				{Key: "PUBLIC"},
+				{Key: "FRIENDS", Deprecation: ptr.Of("Use PRIVATE")},
+				{Key: "HIDDEN", Deprecation: ptr.Of("")},
+			},
+		})
+
+	s := parser.Schema(nil)
+	profile := s.Type("Profile").(*schema.ObjectType)
+
+	var directives []string
+	for _, field := range profile.Fields {
+		directive := field.Name
+
+		if deprecated := schema.FindDirective(field.Directives, "deprecated"); deprecated != nil {
+			directive += " @deprecated"
+
+			for _, argument := range deprecated.Arguments {
+				directive += "(" + argument.Name + ": " + argument.Value + ")"
+			}
+		}
+
+		directives = append(directives, directive)
+	}
+
+	// The Deprecated: paragraph isn't part of the description and the tag wins over it.
+	assert.Equal(t, []string{
+		`name @deprecated(reason: "Use displayName.")`,
+		`displayName`,
+		`nickname @deprecated(reason: "Nicknames are gone")`,
+		`avatar @deprecated`,
+	}, directives)
+	assert.Equal(t, "The name shown to other users.", profile.Field("name").Description)
+	assert.Equal(t, "", profile.Field("nickname").Description)
+
+	values := s.Type("Visibility").(*schema.EnumType).Values
+	assert.False(t, values[0].IsDeprecated())
+	assert.Equal(t, []*schema.AppliedDirective{{Name: "deprecated", Arguments: []*schema.Argument{{Name: "reason", Value: `"Use PRIVATE"`}}}}, values[1].Directives)
+	assert.Equal(t, []*schema.AppliedDirective{{Name: "deprecated"}}, values[2].Directives)
+
+	assert.Contains(t, parser.TypeScript(nil), "  /**\n   * The name shown to other users.\n   * @deprecated Use displayName.\n   */\n  name: string;\n")
+	assert.Contains(t, parser.TypeScript(nil), "  /** @deprecated */\n  avatar: string;\n")
+}
+
+type Article struct {
+	_     struct{} `graphql:"decorators=[+cacheControl(maxAge: 60)]"`
+	Title string   `json:"title" graphql:"decorators=[+auth(roles: [ADMIN, EDITOR]), +tag(name: \"public\"), +tag(name: \"seo\")]"`
+	Body  string   `json:"body" graphql:"decorators=[+auth(roles: READER, level: 2)]"`
+}
+
+func TestDirectives(t *testing.T) {
+	roles := typeparser.Enum{
+		Name:   "Role",
+		Values: []typeparser.EnumKeyPairOptions{{Key: "ADMIN"}, {Key: "EDITOR"}, {Key: "READER"}},
+	}
+
+	directives := func(replace ...*schema.Directive) []*schema.Directive {
+		defaultLevel := "1"
+
+		definitions := []*schema.Directive{
+			{
+				Name:      "cacheControl",
+				Arguments: []*schema.InputValue{{Name: "maxAge", Type: schema.Named("Int")}},
+				Locations: []string{"OBJECT", "FIELD_DEFINITION"},
+			},
+			{
+				Name: "auth",
+				Arguments: []*schema.InputValue{
+					{Name: "roles", Type: schema.NonNullOf(schema.ListOf(schema.NonNullOf(schema.Named("Role"))))},
+					{Name: "level", Type: schema.NonNullOf(schema.Named("Int")), DefaultValue: &defaultLevel},
+				},
+				Locations: []string{"FIELD_DEFINITION"},
+			},
+			{
+				Name:       "tag",
+				Arguments:  []*schema.InputValue{{Name: "name", Type: schema.NonNullOf(schema.Named("String"))}},
+				Locations:  []string{"FIELD_DEFINITION"},
+				Repeatable: true,
+			},
+		}
+
+		for _, directive := range replace {
+			for i, definition := range definitions {
+				if definition.Name == directive.Name {
+					definitions[i] = directive
+				}
+			}
+		}
+
+		return definitions
+	}
+
+	tests := []struct {
+		name       string
+		directives []*schema.Directive
+		err        string
+	}{
+		{
+			name:       "Decorators match the directives",
+			directives: directives(),
+		},
+		{
+			name: "Without directives",
+			err: "invalid schema: 5 errors\n" +
+				"  Article: the directive @cacheControl isn't defined (Go type typeparser_test.Article)\n" +
+				"  Article.title: the directive @auth isn't defined (Go field typeparser_test.Article.Title)\n" +
+				"  Article.title: the directive @tag isn't defined (Go field typeparser_test.Article.Title)\n" +
+				"  Article.title: the directive @tag isn't defined (Go field typeparser_test.Article.Title)\n" +
+				"  Article.body: the directive @auth isn't defined (Go field typeparser_test.Article.Body)",
+		},
+		{
+			name:       "Unknown directive",
+			directives: directives()[1:],
+			err: "invalid schema: 1 error\n" +
+				"  Article: the directive @cacheControl isn't defined (Go type typeparser_test.Article)",
+		},
+		{
+			name: "Wrong location",
+			directives: directives(&schema.Directive{
+				Name:      "cacheControl",
+				Arguments: []*schema.InputValue{{Name: "maxAge", Type: schema.Named("Int")}},
+				Locations: []string{"FIELD_DEFINITION"},
+			}),
+			err: "invalid schema: 1 error\n" +
+				"  Article: the directive @cacheControl can't be applied on OBJECT (Go type typeparser_test.Article)",
+		},
+		{
+			name: "Repeated directive",
+			directives: directives(&schema.Directive{
+				Name:      "tag",
+				Arguments: []*schema.InputValue{{Name: "name", Type: schema.NonNullOf(schema.Named("String"))}},
+				Locations: []string{"FIELD_DEFINITION"},
+			}),
+			err: "invalid schema: 1 error\n" +
+				"  Article.title: the directive @tag isn't repeatable but is applied more than once (Go field typeparser_test.Article.Title)",
+		},
+		{
+			name: "Unknown argument",
+			directives: directives(&schema.Directive{
+				Name:      "cacheControl",
+				Locations: []string{"OBJECT"},
+			}),
+			err: "invalid schema: 1 error\n" +
+				"  Article: the directive @cacheControl has no argument maxAge (Go type typeparser_test.Article)",
+		},
+		{
+			name: "Missing argument",
+			directives: directives(&schema.Directive{
+				Name: "tag",
+				Arguments: []*schema.InputValue{
+					{Name: "name", Type: schema.NonNullOf(schema.Named("String"))},
+					{Name: "value", Type: schema.NonNullOf(schema.Named("String"))},
+				},
+				Locations:  []string{"FIELD_DEFINITION"},
+				Repeatable: true,
+			}),
+			err: "invalid schema: 2 errors\n" +
+				"  Article.title: the required argument value of @tag is missing (Go field typeparser_test.Article.Title)\n" +
+				"  Article.title: the required argument value of @tag is missing (Go field typeparser_test.Article.Title)",
+		},
+		{
+			name: "Argument of the wrong type",
+			directives: directives(&schema.Directive{
+				Name:      "cacheControl",
+				Arguments: []*schema.InputValue{{Name: "maxAge", Type: schema.Named("String")}},
+				Locations: []string{"OBJECT"},
+			}),
+			err: "invalid schema: 1 error\n" +
+				"  Article: the argument maxAge of @cacheControl is 60, which isn't of type String (Go type typeparser_test.Article)",
+		},
+		{
+			name: "Argument of an unknown type",
+			directives: directives(&schema.Directive{
+				Name:      "auth",
+				Arguments: []*schema.InputValue{{Name: "roles", Type: schema.ListOf(schema.Named("Status"))}},
+				Locations: []string{"FIELD_DEFINITION"},
+			}),
+			err: "invalid schema: 2 errors\n" +
+				"  @auth(roles:): the type Status isn't defined\n" +
+				"  Article.body: the directive @auth has no argument level (Go field typeparser_test.Article.Body)",
+		},
+		{
+			name:       "Built-in directive",
+			directives: append(directives(), &schema.Directive{Name: "deprecated", Locations: []string{"FIELD_DEFINITION"}}),
+			err:        "invalid schema: 1 error\n  @deprecated: the built-in directive @deprecated can't be defined",
+		},
+		{
+			name:       "Directive defined twice",
+			directives: append(directives(), directives()[0]),
+			err:        "invalid schema: 1 error\n  @cacheControl: the directive @cacheControl is defined more than once",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := typeparser.NewTypeParser(nil).AddStruct(Article{}, nil).AddEnum(roles)
+			options := &typeparser.SchemaOptions{Directives: test.directives}
+
+			s := parser.Schema(options)
+
+			if test.err != "" {
+				assert.EqualError(t, parser.Validate(s), test.err)
+
+				return
+			}
+
+			assert.NoError(t, parser.Validate(s))
+			assert.Equal(t, test.directives, s.Directives)
+			assert.Equal(t, "cacheControl", s.Type("Article").(*schema.ObjectType).Directives[0].Name)
+		})
+	}
+
+	// Values are checked against the type of the argument, a single value is also a list.
+	parser := typeparser.NewTypeParser(nil).AddStruct(Article{}, nil).AddEnum(typeparser.Enum{
+		Name:   "Role",
+		Values: []typeparser.EnumKeyPairOptions{{Key: "ADMIN"}, {Key: "EDITOR"}},
+	})
+
+	assert.EqualError(t, parser.Validate(parser.Schema(&typeparser.SchemaOptions{Directives: directives()})), "invalid schema: 1 error\n"+
+		"  Article.body: the argument roles of @auth is READER, which isn't of type [Role!]! (Go field typeparser_test.Article.Body)")
+}
+
+type Draft struct {
+	Title string `json:"title" graphql:"decorators=[+deprecated(reason: \"Use the headline\")]"`
+}
+
+func TestDirectives_BuiltIn(t *testing.T) {
+	// Built in directives are applied without being defined.
+	s := typeparser.NewTypeParser(nil).AddStruct(Draft{}, nil).Schema(nil)
+
+	assert.Equal(t, "deprecated", s.Type("Draft").(*schema.ObjectType).Field("title").Directives[0].Name)
+}
+
+type Coupon struct {
+	Code string `json:"code" graphql:"description=The code,deprecated="`
+}
+
+func TestInvalidTag(t *testing.T) {
+	// The schema is still built from what could be parsed, and Validate reports the error.
+	parser := typeparser.NewTypeParser(nil).AddStruct(Coupon{}, nil)
+	s := parser.Schema(nil)
+
+	assert.Equal(t, "The code", s.Type("Coupon").(*schema.ObjectType).Field("code").Description)
+	assert.EqualError(t, parser.Validate(s), "invalid schema: 1 error\n"+
+		"  Coupon.Code: invalid graphql tag at column 33: option 'deprecated' has no value (Go field typeparser_test.Coupon.Code)")
+}
+
+type Listing struct {
+	Title    string    `json:"title" graphql:"name=headline,nullable"`
+	Price    *float64  `json:"price" graphql:"nonNull"`
+	Secret   string    `json:"secret" graphql:"-"`
+	Seller   Member    `json:"seller" graphql:"exclude"`
+	Photos   []string  `json:"photos" graphql:"args=[first: Int = 10, size: PhotoSize!]"`
+	Updated  time.Time `json:"updated" graphql:"type=DateTime,args=[since: DateTime]"`
+	Category string    `json:"category" graphql:"nulable"`
+}
+
+func TestTagOptions(t *testing.T) {
+	parser := typeparser.NewTypeParser(&typeparser.TypeParserOptions{
+		Scalars: map[string]string{"time.Time": "DateTime"},
+	}).AddStruct(Listing{}, nil).AddEnum(typeparser.Enum{
+		Name:   "PhotoSize",
+		Values: []typeparser.EnumKeyPairOptions{{Key: "SMALL"}, {Key: "LARGE"}},
+	})
+
+	listing := parser.Schema(nil).Type("Listing").(*schema.ObjectType)
+
+	var fields []string
+	for _, field := range listing.Fields {
+		var arguments []string
+		for _, argument := range field.Arguments {
+			argument := argument.Name + ": " + argument.Type.String()
+			if field.Arguments[len(arguments)].DefaultValue != nil {
+				argument += " = " + *field.Arguments[len(arguments)].DefaultValue
+			}
+
+			arguments = append(arguments, argument)
+		}
+
+		fields = append(fields, fmt.Sprintf("%s(%s): %s", field.Name, strings.Join(arguments, ", "), field.Type))
+	}
+
+	// Excluded fields aren't walked, Member isn't added.
+	assert.Equal(t, []string{
+		"headline(): String",
+		"price(): Float!",
+		"photos(first: Int = 10, size: PhotoSize!): [String!]!",
+		"updated(since: DateTime): DateTime!",
+		"category(): String!",
+	}, fields)
+	assert.Len(t, *parser.Structs, 1)
+	assert.Equal(t, []string{
+		"unknown option 'nulable' in the graphql tag on 'Listing.Category' at column 1, did you mean 'nullable'?",
+	}, parser.Warnings())
+
+	// The renamed field keeps its JSON name.
+	assert.Equal(t, "title", *(*(*parser.Structs)[0].Fields)[0].JSONName)
+
+	parser = typeparser.NewTypeParser(&typeparser.TypeParserOptions{
+		Scalars: map[string]string{"time.Time": "DateTime"},
+	}).AddStruct(Listing{}, nil)
+	assert.EqualError(t, parser.Validate(parser.Schema(nil)), "invalid schema: 1 error\n"+
+		"  Listing.photos(size:): the type PhotoSize isn't defined (Go field typeparser_test.Listing.Photos)")
+}
+
+type Preferences struct {
+	Language string `json:"language"`
+	Theme    struct {
+		Color string `json:"-"`
+	} `json:"theme"`
+}
+
+func TestValidate(t *testing.T) {
+	valid := typeparser.NewTypeParser(nil).AddStruct(Project{}, nil)
+	assert.NoError(t, valid.Validate(valid.Schema(nil)))
+
+	// Every field of the anonymous struct is excluded.
+	parser := typeparser.NewTypeParser(nil).AddStruct(Preferences{}, nil)
+	err := parser.Validate(parser.Schema(nil))
+
+	var validationErrors schema.ValidationErrors
+	assert.ErrorAs(t, err, &validationErrors)
+	assert.Equal(t, schema.ValidationErrors{
+		{Coordinate: "PreferencesStruct1", Message: "the object type PreferencesStruct1 has no fields", Origin: "anonymous Go struct"},
+	}, validationErrors)
+}
+
+type Label struct {
+	Text string `json:"text"`
+}
+
+// Page is a page of items.
+type Page[T any] struct {
+	Items []T     `json:"items"`
+	Next  *string `json:"next"`
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+type Feed struct {
+	Labels Page[Label]                  `json:"labels"`
+	Pinned *Page[Label]                 `json:"pinned"`
+	Words  Page[string]                 `json:"words"`
+	Nested Page[Page[Label]]            `json:"nested"`
+	Counts Pair[string, map[string]int] `json:"counts"`
+	Genres Page[*library.Genre]         `json:"genres"`
+}
+
+func TestGenerics(t *testing.T) {
+	tests := []struct {
+		name     string
+		naming   typeparser.GenericNaming
+		expected []string
+	}{
+		{
+			name:     "Default naming",
+			expected: []string{"Label", "LabelPage", "StringPage", "LabelPagePage", "StringStringIntMapPair", "GenrePage", "Feed"},
+		},
+		{
+			name:     "Template",
+			naming:   `{{.Type}}_{{join .Args "_"}}`,
+			expected: []string{"Label", "Page_Label", "Page_String", "Page_Page_Label", "Pair_String_StringIntMap", "Page_Genre", "Feed"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := typeparser.NewTypeParser(&typeparser.TypeParserOptions{GenericNaming: test.naming}).AddStruct(Feed{}, nil)
+
+			var names []string
+			for _, s := range *parser.Structs {
+				names = append(names, s.Name)
+			}
+
+			// Page[Label] is added once, although two fields refer to it.
+			assert.Equal(t, test.expected, names)
+			assert.Equal(t, test.expected[1], (*(*parser.Structs)[len(names)-1].Fields)[0].Type)
+		})
+	}
+
+	// The doc comment of the generic type describes its instantiations.
+	documented := typeparser.NewTypeParser(&typeparser.TypeParserOptions{ParseDocComments: true}).AddStruct(Page[Label]{}, nil)
+	assert.Equal(t, ptr.Of("Page is a page of items."), (*documented.Structs)[1].Description)
+
+	assert.PanicsWithValue(t, "the generic naming template names Page[Label] 'Page<Label>', which is not a valid GraphQL name", func() {
+		typeparser.NewTypeParser(&typeparser.TypeParserOptions{GenericNaming: `{{.Type}}<{{join .Args ","}}>`}).AddStruct(Feed{}, nil)
+	})
+
+	assert.PanicsWithValue(t, fmt.Sprintf("The Go types '%[1]s.Page[%[1]s.Label]' and '%[1]s.Page[string]' are both named 'Page' "+
+		"in the schema, give one of them an alias or change the GenericNaming template", pkgPath), func() {
+		typeparser.NewTypeParser(&typeparser.TypeParserOptions{GenericNaming: "{{.Type}}"}).AddStruct(Feed{}, nil)
+	})
+}
+
+type Customer struct {
+	Invoices []Invoice        `json:"invoices"`
+	Billing  *billing.Invoice `json:"billing"`
+}
+
+func TestNameCollisions(t *testing.T) {
+	const billingInvoice = "github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser/testdata/billing.Invoice"
+
+	tests := []struct {
+		name     string
+		options  *typeparser.TypeParserOptions
+		expected []string
+		panics   string
+	}{
+		{
+			name: "Error",
+			panics: "The Go types '" + pkgPath + ".Invoice' and '" + billingInvoice + "' are both named 'Invoice' in the schema, " +
+				"give one of them an alias or prefix them with the name of their package",
+		},
+		{
+			name:     "Prefix",
+			options:  &typeparser.TypeParserOptions{NameCollisions: typeparser.NameCollisionPrefix},
+			expected: []string{"Typeparser_testInvoice", "BillingInvoice", "Customer"},
+		},
+		{
+			name:     "Alias",
+			options:  &typeparser.TypeParserOptions{TypeAliases: map[string]string{billingInvoice: "Bill"}},
+			expected: []string{"Invoice", "Bill", "Customer"},
+		},
+		{
+			name:    "Alias to the name of another type",
+			options: &typeparser.TypeParserOptions{TypeAliases: map[string]string{pkgPath + ".Invoice": "Customer"}},
+			panics: "The Go types '" + pkgPath + ".Customer' and '" + pkgPath + ".Invoice' are both named 'Customer' in the schema, " +
+				"give one of them an alias or prefix them with the name of their package",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if test.panics != "" {
+				assert.PanicsWithValue(t, test.panics, func() {
+					typeparser.NewTypeParser(test.options).AddStruct(Customer{}, nil)
+				})
+
+				return
+			}
+
+			parser := typeparser.NewTypeParser(test.options).AddStruct(Customer{}, nil)
+
+			var names []string
+			for _, s := range *parser.Structs {
+				names = append(names, s.Name)
+			}
+
+			assert.Equal(t, test.expected, names)
+			assert.Equal(t, test.expected[0], (*(*parser.Structs)[2].Fields)[0].Type)
+			assert.Equal(t, test.expected[1], (*(*parser.Structs)[2].Fields)[1].Type)
+
+			// The same Go type keeps its name when it is added again.
+			parser.AddStruct(billing.Invoice{}, nil)
+			assert.Len(t, *parser.Structs, 3)
+		})
+	}
+}
+
+type Ledger struct {
+	Invoices Page[Invoice]          `json:"invoices"`
+	Bills    Page[*billing.Invoice] `json:"bills"`
+}
+
+func TestNameCollisions_Generics(t *testing.T) {
+	const billingInvoice = "github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser/testdata/billing.Invoice"
+
+	prefix := &typeparser.TypeParserOptions{NameCollisions: typeparser.NameCollisionPrefix}
+
+	tests := []struct {
+		name     string
+		options  *typeparser.TypeParserOptions
+		types    []any
+		expected []string
+		panics   string
+	}{
+		{
+			name:  "Error",
+			types: []any{Ledger{}},
+			panics: "The Go types '" + pkgPath + ".Invoice' and '" + billingInvoice + "' are both named 'Invoice' in the schema, " +
+				"give one of them an alias or prefix them with the name of their package",
+		},
+		{
+			name:     "Prefix",
+			options:  prefix,
+			types:    []any{Ledger{}},
+			expected: []string{"Typeparser_testInvoicePage", "BillingInvoicePage"},
+		},
+		{
+			name:     "Prefix in separate calls",
+			options:  prefix,
+			types:    []any{Page[Invoice]{}, Page[*billing.Invoice]{}, Ledger{}},
+			expected: []string{"Typeparser_testInvoicePage", "BillingInvoicePage"},
+		},
+		{
+			name:     "Alias",
+			options:  &typeparser.TypeParserOptions{TypeAliases: map[string]string{billingInvoice: "Bill"}},
+			types:    []any{Ledger{}},
+			expected: []string{"InvoicePage", "BillPage"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := typeparser.NewTypeParser(test.options)
+			add := func() {
+				for _, value := range test.types {
+					parser.AddStruct(value, nil)
+				}
+			}
+
+			if test.panics != "" {
+				assert.PanicsWithValue(t, test.panics, add)
+
+				return
+			}
+
+			add()
+
+			ledger := (*parser.Structs)[len(*parser.Structs)-1]
+			assert.Equal(t, "Ledger", ledger.Name)
+			assert.Equal(t, test.expected, []string{(*ledger.Fields)[0].Type, (*ledger.Fields)[1].Type})
+			assert.Len(t, *parser.Structs, 5)
+		})
+	}
+}
+
+// Dispute renames Invoice when billing.Invoice is found, before the two statements collide.
+type Dispute struct {
+	Invoice   Invoice           `json:"invoice"`
+	Billing   billing.Invoice   `json:"billing"`
+	Statement Statement         `json:"statement"`
+	Billed    billing.Statement `json:"billed"`
+}
+
+func TestNameCollisions_Rollback(t *testing.T) {
+	const billingStatement = "github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser/testdata/billing.Statement"
+
+	parser := typeparser.NewTypeParser(nil).AddStruct(Invoice{}, nil)
+	before := parser.Snapshot()
+
+	// Customer is found before the collision of the Invoice types, it isn't left behind.
+	assert.Panics(t, func() { parser.AddStruct(Customer{}, nil) })
+	assert.Equal(t, before.Structs, parser.Structs)
+	assert.NoError(t, parser.Validate(parser.Schema(nil)))
+
+	prefixed := typeparser.NewTypeParser(&typeparser.TypeParserOptions{
+		NameCollisions: typeparser.NameCollisionPrefix,
+		TypeAliases: map[string]string{
+			pkgPath + ".Statement": "Statement",
+			billingStatement:       "Statement",
+		},
+	}).AddStruct(Invoice{}, nil)
+	before = prefixed.Snapshot()
+
+	// Invoice isn't left renamed Typeparser_testInvoice by the call that panicked.
+	assert.Panics(t, func() { prefixed.AddStruct(Dispute{}, nil) })
+	assert.Equal(t, before.Structs, prefixed.Structs)
+	assert.Equal(t, "Invoice", (*prefixed.Structs)[0].Name)
+}
+
+type Statement struct {
+	Balance struct {
+		Amount int `json:"amount"`
+	} `json:"balance"`
+	Lines map[string]int `json:"lines"`
+}
+
+func TestNameCollisions_Order(t *testing.T) {
+	options := &typeparser.TypeParserOptions{NameCollisions: typeparser.NameCollisionPrefix}
+
+	tests := []struct {
+		name  string
+		types []any
+	}{
+		{name: "Local type first", types: []any{Statement{}, billing.Statement{}, Invoice{}, billing.Invoice{}}},
+		{name: "Billing type first", types: []any{billing.Invoice{}, Invoice{}, billing.Statement{}, Statement{}}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := typeparser.NewTypeParser(options)
+			for _, value := range test.types {
+				parser.AddStruct(value, nil)
+			}
+
+			structs := map[string][]string{}
+			for _, s := range *parser.Structs {
+				for _, field := range *s.Fields {
+					structs[s.Name] = append(structs[s.Name], field.Type)
+				}
+			}
+
+			var maps []string
+			for _, m := range *parser.Maps {
+				maps = append(maps, m.Name)
+			}
+
+			assert.Equal(t, map[string][]string{
+				"Typeparser_testStatement":        {"Typeparser_testStatementStruct1", "Typeparser_testStatementLines"},
+				"Typeparser_testStatementStruct1": {"int"},
+				"BillingStatement":                {"BillingStatementStruct1", "BillingStatementLines"},
+				"BillingStatementStruct1":         {"int"},
+				"Typeparser_testInvoice":          {"string", "int", "bool"},
+				"BillingInvoice":                  {"string"},
+			}, structs)
+			assert.ElementsMatch(t, []string{"Typeparser_testStatementLines", "BillingStatementLines"}, maps)
+
+			// A type of the same name found later is prefixed too.
+			parser.AddStruct(shipping.Invoice{}, nil)
+			assert.Equal(t, "ShippingInvoice", (*parser.Structs)[len(*parser.Structs)-1].Name)
+		})
+	}
+}
+
+type Bill struct {
+	Labels billing.Labels `json:"labels"`
+}
+
+type Delivery struct {
+	Labels   shipping.Labels `json:"labels"`
+	Previous *Bill           `json:"previous"`
+}
+
+func TestNameCollisions_Maps(t *testing.T) {
+	const (
+		billingLabels  = "github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser/testdata/billing.Labels"
+		shippingLabels = "github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser/testdata/shipping.Labels"
+	)
+
+	prefix := &typeparser.TypeParserOptions{NameCollisions: typeparser.NameCollisionPrefix}
+
+	tests := []struct {
+		name    string
+		options *typeparser.TypeParserOptions
+		// enum is whether the Labels enum is added before or after the types.
+		enum     string
+		types    []any
+		expected map[string]string
+		panics   string
+	}{
+		{
+			name:  "Error",
+			types: []any{Delivery{}},
+			panics: "The Go types '" + shippingLabels + "' and '" + billingLabels + "' are both named 'Labels' in the schema, " +
+				"give one of them an alias or prefix them with the name of their package",
+		},
+		{
+			name:     "Prefix",
+			options:  prefix,
+			types:    []any{Delivery{}},
+			expected: map[string]string{"Bill": "BillingLabels", "Delivery": "ShippingLabels"},
+		},
+		{
+			name:     "Prefix in another order",
+			options:  prefix,
+			types:    []any{Bill{}, Delivery{}},
+			expected: map[string]string{"Bill": "BillingLabels", "Delivery": "ShippingLabels"},
+		},
+		{
+			name:  "Enum first",
+			enum:  "before",
+			types: []any{Bill{}},
+			panics: "The enum and the Go type '" + billingLabels + "' are both named 'Labels' in the schema, " +
+				"give the Go type an alias or prefix it with the name of its package",
+		},
+		{
+			name:     "Enum first with prefix",
+			options:  prefix,
+			enum:     "before",
+			types:    []any{Bill{}},
+			expected: map[string]string{"Bill": "BillingLabels"},
+		},
+		{
+			name:     "Enum last with prefix",
+			options:  prefix,
+			types:    []any{Bill{}},
+			enum:     "after",
+			expected: map[string]string{"Bill": "BillingLabels"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := typeparser.NewTypeParser(test.options)
+			add := func() {
+				if test.enum == "before" {
+					parser.AddEnum(typeparser.Enum{Name: "Labels"})
+				}
+
+				for _, value := range test.types {
+					parser.AddStruct(value, nil)
+				}
+
+				if test.enum == "after" {
+					parser.AddEnum(typeparser.Enum{Name: "Labels"})
+				}
+			}
+
+			if test.panics != "" {
+				assert.PanicsWithValue(t, test.panics, add)
+
+				return
+			}
+
+			add()
+
+			fields := map[string]string{}
+			for _, s := range *parser.Structs {
+				fields[s.Name] = (*s.Fields)[0].Type
+			}
+
+			var maps []string
+			for _, m := range *parser.Maps {
+				maps = append(maps, m.Name)
+			}
+
+			assert.Equal(t, test.expected, fields)
+			assert.Len(t, maps, len(test.expected))
+
+			for _, name := range test.expected {
+				assert.Contains(t, maps, name)
+			}
+		})
+	}
+
+	assert.PanicsWithValue(t, "The enum 'Labels' and the Go type '"+billingLabels+"' are both named 'Labels' in the schema, "+
+		"give one of them an alias or prefix it with the name of its package", func() {
+		typeparser.NewTypeParser(nil).AddStruct(Bill{}, nil).AddEnum(typeparser.Enum{Name: "Labels"})
+	})
+}
+
+// Categories is a tree of categories through a named map.
+type Categories map[string]*Category
+
+type Category struct {
+	Name     string     `json:"name"`
+	Children Categories `json:"children"`
+	Parent   *Category  `json:"parent"`
+}
+
+// Graph refers to itself through the value of the map.
+type Graph map[string][]Graph
+
+type Tree map[string]Tree
+
+type Forest struct {
+	Trees Tree  `json:"trees"`
+	Graph Graph `json:"graph"`
+}
+
+func TestRecursiveMaps(t *testing.T) {
+	parser := typeparser.NewTypeParser(nil).AddStruct(Forest{}, nil)
+
+	// The maps refer to themselves, so they are declared rather than written inline.
+	assert.Equal(t, `export interface Forest {
+  trees: Tree | null;
+  graph: Graph | null;
+}
+
+export interface Tree {
+  [key: string]: Tree | null;
+}
+
+export interface Graph {
+  [key: string]: Graph[] | null;
+}
+`, parser.TypeScript(nil))
+
+	jsonSchema := parser.JSONSchema()
+	assert.Equal(t, "#/$defs/Tree", jsonSchema.Defs["Forest"].Properties[0].Schema.AnyOf[0].Ref)
+	assert.Equal(t, "#/$defs/Tree", jsonSchema.Defs["Tree"].AdditionalProperties.AnyOf[0].Ref)
+	assert.Equal(t, "#/$defs/Graph", jsonSchema.Defs["Graph"].AdditionalProperties.Items.Ref)
+
+	_, err := json.Marshal(jsonSchema)
+	assert.NoError(t, err)
+}
+
+type Thread struct {
+	Title   string `json:"title"`
+	Replies []struct {
+		Text   string  `json:"text"`
+		Thread *Thread `json:"thread"`
+		Quotes map[string]struct {
+			Reply *Thread `json:"reply"`
+		} `json:"quotes"`
+	} `json:"replies"`
+	Pinned struct {
+		Text string `json:"text"`
+	} `json:"pinned"`
+}
+
+func TestRecursion(t *testing.T) {
+	tests := []struct {
+		name     string
+		add      func(parser *typeparser.TypeParser)
+		expected []string
+	}{
+		{
+			name: "Struct and named map",
+			add: func(parser *typeparser.TypeParser) {
+				parser.AddStruct(Category{}, nil)
+			},
+			expected: []string{
+				"Category{name: string, children: Categories, parent: Category}",
+				"Categories[string]Category",
+			},
+		},
+		{
+			name: "Named map added again",
+			add: func(parser *typeparser.TypeParser) {
+				parser.AddMap("Categories", Categories{}).AddStruct(&Category{}, nil).AddMap("Categories", Categories{})
+			},
+			expected: []string{
+				"Category{name: string, children: Categories, parent: Category}",
+				"Categories[string]Category",
+			},
+		},
+		{
+			name: "Unnamed map added again",
+			add: func(parser *typeparser.TypeParser) {
+				parser.AddMap("Tags", map[string]string{}).AddMap("Tags", map[string]string{})
+			},
+			expected: []string{"Tags[string]string"},
+		},
+		{
+			name: "Unnamed nested map added again",
+			add: func(parser *typeparser.TypeParser) {
+				parser.AddMap("Matrix", map[string]map[string]int{}).AddMap("Matrix", map[string]map[string]int{})
+			},
+			expected: []string{"MatrixMap1[string]int", "Matrix[string]MatrixMap1"},
+		},
+		{
+			name: "Map of itself",
+			add: func(parser *typeparser.TypeParser) {
+				parser.AddMap("Graph", Graph{})
+			},
+			expected: []string{"Graph[string]Graph"},
+		},
+		{
+			name: "Anonymous structs that refer to their parent",
+			add: func(parser *typeparser.TypeParser) {
+				parser.AddStruct(Thread{}, nil)
+			},
+			expected: []string{
+				"ThreadStruct1QuotesStruct1{reply: Thread}",
+				"ThreadStruct1{text: string, thread: Thread, quotes: ThreadStruct1Quotes}",
+				"ThreadPinned{text: string}",
+				"Thread{title: string, replies: ThreadStruct1, pinned: ThreadPinned}",
+				"ThreadStruct1Quotes[string]ThreadStruct1QuotesStruct1",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := typeparser.NewTypeParser(nil)
+			test.add(parser)
+
+			var types []string
+
+			if parser.Structs != nil {
+				for _, s := range *parser.Structs {
+					var fields []string
+					for _, field := range *s.Fields {
+						fields = append(fields, *field.Name+": "+field.Type)
+					}
+
+					types = append(types, s.Name+"{"+strings.Join(fields, ", ")+"}")
+				}
+			}
+
+			for _, m := range *parser.Maps {
+				types = append(types, m.Name+"["+m.Key.Type+"]"+m.Val.Type)
+			}
+
+			assert.Equal(t, test.expected, types)
+			assert.NoError(t, parser.Validate(parser.Schema(nil)))
+		})
+	}
+}
+
+type Attachment struct {
+	Meta struct {
+		Size int `json:"size"`
+	} `json:"meta"`
+}
+
+type Message struct {
+	Body struct {
+		Attachment Attachment `json:"attachment"`
+	} `json:"body"`
+}
+
+func TestAnonymousStructNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		types []any
+	}{
+		{name: "Named struct first", types: []any{Attachment{}, Message{}}},
+		{name: "Named struct in an anonymous struct", types: []any{Message{}, Attachment{}}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := typeparser.NewTypeParser(nil)
+			for _, value := range test.types {
+				parser.AddStruct(value, nil)
+			}
+
+			var names []string
+			for _, s := range *parser.Structs {
+				names = append(names, s.Name)
+			}
+
+			assert.ElementsMatch(t, []string{"AttachmentStruct1", "Attachment", "MessageStruct1", "Message"}, names)
+		})
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+	adds := []func(parser *typeparser.TypeParser){
+		func(parser *typeparser.TypeParser) { parser.AddStruct(User{}, nil) },
+		func(parser *typeparser.TypeParser) { parser.AddStruct(&UserDocument{}, nil) },
+		func(parser *typeparser.TypeParser) { parser.AddStruct(Category{}, nil) },
+		func(parser *typeparser.TypeParser) { parser.AddMap("Graph", Graph{}) },
+		func(parser *typeparser.TypeParser) { parser.AddStruct(Thread{}, nil) },
+	}
+	roles := typeparser.Enum{Name: "Roles", Values: []typeparser.EnumKeyPairOptions{{Key: "ADMIN", Value: RoleAdmin}}}
+	directives := []*schema.Directive{{Name: "unique", Locations: []string{"FIELD_DEFINITION"}}}
+
+	sequential := typeparser.NewTypeParser(nil).AddEnum(roles)
+	for _, add := range adds {
+		add(sequential)
+	}
+
+	parser := typeparser.NewTypeParser(nil)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		parser.AddEnum(roles)
+	}()
+
+	for i := 0; i < 10; i++ {
+		for _, add := range adds {
+			wg.Add(1)
+			go func(add func(parser *typeparser.TypeParser)) {
+				defer wg.Done()
+				add(parser)
+			}(add)
+		}
+
+		// The outputs are printed while types are being added.
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			s := parser.Schema(&typeparser.SchemaOptions{Directives: directives})
+			assert.NoError(t, parser.Validate(s))
+			parser.JSONSchema()
+			parser.TypeScript(nil)
+			parser.Warnings()
+		}()
+	}
+
+	wg.Wait()
+
+	// Each type is added once whatever the order of the calls, which only changes the order they are found in.
+	options := &typeparser.SchemaOptions{TypeOrder: schema.TypeOrderAlphabetical, Directives: directives}
+	assert.Equal(t, sequential.Schema(options), parser.Schema(options))
+}
+
+func TestSnapshot(t *testing.T) {
+	parser := typeparser.NewTypeParser(nil).AddStruct(Category{}, nil)
+	snapshot := parser.Snapshot()
+
+	parser.AddStruct(Thread{}, nil).AddMap("Graph", Graph{})
+	assert.Len(t, *parser.Structs, 5)
+	assert.Len(t, *parser.Maps, 3)
+
+	assert.Len(t, *snapshot.Structs, 1)
+	assert.Len(t, *snapshot.Maps, 1)
+	assert.Len(t, snapshot.Schema(nil).Types, 2)
+	assert.Same(t, snapshot, snapshot.Snapshot())
+
+	assert.PanicsWithValue(t, "Types can't be added to a snapshot of a TypeParser, add them to the parser it was taken from", func() {
+		snapshot.AddStruct(User{}, nil)
+	})
+}
@@ -0,0 +1,442 @@
+package typeparser
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	tagparser "github.com/warpspeed-cloud/graphql-schema-generator/internal/graphql-tag-parser"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+// scalarNames maps the Go kinds found by the parser to their GraphQL scalar.
+var scalarNames = map[string]string{
+	"bool":    "Boolean",
+	"string":  "String",
+	"int":     "Int",
+	"int8":    "Int",
+	"int16":   "Int",
+	"int32":   "Int",
+	"int64":   "Int",
+	"uint":    "Int",
+	"uint8":   "Int",
+	"uint16":  "Int",
+	"uint32":  "Int",
+	"uint64":  "Int",
+	"float32": "Float",
+	"float64": "Float",
+}
+
+type SchemaOptions struct {
+	// MapScalar is the scalar maps are output as. When it is empty maps are output as
+	// a list of key value pairs, i.e. [ProjectMeta!]! where ProjectMeta is an object
+	// type with a key and a value field, as GraphQL doesn't have maps.
+	MapScalar string
+
+	// Federation outputs the schema as an Apollo Federation v2 subgraph. The federation
+	// directives given in tags are applied, and the _service and _entities fields of
+	// the Query type are added along with the types they return.
+	Federation bool
+
+	// Directives are the definitions of the directives decorators apply, which are added to
+	// the schema. Validate reports a decorator that matches none of them, unless it applies
+	// a built in directive.
+	Directives []*schema.Directive
+
+	// TypeOrder is the order the types are output in, see schema.TypeOrder.
+	TypeOrder schema.TypeOrder
+}
+
+// schemaBuilder turns the types found by a parser into a schema.
+type schemaBuilder struct {
+	parser  *TypeParser
+	options *SchemaOptions
+
+	// Names of the maps found by the parser.
+	mapNames map[string]bool
+
+	// Custom scalars referenced by the fields, which must be declared.
+	scalars map[string]bool
+
+	// Connection and edge types of the fields tagged with connection.
+	connections     []schema.Type
+	connectionNames map[string]bool
+}
+
+// Schema returns the types found so far as a schema: the structs in the order they were
+// found, then the Relay Node interface and connections, the maps, the enums and the
+// custom scalars they refer to, unless the options give another TypeOrder.
+// The schema is built from a Snapshot, types can be added while it is built.
+func (t *TypeParser) Schema(options *SchemaOptions) *schema.Schema {
+	if options == nil {
+		options = &SchemaOptions{}
+	}
+
+	t = t.Snapshot()
+
+	b := &schemaBuilder{
+		parser:          t,
+		options:         options,
+		mapNames:        map[string]bool{},
+		scalars:         map[string]bool{},
+		connectionNames: map[string]bool{},
+	}
+
+	if t.Maps != nil {
+		for _, m := range *t.Maps {
+			b.mapNames[m.Name] = true
+		}
+	}
+
+	s := &schema.Schema{}
+
+	hasNodes := false
+
+	if t.Structs != nil {
+		for _, st := range *t.Structs {
+			s.Types = append(s.Types, b.objectType(st))
+			hasNodes = hasNodes || st.IsNode
+		}
+	}
+
+	if hasNodes {
+		addNodes(s)
+	}
+
+	if len(b.connections) > 0 {
+		s.Types = append(s.Types, b.connections...)
+		s.Types = append(s.Types, pageInfo())
+	}
+
+	// With a map scalar maps are output as that scalar rather
+	// than as a type holding key value pairs.
+	if t.Maps != nil && options.MapScalar == "" {
+		for _, m := range *t.Maps {
+			s.Types = append(s.Types, b.entriesType(m))
+		}
+	}
+
+	if t.Enums != nil {
+		for _, e := range *t.Enums {
+			s.Types = append(s.Types, enumType(e))
+		}
+	}
+
+	scalars := make([]string, 0, len(b.scalars))
+	for scalar := range b.scalars {
+		scalars = append(scalars, scalar)
+	}
+
+	sort.Strings(scalars)
+
+	for _, scalar := range scalars {
+		// A Go type can be mapped to an enum added by hand, which isn't a scalar.
+		if s.Type(scalar) != nil {
+			continue
+		}
+
+		s.Types = append(s.Types, &schema.ScalarType{Name: scalar})
+	}
+
+	b.addDirectives(s)
+
+	if options.Federation {
+		addFederation(s)
+	}
+
+	s.SortTypes(options.TypeOrder)
+
+	return s
+}
+
+// typeRef returns the type of a field and records the custom scalars it refers to.
+// Pointers are nullable, everything else can't be nil in Go so it is non-null.
+func (b *schemaBuilder) typeRef(field TypeDescriptor) *schema.TypeRef {
+	name := field.Type
+	if scalar, ok := scalarNames[name]; ok {
+		name = scalar
+	}
+
+	isMap := field.IsMap || b.mapNames[name]
+
+	if isMap && b.options.MapScalar != "" {
+		name = b.options.MapScalar
+		isMap = false
+	}
+
+	if b.isCustomScalar(name) {
+		b.scalars[name] = true
+	}
+
+	ref := schema.Named(name)
+
+	if field.IsSlice || isMap {
+		if !field.IsSliceOfPointers {
+			ref = schema.NonNullOf(ref)
+		}
+
+		ref = schema.ListOf(ref)
+	}
+
+	if !field.IsPointer {
+		ref = schema.NonNullOf(ref)
+	}
+
+	return ref
+}
+
+// isCustomScalar returns whether a type name is a scalar mapped with the
+// Scalars option of the parser, or the scalar maps are output as.
+func (b *schemaBuilder) isCustomScalar(name string) bool {
+	if schema.IsBuiltinScalar(name) {
+		return false
+	}
+
+	if name == b.options.MapScalar {
+		return true
+	}
+
+	if b.parser.options != nil {
+		for _, scalar := range b.parser.options.Scalars {
+			if scalar == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (b *schemaBuilder) objectType(s Struct) *schema.ObjectType {
+	object := &schema.ObjectType{
+		Name:        s.Name,
+		Description: stringValue(s.Description),
+	}
+
+	if s.IsNode {
+		object.Interfaces = []string{nodeInterfaceName}
+	}
+
+	object.Directives = appliedDirectives(s.ParsedTag)
+
+	if b.options.Federation {
+		object.Directives = append(object.Directives, typeFederationDirectives(s)...)
+	}
+
+	if s.Fields != nil {
+		for _, field := range *s.Fields {
+			if !field.IncludeInOutput {
+				continue
+			}
+
+			output := &schema.Field{
+				Name:        *field.Name,
+				Description: stringValue(field.GetDescription()),
+				Type:        b.typeRef(field),
+				Directives:  appliedDirectives(field.ParsedTag),
+			}
+
+			if reason := field.GetDeprecation(); reason != nil && schema.FindDirective(output.Directives, "deprecated") == nil {
+				output.Directives = append(output.Directives, deprecatedDirective(*reason))
+			}
+
+			if b.options.Federation {
+				output.Directives = append(output.Directives, fieldFederationDirectives(field.ParsedTag)...)
+			}
+
+			if tag := field.ParsedTag; tag != nil {
+				b.applyTag(s.Name, field, tag, output)
+			}
+
+			object.Fields = append(object.Fields, output)
+		}
+	}
+
+	return object
+}
+
+// applyTag applies the options of the tag of a field that change its output: the type,
+// connection, args and nullability options.
+func (b *schemaBuilder) applyTag(parent string, field TypeDescriptor, tag *tagparser.Tag, output *schema.Field) {
+	if tag.Connection {
+		b.connection(parent, field, output)
+	}
+
+	for _, argument := range tag.Args {
+		input := &schema.InputValue{
+			Name:         argument.Name,
+			Type:         parseTypeRef(argument.Type),
+			DefaultValue: argument.DefaultValue,
+		}
+
+		name := input.Type.NamedType()
+		if b.isCustomScalar(name) {
+			b.scalars[name] = true
+		}
+
+		output.Arguments = append(output.Arguments, input)
+	}
+
+	switch tag.Nullability {
+	case tagparser.NullabilityNullable:
+		if output.Type.NonNull {
+			output.Type = output.Type.OfType
+		}
+	case tagparser.NullabilityNonNull:
+		output.Type = schema.NonNullOf(output.Type)
+	}
+}
+
+// parseTypeRef parses a type written in SDL, i.e. [String!]!. The tag parser
+// has already checked that it is well formed.
+func parseTypeRef(s string) *schema.TypeRef {
+	switch {
+	case strings.HasSuffix(s, "!"):
+		return schema.NonNullOf(parseTypeRef(strings.TrimSuffix(s, "!")))
+	case strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]"):
+		return schema.ListOf(parseTypeRef(s[1 : len(s)-1]))
+	default:
+		return schema.Named(s)
+	}
+}
+
+// entriesType returns the type of the entries of a map, with a key and a value field.
+// The value of a map can be a map itself, which is a list of entries too.
+func (b *schemaBuilder) entriesType(m Map) *schema.ObjectType {
+	// A nil key can't be looked up so keys are never nullable.
+	key := m.Key
+	key.IsPointer = false
+
+	return &schema.ObjectType{
+		Name: m.Name,
+		Fields: []*schema.Field{
+			{Name: "key", Type: b.typeRef(key)},
+			{Name: "value", Type: b.typeRef(m.Val)},
+		},
+	}
+}
+
+func enumType(e Enum) *schema.EnumType {
+	enum := &schema.EnumType{
+		Name:        e.Name,
+		Description: stringValue(e.Description),
+	}
+
+	for _, value := range e.Values {
+		enumValue := &schema.EnumValue{
+			Name:        value.Key,
+			Description: stringValue(value.Description),
+		}
+
+		if value.Deprecation != nil {
+			enumValue.Directives = append(enumValue.Directives, deprecatedDirective(*value.Deprecation))
+		}
+
+		enum.Values = append(enum.Values, enumValue)
+	}
+
+	return enum
+}
+
+// deprecatedDirective returns the @deprecated directive, with the reason when there is one.
+func deprecatedDirective(reason string) *schema.AppliedDirective {
+	directive := &schema.AppliedDirective{Name: "deprecated"}
+
+	if reason != "" {
+		directive.Arguments = []*schema.Argument{{Name: "reason", Value: strconv.Quote(reason)}}
+	}
+
+	return directive
+}
+
+// appliedDirectives returns the decorators of a tag as directives.
+func appliedDirectives(tag *tagparser.Tag) []*schema.AppliedDirective {
+	var directives []*schema.AppliedDirective
+
+	if tag == nil {
+		return nil
+	}
+
+	for _, decorator := range tag.Directives {
+		directives = append(directives, &schema.AppliedDirective{
+			Name:      decorator.Name,
+			Arguments: directiveArguments(decorator.Arguments),
+		})
+	}
+
+	return directives
+}
+
+// directiveArguments returns the raw arguments of a decorator, i.e. role: "admin", limit: 10,
+// as arguments. An argument without a name is kept as its value only.
+func directiveArguments(raw string) []*schema.Argument {
+	var arguments []*schema.Argument
+
+	for _, argument := range splitArguments(raw) {
+		name, value, found := strings.Cut(argument, ":")
+		if !found || strings.ContainsAny(name, "\"[{") {
+			arguments = append(arguments, &schema.Argument{Value: argument})
+
+			continue
+		}
+
+		arguments = append(arguments, &schema.Argument{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+	}
+
+	return arguments
+}
+
+// splitArguments splits arguments, or the items of a list, at the commas that aren't
+// inside a string, a list or an object. Empty arguments are dropped.
+func splitArguments(raw string) []string {
+	var (
+		arguments []string
+		current   strings.Builder
+		depth     int
+		inQuotes  bool
+		escaped   bool
+	)
+
+	add := func() {
+		if argument := strings.TrimSpace(current.String()); argument != "" {
+			arguments = append(arguments, argument)
+		}
+
+		current.Reset()
+	}
+
+	for _, char := range raw {
+		switch {
+		case escaped:
+			escaped = false
+		case char == '\\' && inQuotes:
+			escaped = true
+		case char == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+		case char == '[' || char == '{' || char == '(':
+			depth++
+		case char == ']' || char == '}' || char == ')':
+			depth--
+		case char == ',' && depth <= 0:
+			add()
+
+			continue
+		}
+
+		current.WriteRune(char)
+	}
+
+	add()
+
+	return arguments
+}
+
+// stringValue returns the value of an optional string, or an empty string.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
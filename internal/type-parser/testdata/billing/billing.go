@@ -0,0 +1,18 @@
+// Package billing declares an Invoice like the tests do, to check that Go
+// types of the same name in different packages are told apart.
+package billing
+
+type Invoice struct {
+	Number string `json:"number"`
+}
+
+// Statement is named after its own anonymous struct and map, which are renamed with it.
+type Statement struct {
+	Balance struct {
+		Amount int `json:"amount"`
+	} `json:"balance"`
+	Lines map[string]int `json:"lines"`
+}
+
+// Labels is a named map, which is told apart from the shipping one.
+type Labels map[string]int
@@ -0,0 +1,13 @@
+// Package documents instantiates a generic type with a type from a package
+// whose path has dots.
+package documents
+
+import "github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser/testdata/yaml.v3"
+
+type Box[T any] struct {
+	Item T `json:"item"`
+}
+
+type Document struct {
+	Root Box[yaml.Node] `json:"root"`
+}
@@ -0,0 +1,41 @@
+// Package library is walked both by reflection and from source to check that
+// both give the same result.
+package library
+
+type Genre uint8
+
+type Book struct {
+	ID       string              `json:"id" graphql:"description=The ID of the book"`
+	Title    string              `json:"title"`
+	Price    *float64            `json:"price,omitempty"`
+	Genres   []Genre             `json:"genres"`
+	Authors  []*Author           `json:"authors"`
+	Sequel   *Book               `json:"sequel"`
+	Editions map[string][]string `json:"editions"`
+	Shelf    struct {
+		Row    int `json:"row"`
+		Column int `json:"column"`
+	} `json:"shelf"`
+	internalCode string
+}
+
+type Author struct {
+	Name      string       `json:"name"`
+	Books     []Book       `json:"books"`
+	Email     *string      `json:"-"`
+	Favorites Shelf[*Book] `json:"favorites"`
+}
+
+// Shelf is generic to check that instantiations are named the same from source.
+type Shelf[T any] struct {
+	Items []T `json:"items"`
+}
+
+// InternalCode exists to keep the unexported field in use.
+func (b Book) InternalCode() string {
+	return b.internalCode
+}
+
+// IsNode makes books Relay nodes, it has a pointer receiver to check that
+// the method set of the pointer is used.
+func (b *Book) IsNode() {}
@@ -0,0 +1,9 @@
+// Package shipping declares a third Invoice, to check that it is prefixed when
+// the other ones already were.
+package shipping
+
+type Invoice struct {
+	Carrier string `json:"carrier"`
+}
+
+type Labels map[string]string
@@ -0,0 +1,7 @@
+// Package yaml has a package path with dots, like gopkg.in/yaml.v3, to check
+// that the names of the type arguments of generic types drop the whole path.
+package yaml
+
+type Node struct {
+	Value string `json:"value"`
+}
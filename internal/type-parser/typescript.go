@@ -0,0 +1,324 @@
+package typeparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TypeScriptEnumStyle is how enums are output in TypeScript.
+type TypeScriptEnumStyle string
+
+const (
+	// TypeScriptEnumUnion outputs an enum as a union of its values, i.e. type Role = "USER" | "ADMIN".
+	TypeScriptEnumUnion TypeScriptEnumStyle = "union"
+	// TypeScriptEnumEnum outputs an enum as a TypeScript enum, i.e. enum Role { USER = "USER" }.
+	TypeScriptEnumEnum TypeScriptEnumStyle = "enum"
+)
+
+// TypeScriptEnumStyles are the ways enums can be output in TypeScript.
+var TypeScriptEnumStyles = []TypeScriptEnumStyle{TypeScriptEnumUnion, TypeScriptEnumEnum}
+
+type TypeScriptOptions struct {
+	// Enums is how enums are output, TypeScriptEnumUnion by default.
+	Enums TypeScriptEnumStyle
+}
+
+// typeScriptTypes maps the Go kinds found by the parser, and the built-in
+// scalars they can be given as, to their TypeScript type.
+var typeScriptTypes = map[string]string{
+	"bool":    "boolean",
+	"string":  "string",
+	"int":     "number",
+	"int8":    "number",
+	"int16":   "number",
+	"int32":   "number",
+	"int64":   "number",
+	"uint":    "number",
+	"uint8":   "number",
+	"uint16":  "number",
+	"uint32":  "number",
+	"uint64":  "number",
+	"float32": "number",
+	"float64": "number",
+	"Boolean": "boolean",
+	"String":  "string",
+	"Int":     "number",
+	"Float":   "number",
+	"ID":      "string | number",
+}
+
+const tsIndent = "  "
+
+// identifierPattern matches the property names that don't have to be quoted.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// TypeScript returns the types found so far as TypeScript declarations: an interface
+// for every struct and every map whose values are the map itself, i.e. type Tree
+// map[string]Tree, then the enums and a type for every custom scalar. Other maps are
+// written inline as records. Like JSONSchema
+// it describes the JSON the types are encoded as, properties are named after the json
+// tags, fields tagged with omitempty are optional and pointers, slices and maps can be null.
+func (t *TypeParser) TypeScript(options *TypeScriptOptions) string {
+	if options == nil {
+		options = &TypeScriptOptions{}
+	}
+
+	t = t.Snapshot()
+
+	b := &typeScriptBuilder{
+		maps:    map[string]Map{},
+		known:   map[string]bool{},
+		scalars: map[string]bool{},
+	}
+
+	if t.Maps != nil {
+		for _, m := range *t.Maps {
+			b.maps[m.Name] = m
+		}
+	}
+
+	b.recursive = recursiveMaps(b.maps)
+
+	if t.Structs != nil {
+		for _, s := range *t.Structs {
+			b.known[s.Name] = true
+		}
+	}
+
+	if t.Enums != nil {
+		for _, e := range *t.Enums {
+			b.known[e.Name] = true
+		}
+	}
+
+	var declarations []string
+
+	if t.Structs != nil {
+		for _, s := range *t.Structs {
+			declarations = append(declarations, b.structInterface(s))
+		}
+	}
+
+	if t.Maps != nil {
+		for _, m := range *t.Maps {
+			if b.recursive[m.Name] {
+				declarations = append(declarations, b.mapInterface(m))
+			}
+		}
+	}
+
+	if t.Enums != nil {
+		for _, e := range *t.Enums {
+			declarations = append(declarations, typeScriptEnum(e, options.Enums))
+		}
+	}
+
+	scalars := make([]string, 0, len(b.scalars))
+	for scalar := range b.scalars {
+		scalars = append(scalars, scalar)
+	}
+
+	sort.Strings(scalars)
+
+	for _, scalar := range scalars {
+		declarations = append(declarations, fmt.Sprintf("export type %s = unknown;\n", scalar))
+	}
+
+	return strings.Join(declarations, "\n")
+}
+
+// typeScriptBuilder turns the types found by a parser into TypeScript declarations.
+type typeScriptBuilder struct {
+	// Maps found by the parser, by name.
+	maps map[string]Map
+	// Names of the maps that are declared as they refer to themselves.
+	recursive map[string]bool
+	// Names of the structs and enums found by the parser.
+	known map[string]bool
+
+	// Custom scalars referenced by the fields, which must be declared.
+	scalars map[string]bool
+}
+
+func (b *typeScriptBuilder) structInterface(s Struct) string {
+	out := &strings.Builder{}
+	writeTSDoc(out, stringValue(s.Description), "")
+	out.WriteString("export interface " + s.Name + " {\n")
+
+	if s.Fields != nil {
+		for _, field := range *s.Fields {
+			if !field.IncludeInOutput {
+				continue
+			}
+
+			name := field.jsonName()
+			if !identifierPattern.MatchString(name) {
+				name = strconv.Quote(name)
+			}
+
+			if field.OmitEmpty {
+				name += "?"
+			}
+
+			writeTSDoc(out, typeScriptDoc(field.GetDescription(), field.GetDeprecation()), tsIndent)
+			out.WriteString(fmt.Sprintf("%s%s: %s;\n", tsIndent, name, b.fieldType(field)))
+		}
+	}
+
+	out.WriteString("}\n")
+
+	return out.String()
+}
+
+// mapInterface returns the declaration of a map that refers to itself, with an index signature.
+func (b *typeScriptBuilder) mapInterface(m Map) string {
+	return fmt.Sprintf("export interface %s {\n%s[key: %s]: %s;\n}\n", m.Name, tsIndent, typeScriptKeyType(m.Key), b.fieldType(m.Val))
+}
+
+// fieldType returns the type of a field or of the values of a map.
+func (b *typeScriptBuilder) fieldType(field TypeDescriptor) string {
+	m, isMap := b.maps[field.Type]
+
+	var value string
+
+	switch {
+	case isMap && b.recursive[field.Type]:
+		value = field.Type
+	case isMap:
+		value = fmt.Sprintf("Record<%s, %s>", typeScriptKeyType(m.Key), b.fieldType(m.Val))
+	case field.IsMap:
+		// A map that wasn't found by its name can still be described as a record.
+		value = "Record<string, unknown>"
+		isMap = true
+	default:
+		value = b.namedType(field.Type)
+	}
+
+	if field.IsSlice {
+		if field.IsSliceOfPointers {
+			value += " | null"
+		}
+
+		if strings.Contains(value, " ") {
+			value = "(" + value + ")"
+		}
+
+		value += "[]"
+	}
+
+	// Slices and maps are nil when they are empty, which is encoded as null.
+	if field.IsPointer || field.IsSlice || isMap {
+		value += " | null"
+	}
+
+	return value
+}
+
+// typeScriptKeyType returns the type of the keys of a map, which are strings or numbers in JSON.
+func typeScriptKeyType(key TypeDescriptor) string {
+	if typeScriptTypes[key.Type] == "number" {
+		return "number"
+	}
+
+	return "string"
+}
+
+func (b *typeScriptBuilder) namedType(name string) string {
+	if tsType, ok := typeScriptTypes[name]; ok {
+		return tsType
+	}
+
+	if !b.known[name] {
+		b.scalars[name] = true
+	}
+
+	return name
+}
+
+func typeScriptEnum(e Enum, style TypeScriptEnumStyle) string {
+	out := &strings.Builder{}
+	writeTSDoc(out, stringValue(e.Description), "")
+
+	if style == TypeScriptEnumEnum {
+		out.WriteString("export enum " + e.Name + " {\n")
+
+		for _, value := range e.Values {
+			writeTSDoc(out, typeScriptDoc(value.Description, value.Deprecation), tsIndent)
+			out.WriteString(fmt.Sprintf("%s%s = %s,\n", tsIndent, value.Key, typeScriptValue(value)))
+		}
+
+		out.WriteString("}\n")
+
+		return out.String()
+	}
+
+	values := make([]string, 0, len(e.Values))
+	for _, value := range e.Values {
+		values = append(values, typeScriptValue(value))
+	}
+
+	if len(values) == 0 {
+		values = append(values, "never")
+	}
+
+	out.WriteString(fmt.Sprintf("export type %s = %s;\n", e.Name, strings.Join(values, " | ")))
+
+	return out.String()
+}
+
+// typeScriptValue returns the value of an enum as it is encoded in JSON, its key when it has no value.
+func typeScriptValue(value EnumKeyPairOptions) string {
+	if value.Value == nil {
+		return strconv.Quote(value.Key)
+	}
+
+	encoded, err := json.Marshal(value.Value)
+	if err != nil {
+		return strconv.Quote(value.Key)
+	}
+
+	return string(encoded)
+}
+
+// typeScriptDoc returns the JSDoc comment of a description, with a @deprecated tag when
+// there is a deprecation reason.
+func typeScriptDoc(description *string, deprecation *string) string {
+	doc := stringValue(description)
+
+	if deprecation != nil {
+		if doc != "" {
+			doc += "\n"
+		}
+
+		doc += strings.TrimSpace("@deprecated " + *deprecation)
+	}
+
+	return doc
+}
+
+// writeTSDoc writes a description as a JSDoc comment.
+func writeTSDoc(out *strings.Builder, description string, prefix string) {
+	if description == "" {
+		return
+	}
+
+	description = strings.ReplaceAll(description, "*/", "*\\/")
+
+	if !strings.Contains(description, "\n") {
+		out.WriteString(prefix + "/** " + description + " */\n")
+
+		return
+	}
+
+	out.WriteString(prefix + "/**\n")
+
+	for _, line := range strings.Split(description, "\n") {
+		out.WriteString(strings.TrimRight(prefix+" * "+line, " ") + "\n")
+	}
+
+	out.WriteString(prefix + " */\n")
+}
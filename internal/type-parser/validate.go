@@ -0,0 +1,91 @@
+package typeparser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+// Validate checks a schema built from the types of the parser against the type system
+// rules of the GraphQL specification, see schema.Validate. The errors on the types
+// found from structs point back to the Go type or field the definition comes from.
+// The syntax errors in the graphql tags of the types come first.
+func (t *TypeParser) Validate(s *schema.Schema) error {
+	err := schema.Validate(s)
+
+	var validationErrors schema.ValidationErrors
+	if err != nil && !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	snapshot := t.Snapshot()
+
+	if len(snapshot.tagErrors) > 0 {
+		tagErrors := make(schema.ValidationErrors, 0, len(snapshot.tagErrors)+len(validationErrors))
+
+		for _, tagError := range snapshot.tagErrors {
+			tagErrors = append(tagErrors, &schema.ValidationError{
+				Coordinate: tagError.Field,
+				Message:    fmt.Sprintf("invalid graphql tag at column %d: %s", tagError.Column, tagError.Message),
+			})
+		}
+
+		validationErrors = append(tagErrors, validationErrors...)
+	}
+
+	if len(validationErrors) == 0 {
+		return nil
+	}
+
+	for _, validationError := range validationErrors {
+		validationError.Origin = snapshot.origin(validationError.Coordinate)
+	}
+
+	return validationErrors
+}
+
+// origin returns the Go type or field a schema coordinate comes from, i.e. the Go field
+// models.User.Name for User.name, or an empty string when it doesn't come from a struct.
+func (t *TypeParser) origin(coordinate string) string {
+	if t.Structs == nil {
+		return ""
+	}
+
+	typeName, rest, _ := strings.Cut(coordinate, ".")
+	fieldName, _, _ := strings.Cut(rest, "(")
+
+	for _, s := range *t.Structs {
+		if s.Name != typeName {
+			continue
+		}
+
+		if fieldName == "" {
+			if s.GoName == "" {
+				return "anonymous Go struct"
+			}
+
+			return "Go type " + s.GoName
+		}
+
+		if s.Fields == nil {
+			return ""
+		}
+
+		for _, field := range *s.Fields {
+			// Tag errors are on the Go field, as the tag can rename it.
+			if !field.IncludeInOutput || (*field.Name != fieldName && field.GoName != fieldName) {
+				continue
+			}
+
+			if s.GoName == "" {
+				return fmt.Sprintf("Go field %s of an anonymous struct", field.GoName)
+			}
+
+			return fmt.Sprintf("Go field %s.%s", s.GoName, field.GoName)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// DefaultDeprecationReason is the reason of a @deprecated directive without one.
+const DefaultDeprecationReason = "No longer supported"
+
+// namePattern is the grammar of names in GraphQL.
+var namePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// builtinScalars are the scalars every schema has without declaring them.
+var builtinScalars = map[string]bool{
+	"Int":     true,
+	"Float":   true,
+	"String":  true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+// builtinDirectives are the definitions Validate checks the built-in directives against.
+var builtinDirectives = BuiltinDirectives()
+
+// IsValidName returns whether a name follows the grammar of names in GraphQL, i.e. User or _id.
+func IsValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// IsBuiltinScalar returns whether a type is one of the scalars every schema has without
+// declaring them: Int, Float, String, Boolean and ID.
+func IsBuiltinScalar(name string) bool {
+	return builtinScalars[name]
+}
+
+// BuiltinDirectives returns the definitions of the directives every schema has without
+// declaring them, @include, @skip, @deprecated and @specifiedBy, in the order introspection
+// lists them. The definitions are new on every call so they can be changed.
+func BuiltinDirectives() []*Directive {
+	reason := strconv.Quote(DefaultDeprecationReason)
+
+	return []*Directive{
+		{
+			Name:        "include",
+			Description: "Directs the executor to include this field or fragment only when the `if` argument is true.",
+			Arguments:   []*InputValue{{Name: "if", Description: "Included when true.", Type: NonNullOf(Named("Boolean"))}},
+			Locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		},
+		{
+			Name:        "skip",
+			Description: "Directs the executor to skip this field or fragment when the `if` argument is true.",
+			Arguments:   []*InputValue{{Name: "if", Description: "Skipped when true.", Type: NonNullOf(Named("Boolean"))}},
+			Locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		},
+		{
+			Name:        "deprecated",
+			Description: "Marks an element of a GraphQL schema as no longer supported.",
+			Arguments: []*InputValue{{
+				Name:         "reason",
+				Description:  "Explains why this element was deprecated, usually also including a suggestion for how to access supported similar data.",
+				Type:         Named("String"),
+				DefaultValue: &reason,
+			}},
+			Locations: []string{"FIELD_DEFINITION", "ARGUMENT_DEFINITION", "INPUT_FIELD_DEFINITION", "ENUM_VALUE"},
+		},
+		{
+			Name:        "specifiedBy",
+			Description: "Exposes a URL that specifies the behavior of this scalar.",
+			Arguments:   []*InputValue{{Name: "url", Description: "The URL that specifies the behavior of this scalar.", Type: NonNullOf(Named("String"))}},
+			Locations:   []string{"SCALAR"},
+		},
+	}
+}
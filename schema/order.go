@@ -0,0 +1,123 @@
+package schema
+
+import "sort"
+
+// TypeOrder is the order the types of a schema are output in.
+type TypeOrder string
+
+const (
+	// TypeOrderDiscovery keeps the types in the order they were found. A struct comes after the
+	// structs its fields refer to, and the order depends on the order structs are added in.
+	TypeOrderDiscovery TypeOrder = "discovery"
+	// TypeOrderAlphabetical sorts the types by name.
+	TypeOrderAlphabetical TypeOrder = "alphabetical"
+	// TypeOrderDependency puts the types no other type refers to first, in alphabetical order,
+	// each followed by the types it refers to in the order of its fields, i.e. Query, then User
+	// and the types of the fields of User. Types only found in a cycle follow, by name.
+	TypeOrderDependency TypeOrder = "dependency"
+)
+
+// TypeOrders are the orders types can be output in, an empty order is TypeOrderDiscovery.
+var TypeOrders = []TypeOrder{"", TypeOrderDiscovery, TypeOrderAlphabetical, TypeOrderDependency}
+
+// SortTypes puts the types of the schema in an order. Alphabetical and dependency orders
+// only depend on the types, not on the order they were added in, so that the schema
+// diffs cleanly when the Go types change.
+func (s *Schema) SortTypes(order TypeOrder) {
+	switch order {
+	case TypeOrderAlphabetical:
+		sort.SliceStable(s.Types, func(i, j int) bool {
+			return s.Types[i].TypeName() < s.Types[j].TypeName()
+		})
+	case TypeOrderDependency:
+		s.sortByDependency()
+	}
+}
+
+func (s *Schema) sortByDependency() {
+	types := make([]Type, len(s.Types))
+	copy(types, s.Types)
+
+	sort.SliceStable(types, func(i, j int) bool {
+		return types[i].TypeName() < types[j].TypeName()
+	})
+
+	byName := map[string]Type{}
+	referenced := map[string]bool{}
+
+	for _, t := range types {
+		byName[t.TypeName()] = t
+
+		for _, name := range referencedTypes(t) {
+			if name != t.TypeName() {
+				referenced[name] = true
+			}
+		}
+	}
+
+	sorted := make([]Type, 0, len(types))
+	visited := map[string]bool{}
+
+	var visit func(t Type)
+	visit = func(t Type) {
+		if visited[t.TypeName()] {
+			return
+		}
+
+		visited[t.TypeName()] = true
+		sorted = append(sorted, t)
+
+		for _, name := range referencedTypes(t) {
+			if referencedType, ok := byName[name]; ok {
+				visit(referencedType)
+			}
+		}
+	}
+
+	for _, t := range types {
+		if !referenced[t.TypeName()] {
+			visit(t)
+		}
+	}
+
+	for _, t := range types {
+		visit(t)
+	}
+
+	s.Types = sorted
+}
+
+// referencedTypes returns the names of the types a type refers to, in the order they
+// appear in it: the interfaces it implements, the types of its fields and their
+// arguments, the members of a union and the types of the fields of an input object.
+func referencedTypes(t Type) []string {
+	var names []string
+
+	values := func(values []*InputValue) {
+		for _, value := range values {
+			names = append(names, value.Type.NamedType())
+		}
+	}
+
+	fields := func(fields []*Field) {
+		for _, field := range fields {
+			names = append(names, field.Type.NamedType())
+			values(field.Arguments)
+		}
+	}
+
+	switch t := t.(type) {
+	case *ObjectType:
+		names = append(names, t.Interfaces...)
+		fields(t.Fields)
+	case *InterfaceType:
+		names = append(names, t.Interfaces...)
+		fields(t.Fields)
+	case *UnionType:
+		names = append(names, t.Members...)
+	case *InputObjectType:
+		values(t.Fields)
+	}
+
+	return names
+}
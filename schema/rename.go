@@ -0,0 +1,79 @@
+package schema
+
+// RenameType renames a type along with every reference to it: the type of fields,
+// arguments and input fields, the interfaces types implement and the members of unions.
+func (s *Schema) RenameType(from string, to string) {
+	rename := func(names []string) {
+		for i, name := range names {
+			if name == from {
+				names[i] = to
+			}
+		}
+	}
+
+	renameValues := func(values []*InputValue) {
+		for _, value := range values {
+			value.Type.rename(from, to)
+		}
+	}
+
+	renameFields := func(fields []*Field) {
+		for _, field := range fields {
+			field.Type.rename(from, to)
+			renameValues(field.Arguments)
+		}
+	}
+
+	for _, t := range s.Types {
+		switch t := t.(type) {
+		case *ObjectType:
+			if t.Name == from {
+				t.Name = to
+			}
+
+			rename(t.Interfaces)
+			renameFields(t.Fields)
+		case *InterfaceType:
+			if t.Name == from {
+				t.Name = to
+			}
+
+			rename(t.Interfaces)
+			renameFields(t.Fields)
+		case *UnionType:
+			if t.Name == from {
+				t.Name = to
+			}
+
+			rename(t.Members)
+		case *EnumType:
+			if t.Name == from {
+				t.Name = to
+			}
+		case *ScalarType:
+			if t.Name == from {
+				t.Name = to
+			}
+		case *InputObjectType:
+			if t.Name == from {
+				t.Name = to
+			}
+
+			renameValues(t.Fields)
+		}
+	}
+
+	for _, directive := range s.Directives {
+		renameValues(directive.Arguments)
+	}
+}
+
+func (r *TypeRef) rename(from string, to string) {
+	for r.OfType != nil {
+		r = r.OfType
+	}
+
+	if r.Name == from {
+		r.Name = to
+	}
+}
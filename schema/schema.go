@@ -0,0 +1,247 @@
+// Package schema is the model of a GraphQL schema. The type parser builds it from
+// Go types and every output backend, i.e. the SDL printer, reads it, so plugins
+// and custom backends can inspect and change a schema before it is output.
+package schema
+
+// Kind is the kind of a named type, as in the __TypeKind enum of introspection.
+type Kind string
+
+const (
+	KindObject      Kind = "OBJECT"
+	KindInterface   Kind = "INTERFACE"
+	KindUnion       Kind = "UNION"
+	KindEnum        Kind = "ENUM"
+	KindScalar      Kind = "SCALAR"
+	KindInputObject Kind = "INPUT_OBJECT"
+)
+
+// Schema is a GraphQL schema. Types and directives are kept in the order they are output.
+type Schema struct {
+	Types      []Type
+	Directives []*Directive
+	// ExtensionDirectives are the directives applied to the schema with a schema
+	// extension, i.e. extend schema @link(url: "https://specs.apollo.dev/federation/v2.3").
+	ExtensionDirectives []*AppliedDirective
+}
+
+// Type returns the named type with the given name, or nil if there is none.
+func (s *Schema) Type(name string) Type {
+	for _, t := range s.Types {
+		if t.TypeName() == name {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// Directive returns the directive definition with the given name, without
+// the @, or nil if there is none.
+func (s *Schema) Directive(name string) *Directive {
+	for _, directive := range s.Directives {
+		if directive.Name == name {
+			return directive
+		}
+	}
+
+	return nil
+}
+
+// Type is a named type of a schema, one of *ObjectType, *InterfaceType, *UnionType,
+// *EnumType, *ScalarType or *InputObjectType.
+type Type interface {
+	TypeName() string
+	TypeKind() Kind
+	TypeDescription() string
+}
+
+// ObjectType is a type with fields, i.e. type User { id: ID! }.
+type ObjectType struct {
+	Name        string
+	Description string
+	// Interfaces are the names of the interfaces the type implements.
+	Interfaces []string
+	Fields     []*Field
+	Directives []*AppliedDirective
+}
+
+func (t *ObjectType) TypeName() string        { return t.Name }
+func (t *ObjectType) TypeKind() Kind          { return KindObject }
+func (t *ObjectType) TypeDescription() string { return t.Description }
+
+// Field returns the field with the given name, or nil if there is none.
+func (t *ObjectType) Field(name string) *Field {
+	return findField(t.Fields, name)
+}
+
+// InterfaceType is an abstract type with fields that object types implement.
+type InterfaceType struct {
+	Name        string
+	Description string
+	// Interfaces are the names of the interfaces the interface implements.
+	Interfaces []string
+	Fields     []*Field
+	Directives []*AppliedDirective
+}
+
+func (t *InterfaceType) TypeName() string        { return t.Name }
+func (t *InterfaceType) TypeKind() Kind          { return KindInterface }
+func (t *InterfaceType) TypeDescription() string { return t.Description }
+
+// Field returns the field with the given name, or nil if there is none.
+func (t *InterfaceType) Field(name string) *Field {
+	return findField(t.Fields, name)
+}
+
+// UnionType is an abstract type that is one of its member object types.
+type UnionType struct {
+	Name        string
+	Description string
+	// Members are the names of the object types of the union.
+	Members    []string
+	Directives []*AppliedDirective
+}
+
+func (t *UnionType) TypeName() string        { return t.Name }
+func (t *UnionType) TypeKind() Kind          { return KindUnion }
+func (t *UnionType) TypeDescription() string { return t.Description }
+
+// EnumType is a type limited to a set of values.
+type EnumType struct {
+	Name        string
+	Description string
+	Values      []*EnumValue
+	Directives  []*AppliedDirective
+}
+
+func (t *EnumType) TypeName() string        { return t.Name }
+func (t *EnumType) TypeKind() Kind          { return KindEnum }
+func (t *EnumType) TypeDescription() string { return t.Description }
+
+// ScalarType is a leaf type. The built-in scalars, Int, Float, String, Boolean
+// and ID, aren't part of a schema, only custom scalars such as JSON are.
+type ScalarType struct {
+	Name        string
+	Description string
+	Directives  []*AppliedDirective
+}
+
+func (t *ScalarType) TypeName() string        { return t.Name }
+func (t *ScalarType) TypeKind() Kind          { return KindScalar }
+func (t *ScalarType) TypeDescription() string { return t.Description }
+
+// InputObjectType is a type with fields that is given as an argument.
+type InputObjectType struct {
+	Name        string
+	Description string
+	Fields      []*InputValue
+	Directives  []*AppliedDirective
+}
+
+func (t *InputObjectType) TypeName() string        { return t.Name }
+func (t *InputObjectType) TypeKind() Kind          { return KindInputObject }
+func (t *InputObjectType) TypeDescription() string { return t.Description }
+
+// Field returns the input field with the given name, or nil if there is none.
+func (t *InputObjectType) Field(name string) *InputValue {
+	return FindInputValue(t.Fields, name)
+}
+
+// Field is a field of an object or an interface type.
+type Field struct {
+	Name        string
+	Description string
+	Arguments   []*InputValue
+	Type        *TypeRef
+	Directives  []*AppliedDirective
+}
+
+// IsDeprecated returns whether the @deprecated directive is applied to the field.
+func (f *Field) IsDeprecated() bool {
+	return FindDirective(f.Directives, "deprecated") != nil
+}
+
+// InputValue is an argument of a field or a directive, or a field of an input object type.
+type InputValue struct {
+	Name        string
+	Description string
+	Type        *TypeRef
+	// DefaultValue is the default value as it is written in SDL, i.e. "admin" or 10.
+	DefaultValue *string
+	Directives   []*AppliedDirective
+}
+
+// IsDeprecated returns whether the @deprecated directive is applied to the value.
+func (v *InputValue) IsDeprecated() bool {
+	return FindDirective(v.Directives, "deprecated") != nil
+}
+
+// EnumValue is a value of an enum type.
+type EnumValue struct {
+	Name        string
+	Description string
+	Directives  []*AppliedDirective
+}
+
+// IsDeprecated returns whether the @deprecated directive is applied to the value.
+func (v *EnumValue) IsDeprecated() bool {
+	return FindDirective(v.Directives, "deprecated") != nil
+}
+
+// Directive is the definition of a directive, i.e.
+// directive @auth(role: String!) repeatable on FIELD_DEFINITION.
+type Directive struct {
+	// Name is the name of the directive without the @.
+	Name        string
+	Description string
+	Arguments   []*InputValue
+	// Locations are where the directive can be applied, i.e. FIELD_DEFINITION.
+	Locations  []string
+	Repeatable bool
+}
+
+// AppliedDirective is a directive applied to a definition, i.e. @auth(role: "admin").
+type AppliedDirective struct {
+	// Name is the name of the directive without the @.
+	Name      string
+	Arguments []*Argument
+}
+
+// Argument is an argument given to an applied directive.
+type Argument struct {
+	Name string
+	// Value is the value as it is written in SDL, i.e. "admin" or 10.
+	Value string
+}
+
+// FindDirective returns the first directive with the given name, or nil if there is none.
+func FindDirective(directives []*AppliedDirective, name string) *AppliedDirective {
+	for _, directive := range directives {
+		if directive.Name == name {
+			return directive
+		}
+	}
+
+	return nil
+}
+
+// FindInputValue returns the argument or input field with the given name, or nil if there is none.
+func FindInputValue(values []*InputValue, name string) *InputValue {
+	for _, value := range values {
+		if value.Name == name {
+			return value
+		}
+	}
+
+	return nil
+}
+
+func findField(fields []*Field, name string) *Field {
+	for _, field := range fields {
+		if field.Name == name {
+			return field
+		}
+	}
+
+	return nil
+}
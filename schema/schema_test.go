@@ -0,0 +1,433 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+func TestTypeRef(t *testing.T) {
+	tests := []struct {
+		name      string
+		ref       *schema.TypeRef
+		expected  string
+		namedType string
+	}{
+		{
+			name:      "Named",
+			ref:       schema.Named("User"),
+			expected:  "User",
+			namedType: "User",
+		},
+		{
+			name:      "Non-null list of non-null",
+			ref:       schema.NonNullOf(schema.ListOf(schema.NonNullOf(schema.Named("User")))),
+			expected:  "[User!]!",
+			namedType: "User",
+		},
+		{
+			name:      "Non-null twice",
+			ref:       schema.NonNullOf(schema.NonNullOf(schema.Named("ID"))),
+			expected:  "ID!",
+			namedType: "ID",
+		},
+		{
+			name:      "Nested lists",
+			ref:       schema.ListOf(schema.ListOf(schema.Named("Int"))),
+			expected:  "[[Int]]",
+			namedType: "Int",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, test.ref.String())
+			assert.Equal(t, test.namedType, test.ref.NamedType())
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	user := &schema.ObjectType{
+		Name: "User",
+		Fields: []*schema.Field{
+			{Name: "id", Type: schema.NonNullOf(schema.Named("ID"))},
+			{Name: "username", Type: schema.Named("String"), Directives: []*schema.AppliedDirective{{Name: "deprecated"}}},
+		},
+	}
+	role := &schema.EnumType{Name: "Role", Values: []*schema.EnumValue{{Name: "ADMIN"}}}
+	auth := &schema.Directive{Name: "auth", Locations: []string{"FIELD_DEFINITION"}}
+
+	s := &schema.Schema{
+		Types:      []schema.Type{user, role},
+		Directives: []*schema.Directive{auth},
+	}
+
+	assert.Equal(t, user, s.Type("User"))
+	assert.Equal(t, schema.KindEnum, s.Type("Role").TypeKind())
+	assert.Nil(t, s.Type("Project"))
+	assert.Equal(t, auth, s.Directive("auth"))
+	assert.Nil(t, s.Directive("deprecated"))
+
+	assert.False(t, user.Field("id").IsDeprecated())
+	assert.True(t, user.Field("username").IsDeprecated())
+	assert.Nil(t, user.Field("email"))
+	assert.False(t, role.Values[0].IsDeprecated())
+}
+
+func TestRenameType(t *testing.T) {
+	s := &schema.Schema{
+		Directives: []*schema.Directive{
+			{Name: "owner", Arguments: []*schema.InputValue{{Name: "of", Type: schema.Named("User")}}},
+		},
+		Types: []schema.Type{
+			&schema.ObjectType{
+				Name:       "User",
+				Interfaces: []string{"Node"},
+				Fields: []*schema.Field{
+					{Name: "friends", Type: schema.NonNullOf(schema.ListOf(schema.NonNullOf(schema.Named("User"))))},
+					{Name: "node", Arguments: []*schema.InputValue{{Name: "id", Type: schema.Named("User")}}, Type: schema.Named("Node")},
+				},
+			},
+			&schema.UnionType{Name: "Result", Members: []string{"User", "Team"}},
+			&schema.InputObjectType{Name: "Filter", Fields: []*schema.InputValue{{Name: "user", Type: schema.Named("User")}}},
+		},
+	}
+
+	s.RenameType("User", "Account")
+
+	account := s.Type("Account").(*schema.ObjectType)
+	assert.Nil(t, s.Type("User"))
+	assert.Equal(t, "[Account!]!", account.Fields[0].Type.String())
+	assert.Equal(t, "Account", account.Fields[1].Arguments[0].Type.String())
+	assert.Equal(t, "Node", account.Fields[1].Type.String())
+	assert.Equal(t, []string{"Account", "Team"}, s.Type("Result").(*schema.UnionType).Members)
+	assert.Equal(t, "Account", s.Type("Filter").(*schema.InputObjectType).Fields[0].Type.String())
+	assert.Equal(t, "Account", s.Directive("owner").Arguments[0].Type.String())
+
+	s.RenameType("Node", "Entity")
+	assert.Equal(t, []string{"Entity"}, account.Interfaces)
+}
+
+func TestSortTypes(t *testing.T) {
+	discovered := []string{"Role", "Team", "User", "Filter", "Query", "Result", "DateTime", "Cycle", "Loop"}
+
+	tests := []struct {
+		order    schema.TypeOrder
+		expected []string
+	}{
+		{order: "", expected: discovered},
+		{order: schema.TypeOrderDiscovery, expected: discovered},
+		{
+			order:    schema.TypeOrderAlphabetical,
+			expected: []string{"Cycle", "DateTime", "Filter", "Loop", "Query", "Result", "Role", "Team", "User"},
+		},
+		{
+			order:    schema.TypeOrderDependency,
+			expected: []string{"Query", "User", "Team", "Role", "Filter", "DateTime", "Result", "Cycle", "Loop"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(string(test.order), func(t *testing.T) {
+			t.Parallel()
+
+			s := &schema.Schema{Types: []schema.Type{
+				&schema.EnumType{Name: "Role", Values: []*schema.EnumValue{{Name: "ADMIN"}}},
+				&schema.ObjectType{Name: "Team", Fields: []*schema.Field{
+					{Name: "members", Type: schema.ListOf(schema.Named("User"))},
+				}},
+				&schema.ObjectType{Name: "User", Fields: []*schema.Field{
+					{Name: "friends", Type: schema.ListOf(schema.Named("User"))},
+					{Name: "team", Type: schema.Named("Team")},
+					{Name: "role", Type: schema.Named("Role")},
+				}},
+				&schema.InputObjectType{Name: "Filter", Fields: []*schema.InputValue{
+					{Name: "createdAfter", Type: schema.Named("DateTime")},
+				}},
+				&schema.ObjectType{Name: "Query", Fields: []*schema.Field{
+					{Name: "user", Type: schema.Named("User"), Arguments: []*schema.InputValue{{Name: "filter", Type: schema.Named("Filter")}}},
+					{Name: "search", Type: schema.Named("Result")},
+				}},
+				&schema.UnionType{Name: "Result", Members: []string{"User", "Team"}},
+				&schema.ScalarType{Name: "DateTime"},
+				&schema.ObjectType{Name: "Cycle", Fields: []*schema.Field{{Name: "loop", Type: schema.Named("Loop")}}},
+				&schema.ObjectType{Name: "Loop", Fields: []*schema.Field{{Name: "cycle", Type: schema.Named("Cycle")}}},
+			}}
+
+			s.SortTypes(test.order)
+
+			names := make([]string, 0, len(s.Types))
+			for _, schemaType := range s.Types {
+				names = append(names, schemaType.TypeName())
+			}
+
+			assert.Equal(t, test.expected, names)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	id := func() *schema.TypeRef { return schema.NonNullOf(schema.Named("ID")) }
+	node := &schema.InterfaceType{Name: "Node", Fields: []*schema.Field{{Name: "id", Type: id()}}}
+
+	tests := []struct {
+		name     string
+		types    []schema.Type
+		expected []string
+	}{
+		{
+			name: "Valid schema",
+			types: []schema.Type{
+				node,
+				&schema.ObjectType{
+					Name:       "User",
+					Interfaces: []string{"Node"},
+					Fields: []*schema.Field{
+						{Name: "id", Type: id()},
+						{Name: "role", Type: schema.Named("Role"), Arguments: []*schema.InputValue{{Name: "in", Type: schema.Named("Filter")}}},
+					},
+				},
+				&schema.EnumType{Name: "Role", Values: []*schema.EnumValue{{Name: "ADMIN"}}},
+				&schema.InputObjectType{Name: "Filter", Fields: []*schema.InputValue{{Name: "parent", Type: schema.Named("Filter")}}},
+				&schema.UnionType{Name: "Result", Members: []string{"User"}},
+			},
+		},
+		{
+			name: "Invalid names",
+			types: []schema.Type{
+				&schema.ObjectType{Name: "Page[User]", Fields: []*schema.Field{{Name: "__id", Type: id()}}},
+				&schema.EnumType{Name: "Role", Values: []*schema.EnumValue{{Name: "super-admin"}, {Name: "null"}}},
+			},
+			expected: []string{
+				"Page[User]: 'Page[User]' is not a valid GraphQL name",
+				"Page[User].__id: '__id' starts with __, which is reserved for introspection",
+				"Role.super-admin: 'super-admin' is not a valid GraphQL name",
+				"Role.null: an enum value can't be null",
+			},
+		},
+		{
+			name: "Empty and duplicate types",
+			types: []schema.Type{
+				&schema.ObjectType{Name: "User"},
+				&schema.ObjectType{Name: "User", Fields: []*schema.Field{{Name: "id", Type: id()}, {Name: "id", Type: id()}}},
+				&schema.EnumType{Name: "Role"},
+				&schema.UnionType{Name: "Result"},
+				&schema.ScalarType{Name: "String"},
+			},
+			expected: []string{
+				"User: the object type User has no fields",
+				"User: the type User is defined more than once",
+				"User.id: the field id is defined more than once",
+				"Role: the enum Role has no values",
+				"Result: the union Result has no members",
+				"String: the built-in scalar String can't be defined",
+			},
+		},
+		{
+			name: "Type references",
+			types: []schema.Type{
+				&schema.ObjectType{
+					Name: "User",
+					Fields: []*schema.Field{
+						{Name: "team", Type: schema.Named("UserStruct1")},
+						{Name: "filter", Type: schema.Named("Filter"), Arguments: []*schema.InputValue{{Name: "of", Type: schema.Named("User")}}},
+					},
+				},
+				&schema.InputObjectType{Name: "Filter", Fields: []*schema.InputValue{{Name: "self", Type: schema.NonNullOf(schema.Named("Filter"))}}},
+				&schema.UnionType{Name: "Result", Members: []string{"Filter", "Team"}},
+			},
+			expected: []string{
+				"User.team: the type UserStruct1 isn't defined",
+				"User.filter: the type Filter of a field must be an output type, Filter is an input object",
+				"User.filter(of:): the type User of an argument must be an input type, User is an output type",
+				"Filter: the input object type Filter can never be given, it refers to itself through non-null fields: Filter.self",
+				"Result: the members of a union must be object types, Filter isn't one",
+				"Result: the member Team isn't defined",
+			},
+		},
+		{
+			name: "Interfaces",
+			types: []schema.Type{
+				node,
+				&schema.InterfaceType{Name: "Entity", Interfaces: []string{"Node"}, Fields: []*schema.Field{{Name: "id", Type: id()}}},
+				&schema.ObjectType{
+					Name:       "User",
+					Interfaces: []string{"Entity", "Role"},
+					Fields:     []*schema.Field{{Name: "id", Type: schema.Named("ID")}},
+				},
+				&schema.ObjectType{Name: "Team", Interfaces: []string{"Node"}, Fields: []*schema.Field{{Name: "name", Type: schema.Named("String")}}},
+				&schema.EnumType{Name: "Role", Values: []*schema.EnumValue{{Name: "ADMIN"}}},
+			},
+			expected: []string{
+				"User: User must also implement Node, which Entity implements",
+				"User.id: the type ID isn't compatible with the type ID! of Entity.id",
+				"User: User can only implement interfaces, Role isn't one",
+				"Team: the field id of the interface Node is missing",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := schema.Validate(&schema.Schema{Types: test.types})
+
+			if test.expected == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var validationErrors schema.ValidationErrors
+			assert.ErrorAs(t, err, &validationErrors)
+
+			messages := make([]string, 0, len(validationErrors))
+			for _, validationError := range validationErrors {
+				messages = append(messages, validationError.Error())
+			}
+
+			assert.Equal(t, test.expected, messages)
+		})
+	}
+}
+
+func TestValidate_AppliedDirectives(t *testing.T) {
+	auth := &schema.Directive{
+		Name: "auth",
+		Arguments: []*schema.InputValue{
+			{Name: "role", Type: schema.NonNullOf(schema.Named("Role"))},
+			{Name: "level", Type: schema.Named("Int")},
+		},
+		Locations: []string{"FIELD_DEFINITION", "OBJECT"},
+	}
+	role := &schema.EnumType{Name: "Role", Values: []*schema.EnumValue{{Name: "ADMIN"}}}
+	link := &schema.AppliedDirective{
+		Name: "link",
+		Arguments: []*schema.Argument{
+			{Name: "url", Value: `"https://specs.apollo.dev/federation/v2.3"`},
+			{Name: "import", Value: `["@key", "@shareable"]`},
+		},
+	}
+
+	user := func(directives ...*schema.AppliedDirective) *schema.ObjectType {
+		return &schema.ObjectType{
+			Name:   "User",
+			Fields: []*schema.Field{{Name: "name", Type: schema.Named("String"), Directives: directives}},
+		}
+	}
+
+	applied := func(name string, arguments ...string) *schema.AppliedDirective {
+		directive := &schema.AppliedDirective{Name: name}
+
+		for i := 0; i < len(arguments); i += 2 {
+			directive.Arguments = append(directive.Arguments, &schema.Argument{Name: arguments[i], Value: arguments[i+1]})
+		}
+
+		return directive
+	}
+
+	tests := []struct {
+		name     string
+		user     *schema.ObjectType
+		expected []string
+	}{
+		{
+			name: "Defined, built in and linked directives",
+			user: user(applied("auth", "role", "ADMIN", "level", "2"), applied("deprecated", "reason", `"Use id"`), applied("shareable")),
+		},
+		{
+			name:     "Undefined directive",
+			user:     user(applied("unique")),
+			expected: []string{"User.name: the directive @unique isn't defined"},
+		},
+		{
+			name: "Wrong location",
+			user: user(applied("specifiedBy", "url", `"https://example.com"`)),
+			expected: []string{
+				"User.name: the directive @specifiedBy can't be applied on FIELD_DEFINITION",
+			},
+		},
+		{
+			name:     "Directive applied twice",
+			user:     user(applied("auth", "role", "ADMIN"), applied("auth", "role", "ADMIN")),
+			expected: []string{"User.name: the directive @auth isn't repeatable but is applied more than once"},
+		},
+		{
+			name:     "Unknown argument",
+			user:     user(applied("auth", "role", "ADMIN", "scope", `"read"`)),
+			expected: []string{"User.name: the directive @auth has no argument scope"},
+		},
+		{
+			name: "Argument of the wrong type",
+			user: user(applied("auth", "role", "OWNER", "level", `"2"`)),
+			expected: []string{
+				"User.name: the argument role of @auth is OWNER, which isn't of type Role!",
+				`User.name: the argument level of @auth is "2", which isn't of type Int`,
+			},
+		},
+		{
+			name:     "Missing argument",
+			user:     user(applied("auth", "level", "2")),
+			expected: []string{"User.name: the required argument role of @auth is missing"},
+		},
+		{
+			name: "Argument without a name",
+			user: user(applied("auth", "role", "ADMIN", "", "2")),
+			expected: []string{
+				"User.name: the argument 2 of @auth has no name",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := schema.Validate(&schema.Schema{
+				Types:               []schema.Type{test.user, role},
+				Directives:          []*schema.Directive{auth},
+				ExtensionDirectives: []*schema.AppliedDirective{link},
+			})
+
+			if test.expected == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var validationErrors schema.ValidationErrors
+			assert.ErrorAs(t, err, &validationErrors)
+
+			messages := make([]string, 0, len(validationErrors))
+			for _, validationError := range validationErrors {
+				messages = append(messages, validationError.Error())
+			}
+
+			assert.Equal(t, test.expected, messages)
+		})
+	}
+
+	// The built-in directives can't be defined again.
+	err := schema.Validate(&schema.Schema{Directives: []*schema.Directive{{Name: "skip", Locations: []string{"FIELD"}}}})
+	assert.EqualError(t, err, "invalid schema: 1 error\n  @skip: the built-in directive @skip can't be defined")
+}
+
+func TestValidationErrors(t *testing.T) {
+	err := schema.ValidationErrors{
+		{Coordinate: "User", Message: "the object type User has no fields", Origin: "Go type models.User"},
+		{Coordinate: "@auth(role:)", Message: "the type User of an argument must be an input type, User is an output type"},
+	}
+
+	assert.EqualError(t, err, "invalid schema: 2 errors\n"+
+		"  User: the object type User has no fields (Go type models.User)\n"+
+		"  @auth(role:): the type User of an argument must be an input type, User is an output type")
+}
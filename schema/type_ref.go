@@ -0,0 +1,53 @@
+package schema
+
+// TypeRef is the type of a field or an input value: a named type, or a list
+// or a non-null type wrapping another TypeRef, i.e. [User!]! is
+// NonNullOf(ListOf(NonNullOf(Named("User")))).
+type TypeRef struct {
+	// Name is the name of the type for a reference to a named type.
+	Name string
+	// OfType is the wrapped type of a list or a non-null type.
+	OfType  *TypeRef
+	List    bool
+	NonNull bool
+}
+
+// Named returns a reference to a named type.
+func Named(name string) *TypeRef {
+	return &TypeRef{Name: name}
+}
+
+// ListOf returns a list of a type.
+func ListOf(ofType *TypeRef) *TypeRef {
+	return &TypeRef{List: true, OfType: ofType}
+}
+
+// NonNullOf returns a non-null type, wrapping a non-null type again returns it as is.
+func NonNullOf(ofType *TypeRef) *TypeRef {
+	if ofType.NonNull {
+		return ofType
+	}
+
+	return &TypeRef{NonNull: true, OfType: ofType}
+}
+
+// NamedType returns the name of the named type inside the wrapping types, i.e. User for [User!]!.
+func (r *TypeRef) NamedType() string {
+	for r.OfType != nil {
+		r = r.OfType
+	}
+
+	return r.Name
+}
+
+// String returns the type as it is written in SDL, i.e. [User!]!.
+func (r *TypeRef) String() string {
+	switch {
+	case r.NonNull:
+		return r.OfType.String() + "!"
+	case r.List:
+		return "[" + r.OfType.String() + "]"
+	default:
+		return r.Name
+	}
+}
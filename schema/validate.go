@@ -0,0 +1,591 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ValidationError is a rule of the type system of the GraphQL specification a schema breaks.
+type ValidationError struct {
+	// Coordinate is the schema coordinate of the definition breaking the rule,
+	// i.e. User, User.name, User.posts(first:), Role.ADMIN or @auth(role:).
+	Coordinate string
+	Message    string
+	// Origin is where the definition comes from, i.e. the Go field models.User.Name,
+	// when it is known. It is set by the generator rather than by Validate.
+	Origin string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Origin == "" {
+		return fmt.Sprintf("%s: %s", e.Coordinate, e.Message)
+	}
+
+	return fmt.Sprintf("%s: %s (%s)", e.Coordinate, e.Message, e.Origin)
+}
+
+// ValidationErrors are all the rules a schema breaks, in the order its definitions are output.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	var b strings.Builder
+
+	if len(e) == 1 {
+		b.WriteString("invalid schema: 1 error")
+	} else {
+		fmt.Fprintf(&b, "invalid schema: %d errors", len(e))
+	}
+
+	for _, err := range e {
+		b.WriteString("\n  ")
+		b.WriteString(err.Error())
+	}
+
+	return b.String()
+}
+
+// Validate checks a schema against the type system validation rules of the GraphQL
+// specification: names are valid and unique, types aren't empty, every type referenced
+// is defined and of the right kind, object and interface types implement their
+// interfaces, and applied directives are defined, allowed where they are applied and
+// given valid arguments. It returns every rule broken as ValidationErrors, or nil for a
+// valid schema. A schema without a Query type is valid, as it can be merged into another
+// one, and the directives imported with @link on the schema aren't checked, as they
+// are defined by the specification it links to.
+func Validate(s *Schema) error {
+	v := &validator{schema: s}
+
+	v.validateTypes()
+	v.validateDirectives()
+	v.validateAppliedDirectives()
+
+	if len(v.errors) == 0 {
+		return nil
+	}
+
+	return v.errors
+}
+
+type validator struct {
+	schema *Schema
+	errors ValidationErrors
+}
+
+func (v *validator) report(coordinate string, format string, args ...interface{}) {
+	v.errors = append(v.errors, &ValidationError{Coordinate: coordinate, Message: fmt.Sprintf(format, args...)})
+}
+
+// validateName reports a name that isn't a GraphQL name or starts with __, which is reserved for introspection.
+func (v *validator) validateName(coordinate string, name string) {
+	switch {
+	case !IsValidName(name):
+		v.report(coordinate, "'%s' is not a valid GraphQL name", name)
+	case strings.HasPrefix(name, "__"):
+		v.report(coordinate, "'%s' starts with __, which is reserved for introspection", name)
+	}
+}
+
+func (v *validator) validateTypes() {
+	seen := map[string]bool{}
+
+	for _, t := range v.schema.Types {
+		name := t.TypeName()
+		v.validateName(name, name)
+
+		switch {
+		case builtinScalars[name]:
+			v.report(name, "the built-in scalar %s can't be defined", name)
+		case seen[name]:
+			v.report(name, "the type %s is defined more than once", name)
+		}
+
+		seen[name] = true
+
+		switch t := t.(type) {
+		case *ObjectType:
+			v.validateFields(name, "object", t.Fields)
+			v.validateInterfaces(name, t.Interfaces, t.Fields)
+		case *InterfaceType:
+			v.validateFields(name, "interface", t.Fields)
+			v.validateInterfaces(name, t.Interfaces, t.Fields)
+		case *UnionType:
+			v.validateUnion(t)
+		case *EnumType:
+			v.validateEnum(t)
+		case *InputObjectType:
+			v.validateInputObject(t)
+		}
+	}
+}
+
+func (v *validator) validateFields(parent string, kind string, fields []*Field) {
+	if len(fields) == 0 {
+		v.report(parent, "the %s type %s has no fields", kind, parent)
+	}
+
+	seen := map[string]bool{}
+
+	for _, field := range fields {
+		coordinate := parent + "." + field.Name
+		v.validateName(coordinate, field.Name)
+
+		if seen[field.Name] {
+			v.report(coordinate, "the field %s is defined more than once", field.Name)
+		}
+
+		seen[field.Name] = true
+
+		if v.validateTypeRef(coordinate, field.Type) && !isOutputType(v.schema.Type(field.Type.NamedType())) {
+			v.report(coordinate, "the type %s of a field must be an output type, %s is an input object", field.Type, field.Type.NamedType())
+		}
+
+		v.validateInputValues(coordinate, "argument", field.Arguments, func(name string) string {
+			return fmt.Sprintf("%s(%s:)", coordinate, name)
+		})
+	}
+}
+
+// validateInputValues validates the arguments of a field or a directive, or the fields of an input object type.
+func (v *validator) validateInputValues(parent string, kind string, values []*InputValue, coordinate func(name string) string) {
+	seen := map[string]bool{}
+
+	for _, value := range values {
+		c := coordinate(value.Name)
+		v.validateName(c, value.Name)
+
+		if seen[value.Name] {
+			v.report(c, "the %s %s is defined more than once", kind, value.Name)
+		}
+
+		seen[value.Name] = true
+
+		if v.validateTypeRef(c, value.Type) && !isInputType(v.schema.Type(value.Type.NamedType())) {
+			v.report(c, "the type %s of an %s must be an input type, %s is an output type", value.Type, kind, value.Type.NamedType())
+		}
+
+		if value.Type.NonNull && value.DefaultValue == nil && value.IsDeprecated() {
+			v.report(c, "the required %s %s can't be deprecated", kind, value.Name)
+		}
+	}
+}
+
+// validateTypeRef reports a reference to a type that isn't defined, it returns whether the type is defined.
+func (v *validator) validateTypeRef(coordinate string, ref *TypeRef) bool {
+	if ref == nil {
+		v.report(coordinate, "it has no type")
+
+		return false
+	}
+
+	name := ref.NamedType()
+	if builtinScalars[name] || v.schema.Type(name) != nil {
+		return true
+	}
+
+	v.report(coordinate, "the type %s isn't defined", name)
+
+	return false
+}
+
+// validateInterfaces checks that an object or an interface type implements its interfaces: it has every
+// field of each interface, with a compatible type and the same arguments, and implements the interfaces
+// they implement themselves.
+func (v *validator) validateInterfaces(parent string, interfaces []string, fields []*Field) {
+	seen := map[string]bool{}
+
+	for _, name := range interfaces {
+		if seen[name] {
+			v.report(parent, "the interface %s is implemented more than once", name)
+
+			continue
+		}
+
+		seen[name] = true
+
+		if name == parent {
+			v.report(parent, "the type %s can't implement itself", name)
+
+			continue
+		}
+
+		iface, ok := v.schema.Type(name).(*InterfaceType)
+		if !ok {
+			if v.schema.Type(name) == nil {
+				v.report(parent, "the interface %s isn't defined", name)
+			} else {
+				v.report(parent, "%s can only implement interfaces, %s isn't one", parent, name)
+			}
+
+			continue
+		}
+
+		for _, transitive := range iface.Interfaces {
+			if transitive != parent && !contains(interfaces, transitive) {
+				v.report(parent, "%s must also implement %s, which %s implements", parent, transitive, name)
+			}
+		}
+
+		for _, interfaceField := range iface.Fields {
+			coordinate := parent + "." + interfaceField.Name
+
+			field := findField(fields, interfaceField.Name)
+			if field == nil {
+				v.report(parent, "the field %s of the interface %s is missing", interfaceField.Name, name)
+
+				continue
+			}
+
+			if field.Type != nil && interfaceField.Type != nil && !v.isSubType(field.Type, interfaceField.Type) {
+				v.report(coordinate, "the type %s isn't compatible with the type %s of %s.%s", field.Type, interfaceField.Type, name, interfaceField.Name)
+			}
+
+			for _, interfaceArgument := range interfaceField.Arguments {
+				argument := FindInputValue(field.Arguments, interfaceArgument.Name)
+
+				switch {
+				case argument == nil:
+					v.report(coordinate, "the argument %s of %s.%s is missing", interfaceArgument.Name, name, interfaceField.Name)
+				case argument.Type != nil && interfaceArgument.Type != nil && argument.Type.String() != interfaceArgument.Type.String():
+					v.report(fmt.Sprintf("%s(%s:)", coordinate, argument.Name),
+						"the type %s isn't the type %s of the argument of %s.%s", argument.Type, interfaceArgument.Type, name, interfaceField.Name)
+				}
+			}
+
+			for _, argument := range field.Arguments {
+				if FindInputValue(interfaceField.Arguments, argument.Name) == nil && argument.Type != nil && argument.Type.NonNull && argument.DefaultValue == nil {
+					v.report(fmt.Sprintf("%s(%s:)", coordinate, argument.Name),
+						"the argument %s isn't in %s.%s so it can't be required", argument.Name, name, interfaceField.Name)
+				}
+			}
+		}
+	}
+}
+
+// isSubType returns whether a field of an implementation can be of a type when the field of the interface is
+// of another: the same type, a member of a union or an implementation of an interface, or a non-null version of it.
+func (v *validator) isSubType(ref *TypeRef, of *TypeRef) bool {
+	switch {
+	case of.NonNull:
+		return ref.NonNull && v.isSubType(ref.OfType, of.OfType)
+	case ref.NonNull:
+		return v.isSubType(ref.OfType, of)
+	case of.List:
+		return ref.List && v.isSubType(ref.OfType, of.OfType)
+	case ref.List:
+		return false
+	case ref.Name == of.Name:
+		return true
+	}
+
+	switch t := v.schema.Type(of.Name).(type) {
+	case *UnionType:
+		return contains(t.Members, ref.Name)
+	case *InterfaceType:
+		switch implementation := v.schema.Type(ref.Name).(type) {
+		case *ObjectType:
+			return contains(implementation.Interfaces, of.Name)
+		case *InterfaceType:
+			return contains(implementation.Interfaces, of.Name)
+		}
+	}
+
+	return false
+}
+
+func (v *validator) validateUnion(union *UnionType) {
+	if len(union.Members) == 0 {
+		v.report(union.Name, "the union %s has no members", union.Name)
+	}
+
+	seen := map[string]bool{}
+
+	for _, member := range union.Members {
+		switch {
+		case seen[member]:
+			v.report(union.Name, "the member %s is given more than once", member)
+		case v.schema.Type(member) == nil:
+			v.report(union.Name, "the member %s isn't defined", member)
+		default:
+			if _, ok := v.schema.Type(member).(*ObjectType); !ok {
+				v.report(union.Name, "the members of a union must be object types, %s isn't one", member)
+			}
+		}
+
+		seen[member] = true
+	}
+}
+
+func (v *validator) validateEnum(enum *EnumType) {
+	if len(enum.Values) == 0 {
+		v.report(enum.Name, "the enum %s has no values", enum.Name)
+	}
+
+	seen := map[string]bool{}
+
+	for _, value := range enum.Values {
+		coordinate := enum.Name + "." + value.Name
+		v.validateName(coordinate, value.Name)
+
+		switch {
+		case value.Name == "true" || value.Name == "false" || value.Name == "null":
+			v.report(coordinate, "an enum value can't be %s", value.Name)
+		case seen[value.Name]:
+			v.report(coordinate, "the value %s is defined more than once", value.Name)
+		}
+
+		seen[value.Name] = true
+	}
+}
+
+func (v *validator) validateInputObject(input *InputObjectType) {
+	if len(input.Fields) == 0 {
+		v.report(input.Name, "the input object type %s has no fields", input.Name)
+	}
+
+	v.validateInputValues(input.Name, "input field", input.Fields, func(name string) string {
+		return input.Name + "." + name
+	})
+
+	if path := v.nonNullCycle(input, []string{input.Name}); path != nil {
+		v.report(input.Name, "the input object type %s can never be given, it refers to itself through non-null fields: %s",
+			input.Name, strings.Join(path, "."))
+	}
+}
+
+// nonNullCycle returns the path of non-null, non-list fields by which an input object type refers
+// back to the first type of the path, or nil if there is none.
+func (v *validator) nonNullCycle(input *InputObjectType, path []string) []string {
+	for _, field := range input.Fields {
+		if field.Type == nil || !field.Type.NonNull || field.Type.OfType.List {
+			continue
+		}
+
+		fieldType, ok := v.schema.Type(field.Type.NamedType()).(*InputObjectType)
+		if !ok {
+			continue
+		}
+
+		if fieldType.Name == path[0] {
+			return append(path, field.Name)
+		}
+
+		if contains(path, fieldType.Name) {
+			continue
+		}
+
+		if cycle := v.nonNullCycle(fieldType, append(append([]string{}, path...), field.Name, fieldType.Name)); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) validateDirectives() {
+	seen := map[string]bool{}
+
+	for _, directive := range v.schema.Directives {
+		coordinate := "@" + directive.Name
+		v.validateName(coordinate, directive.Name)
+
+		switch {
+		case findDirective(builtinDirectives, directive.Name) != nil:
+			v.report(coordinate, "the built-in directive @%s can't be defined", directive.Name)
+		case seen[directive.Name]:
+			v.report(coordinate, "the directive @%s is defined more than once", directive.Name)
+		}
+
+		seen[directive.Name] = true
+
+		if len(directive.Locations) == 0 {
+			v.report(coordinate, "the directive @%s has no locations", directive.Name)
+		}
+
+		v.validateInputValues(coordinate, "argument", directive.Arguments, func(name string) string {
+			return fmt.Sprintf("%s(%s:)", coordinate, name)
+		})
+	}
+}
+
+// validateAppliedDirectives checks the directives applied to every definition of the schema.
+func (v *validator) validateAppliedDirectives() {
+	linked := v.linkedDirectives()
+
+	check := func(coordinate string, location string, directives []*AppliedDirective) {
+		v.validateApplied(coordinate, location, directives, linked)
+	}
+
+	fields := func(parent string, fields []*Field) {
+		for _, field := range fields {
+			coordinate := parent + "." + field.Name
+			check(coordinate, "FIELD_DEFINITION", field.Directives)
+
+			for _, argument := range field.Arguments {
+				check(fmt.Sprintf("%s(%s:)", coordinate, argument.Name), "ARGUMENT_DEFINITION", argument.Directives)
+			}
+		}
+	}
+
+	check("schema", "SCHEMA", v.schema.ExtensionDirectives)
+
+	for _, t := range v.schema.Types {
+		name := t.TypeName()
+
+		switch t := t.(type) {
+		case *ObjectType:
+			check(name, "OBJECT", t.Directives)
+			fields(name, t.Fields)
+		case *InterfaceType:
+			check(name, "INTERFACE", t.Directives)
+			fields(name, t.Fields)
+		case *UnionType:
+			check(name, "UNION", t.Directives)
+		case *EnumType:
+			check(name, "ENUM", t.Directives)
+
+			for _, value := range t.Values {
+				check(name+"."+value.Name, "ENUM_VALUE", value.Directives)
+			}
+		case *ScalarType:
+			check(name, "SCALAR", t.Directives)
+		case *InputObjectType:
+			check(name, "INPUT_OBJECT", t.Directives)
+
+			for _, field := range t.Fields {
+				check(name+"."+field.Name, "INPUT_FIELD_DEFINITION", field.Directives)
+			}
+		}
+	}
+
+	for _, directive := range v.schema.Directives {
+		for _, argument := range directive.Arguments {
+			check(fmt.Sprintf("@%s(%s:)", directive.Name, argument.Name), "ARGUMENT_DEFINITION", argument.Directives)
+		}
+	}
+}
+
+// validateApplied checks the directives applied to a definition: each one is defined or
+// built in, can be applied at the location of the definition, at most once unless it is
+// repeatable, and is given arguments it has with values of their type.
+func (v *validator) validateApplied(coordinate string, location string, directives []*AppliedDirective, linked map[string]bool) {
+	seen := map[string]bool{}
+
+	for _, applied := range directives {
+		if linked[applied.Name] {
+			continue
+		}
+
+		directive := v.schema.Directive(applied.Name)
+		if directive == nil {
+			directive = findDirective(builtinDirectives, applied.Name)
+		}
+
+		if directive == nil {
+			v.report(coordinate, "the directive @%s isn't defined", applied.Name)
+
+			continue
+		}
+
+		if !contains(directive.Locations, location) {
+			v.report(coordinate, "the directive @%s can't be applied on %s", applied.Name, location)
+		}
+
+		if seen[applied.Name] && !directive.Repeatable {
+			v.report(coordinate, "the directive @%s isn't repeatable but is applied more than once", applied.Name)
+		}
+
+		seen[applied.Name] = true
+
+		given := map[string]bool{}
+
+		for _, argument := range applied.Arguments {
+			definition := FindInputValue(directive.Arguments, argument.Name)
+
+			switch {
+			case argument.Name == "":
+				v.report(coordinate, "the argument %s of @%s has no name", argument.Value, applied.Name)
+			case definition == nil:
+				v.report(coordinate, "the directive @%s has no argument %s", applied.Name, argument.Name)
+			case definition.Type != nil && !v.schema.ValueMatches(argument.Value, definition.Type):
+				v.report(coordinate, "the argument %s of @%s is %s, which isn't of type %s", argument.Name, applied.Name, argument.Value, definition.Type)
+			}
+
+			given[argument.Name] = true
+		}
+
+		for _, definition := range directive.Arguments {
+			if definition.Type != nil && definition.Type.NonNull && definition.DefaultValue == nil && !given[definition.Name] {
+				v.report(coordinate, "the required argument %s of @%s is missing", definition.Name, applied.Name)
+			}
+		}
+	}
+}
+
+// linkedDirectives returns the names of the directives imported with @link on the schema,
+// i.e. key in @link(url: "https://specs.apollo.dev/federation/v2.3", import: ["@key"]),
+// along with link itself.
+func (v *validator) linkedDirectives() map[string]bool {
+	linked := map[string]bool{}
+
+	for _, applied := range v.schema.ExtensionDirectives {
+		if applied.Name != "link" {
+			continue
+		}
+
+		linked["link"] = true
+
+		for _, argument := range applied.Arguments {
+			if argument.Name != "import" {
+				continue
+			}
+
+			list := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(argument.Value), "["), "]")
+
+			for _, name := range splitValues(list) {
+				if unquoted, err := strconv.Unquote(name); err == nil && strings.HasPrefix(unquoted, "@") {
+					linked[unquoted[1:]] = true
+				}
+			}
+		}
+	}
+
+	return linked
+}
+
+func findDirective(directives []*Directive, name string) *Directive {
+	for _, directive := range directives {
+		if directive.Name == name {
+			return directive
+		}
+	}
+
+	return nil
+}
+
+// isOutputType returns whether a named type can be the type of a field, a nil type is a built-in scalar.
+func isOutputType(t Type) bool {
+	_, isInput := t.(*InputObjectType)
+
+	return !isInput
+}
+
+// isInputType returns whether a named type can be the type of an argument, a nil type is a built-in scalar.
+func isInputType(t Type) bool {
+	switch t.(type) {
+	case *ObjectType, *InterfaceType, *UnionType:
+		return false
+	default:
+		return true
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
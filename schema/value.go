@@ -0,0 +1,120 @@
+package schema
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	intValuePattern   = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	floatValuePattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+)
+
+// ValueMatches returns whether a value, as it is written in SDL, is of a type of the schema.
+// A single value is also a list of one item, and the values of custom scalars aren't checked.
+func (s *Schema) ValueMatches(value string, ref *TypeRef) bool {
+	value = strings.TrimSpace(value)
+
+	if value == "null" {
+		return !ref.NonNull
+	}
+
+	if ref.NonNull {
+		return s.ValueMatches(value, ref.OfType)
+	}
+
+	if ref.List {
+		if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+			return s.ValueMatches(value, ref.OfType)
+		}
+
+		for _, item := range splitValues(value[1 : len(value)-1]) {
+			if !s.ValueMatches(item, ref.OfType) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	switch ref.Name {
+	case "Int":
+		return intValuePattern.MatchString(value)
+	case "Float":
+		return floatValuePattern.MatchString(value)
+	case "String":
+		return isStringValue(value)
+	case "Boolean":
+		return value == "true" || value == "false"
+	case "ID":
+		return isStringValue(value) || intValuePattern.MatchString(value)
+	}
+
+	switch t := s.Type(ref.Name).(type) {
+	case *EnumType:
+		for _, enumValue := range t.Values {
+			if enumValue.Name == value {
+				return true
+			}
+		}
+
+		return false
+	case *InputObjectType:
+		return strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}")
+	case *ObjectType, *InterfaceType, *UnionType:
+		// Arguments can't be of an output type.
+		return false
+	}
+
+	return true
+}
+
+func isStringValue(value string) bool {
+	return len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`)
+}
+
+// splitValues splits the items of a list at the commas that aren't inside a string,
+// a list or an object. Empty items are dropped.
+func splitValues(list string) []string {
+	var (
+		values   []string
+		current  strings.Builder
+		depth    int
+		inQuotes bool
+		escaped  bool
+	)
+
+	add := func() {
+		if value := strings.TrimSpace(current.String()); value != "" {
+			values = append(values, value)
+		}
+
+		current.Reset()
+	}
+
+	for _, char := range list {
+		switch {
+		case escaped:
+			escaped = false
+		case char == '\\' && inQuotes:
+			escaped = true
+		case char == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+		case char == '[' || char == '{':
+			depth++
+		case char == ']' || char == '}':
+			depth--
+		case char == ',' && depth <= 0:
+			add()
+
+			continue
+		}
+
+		current.WriteRune(char)
+	}
+
+	add()
+
+	return values
+}
@@ -0,0 +1,87 @@
+package schemagen
+
+import (
+	"github.com/warpspeed-cloud/graphql-schema-generator/internal/config"
+)
+
+// Config is a config file, in YAML or JSON, shared by library and command line users so that
+// a schema is generated the same way whichever is used, i.e.
+//
+//	docComments: true
+//	scalars:
+//	  time.Time: DateTime
+//	naming:
+//	  fields: camelCase
+//	order: alphabetical
+//	directives: |
+//	  directive @unique on FIELD_DEFINITION
+type Config struct {
+	// Options configure a Generator, i.e. schemagen.New(&cfg.Options).
+	Options Options
+
+	// Packages are the package patterns the command line tool reads types from.
+	Packages []string
+	// Roots are the structs the command line tool adds to the schema along with the types they reference.
+	Roots []string
+	// Output is the file the command line tool writes the schema to.
+	Output string
+	// Introspection is a file the command line tool also writes the schema to,
+	// as the JSON result of the introspection query.
+	Introspection string
+	// JSONSchema is a file the command line tool writes the Go types to as a JSON Schema document.
+	JSONSchema string
+	// TypeScript is a file the command line tool writes the Go types to as TypeScript declarations.
+	TypeScript string
+}
+
+// DefaultConfig returns the config used when there is no config file,
+// which uses doc comments as descriptions.
+func DefaultConfig() *Config {
+	cfg, _ := newConfig(config.Default())
+
+	return cfg
+}
+
+// LoadConfig reads a config file, the format is picked from its extension, .yaml, .yml
+// or .json. Settings missing from the file keep their value in DefaultConfig. Every
+// problem found in the config is returned at once.
+func LoadConfig(path string) (*Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConfig(cfg)
+}
+
+func newConfig(cfg *config.Config) (*Config, error) {
+	directives, err := cfg.DirectiveDefinitions()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Config{
+		Options: Options{
+			DocComments:     cfg.DocComments,
+			Scalars:         cfg.Scalars,
+			FieldNaming:     FieldNaming(cfg.Naming.Fields),
+			GenericNaming:   string(cfg.Naming.Generics),
+			TypeAliases:     cfg.Naming.Aliases,
+			NameCollisions:  NameCollision(cfg.Naming.Collisions),
+			ExcludeTypes:    cfg.Exclude,
+			IDFields:        cfg.IDFields,
+			MapStrategy:     MapStrategy(cfg.Maps.Strategy),
+			MapScalar:       cfg.Maps.Scalar,
+			Federation:      cfg.Federation,
+			TypeScriptEnums: TypeScriptEnums(cfg.TypeScript.Enums),
+			TypeOrder:       cfg.Order,
+			Directives:      directives,
+		},
+		Packages:      cfg.Packages,
+		Roots:         cfg.Roots,
+		Output:        cfg.Output,
+		Introspection: cfg.Introspection,
+		JSONSchema:    cfg.JSONSchema,
+		TypeScript:    cfg.TypeScript.Output,
+	}, nil
+}
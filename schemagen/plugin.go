@@ -0,0 +1,71 @@
+package schemagen
+
+import "github.com/warpspeed-cloud/graphql-schema-generator/schema"
+
+// Plugin changes the schema before it is output, i.e. to add directives, inject
+// fields or rename types. A plugin implements any of TypeHook, FieldHook and
+// SchemaHook, the hooks of each plugin are called in the order plugins are given.
+// The plugins run once on the schema built from the types added so far, and again
+// on a schema built from scratch only once more types are added.
+type Plugin interface {
+	Name() string
+}
+
+// TypeHook is called with every type of the schema, in the order they are output. A type
+// the hook renames is moved to its place in the TypeOrder once every plugin has run.
+type TypeHook interface {
+	OnType(t schema.Type)
+}
+
+// FieldHook is called with every field of the object and interface types of the
+// schema, after the TypeHook of its type so that it sees fields added there.
+type FieldHook interface {
+	OnField(parent schema.Type, field *schema.Field)
+}
+
+// SchemaHook is called with the whole schema once every type and field hook has
+// run, right before the schema is printed.
+type SchemaHook interface {
+	OnSchema(s *schema.Schema)
+}
+
+// runPlugins calls the hooks of the plugins on a schema.
+func runPlugins(plugins []Plugin, s *schema.Schema) {
+	for _, plugin := range plugins {
+		typeHook, hasTypeHook := plugin.(TypeHook)
+		fieldHook, hasFieldHook := plugin.(FieldHook)
+
+		if !hasTypeHook && !hasFieldHook {
+			continue
+		}
+
+		for _, t := range s.Types {
+			if hasTypeHook {
+				typeHook.OnType(t)
+			}
+
+			if hasFieldHook {
+				for _, field := range fieldsOf(t) {
+					fieldHook.OnField(t, field)
+				}
+			}
+		}
+	}
+
+	for _, plugin := range plugins {
+		if schemaHook, ok := plugin.(SchemaHook); ok {
+			schemaHook.OnSchema(s)
+		}
+	}
+}
+
+func fieldsOf(t schema.Type) []*schema.Field {
+	switch t := t.(type) {
+	case *schema.ObjectType:
+		return t.Fields
+	case *schema.InterfaceType:
+		return t.Fields
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,417 @@
+// Package schemagen generates a GraphQL schema from Go types.
+//
+// Structs are added to a Generator, which walks their fields to discover every
+// type they refer to, and the schema is then printed in SDL or returned as a
+// schema.Schema for other backends.
+//
+// Relay is supported with the graphql tag: a slice field tagged with connection is
+// output as a connection, and a struct whose ID field is tagged with node, or that
+// has an IsNode method, implements the Node interface and can be fetched with the
+// node and nodes fields of the Query type.
+//
+// Decorators apply directives, i.e. graphql:"decorators=[+auth(role: \"admin\")]".
+// The definitions of the directives are added with AddDirective and printed with the
+// schema, and Validate checks every decorator against them: it must apply a defined
+// directive, where the directive can be applied, with arguments of the right type. Only
+// the built in directives, i.e. @deprecated, can be applied without a definition.
+//
+// A field is deprecated with the deprecated option of the tag, i.e.
+// graphql:"deprecated=\"Use name\"", or with a Deprecated: paragraph in its doc
+// comment when DocComments is set.
+//
+// The type of a field can be given with the type option of the tag, i.e.
+// graphql:"type=DateTime", Validate reports it when it isn't a built-in scalar or a type
+// of the schema.
+// The name option renames a field, the nullable and nonNull flags override whether it
+// can be null, args=[first: Int = 10] gives its arguments and the exclude flag, or a
+// tag of -, leaves it out. An unknown option is reported by Warnings.
+//
+// The instantiations of generic types are named with a template, as Page[models.User]
+// isn't a valid GraphQL name: by default it is UserPage, and the GenericNaming option
+// {{.Type}}_{{join .Args "_"}} names it Page_User.
+//
+// Types are told apart by their package path, so when two Go types would have the same
+// name, i.e. models.Invoice and billing.Invoice, Build panics with both of them. One of
+// them is renamed with TypeAliases, or NameCollisionPrefix names both after their package,
+// ModelsInvoice and BillingInvoice, whatever order they are found in. The same goes for
+// named maps, and for a Go type named like an enum, which is the one that is prefixed.
+//
+// Types are output in the order they were found, which depends on the order structs are
+// added in. With the TypeOrder option they are sorted by name, or put in dependency order
+// with the types no other type refers to first, so that schema files diff cleanly.
+//
+// Validate checks the schema against the GraphQL specification, so call it before the
+// schema is written: i.e. a struct whose fields are all excluded is an empty type.
+//
+// With the Federation option the schema is an Apollo Federation v2 subgraph. Fields
+// are tagged with shareable, external, requires=<fields> and provides=<fields>, and
+// the fields tagged with key make up the @key of their type. As Go types can't be
+// tagged, the options of a type are given on a blank field:
+//
+//	type Product struct {
+//		_   struct{} `graphql:"key=[id, sku upc],shareable"`
+//		ID  string   `json:"id"`
+//		SKU string   `json:"sku"`
+//		UPC string   `json:"upc"`
+//	}
+//
+// The options can be read from a config file shared with the command line tool, see
+// LoadConfig, so that a schema is generated the same way by both.
+//
+// schemagen and schema are the public API of this module and follow semantic
+// versioning: their exported identifiers don't change in an incompatible way
+// within a major version. Everything under internal/ can change at any time.
+package schemagen
+
+import (
+	"go/types"
+	"sync"
+
+	"github.com/warpspeed-cloud/graphql-schema-generator/internal/builder"
+	typeparser "github.com/warpspeed-cloud/graphql-schema-generator/internal/type-parser"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+)
+
+// Writer receives the schema once it has been built, i.e. to write it to a file.
+type Writer interface {
+	WriteSchema(schema string)
+}
+
+// MapStrategy is how maps are output, as GraphQL doesn't have maps.
+type MapStrategy string
+
+const (
+	// MapStrategyEntries outputs a map as a list of key value pairs, i.e. [ProjectMeta!]!
+	// where ProjectMeta is a type with a key and a value field.
+	MapStrategyEntries MapStrategy = "entries"
+	// MapStrategyScalar outputs a map as a scalar, JSON unless MapScalar says otherwise.
+	MapStrategyScalar MapStrategy = "scalar"
+
+	// DefaultMapScalar is the scalar maps are output as with MapStrategyScalar.
+	DefaultMapScalar = builder.DefaultMapScalar
+)
+
+// FieldNaming is the naming convention of field names.
+type FieldNaming string
+
+const (
+	// FieldNamingJSON keeps the name from the json tag, or the Go field name without one.
+	FieldNamingJSON FieldNaming = ""
+	// FieldNamingCamelCase converts names to camelCase, i.e. ThumbURL becomes thumbUrl.
+	FieldNamingCamelCase FieldNaming = "camelCase"
+	// FieldNamingSnakeCase converts names to snake_case, i.e. ThumbURL becomes thumb_url.
+	FieldNamingSnakeCase FieldNaming = "snake_case"
+)
+
+// DefaultIDFields are the fields output as the ID scalar when IDFields isn't set, the
+// ones named ID in Go or id in the schema.
+var DefaultIDFields = []string{"ID", "id"}
+
+// NameCollision is what happens when two Go types would have the same name in the schema.
+type NameCollision string
+
+const (
+	// NameCollisionError panics with both Go types.
+	NameCollisionError NameCollision = "error"
+	// NameCollisionPrefix prefixes the names of the types with the name of their package,
+	// i.e. models.Invoice and billing.Invoice are named ModelsInvoice and BillingInvoice.
+	NameCollisionPrefix NameCollision = "prefix"
+)
+
+// TypeScriptEnums is how enums are output in TypeScript.
+type TypeScriptEnums string
+
+const (
+	// TypeScriptEnumsUnion outputs an enum as a union of its values, i.e. type Role = "USER" | "ADMIN".
+	TypeScriptEnumsUnion TypeScriptEnums = "union"
+	// TypeScriptEnumsEnum outputs an enum as a TypeScript enum, i.e. enum Role { USER = "USER" }.
+	TypeScriptEnumsEnum TypeScriptEnums = "enum"
+)
+
+// Options configure a Generator, the zero value is a valid configuration.
+type Options struct {
+	// Writer is given the schema when it is built, it is optional
+	// as the schema is also returned by Build.
+	Writer Writer
+
+	// DocComments reads the Go source of each discovered struct and uses the
+	// doc comments of the struct and its fields as their descriptions.
+	DocComments bool
+
+	// Scalars maps Go types to the GraphQL scalar they are output as. Keys are either
+	// a kind, i.e. int64, or a named type qualified by its package path, i.e. time.Time.
+	// Structs mapped to a scalar aren't walked.
+	Scalars map[string]string
+
+	// FieldNaming is the convention field names are converted to.
+	FieldNaming FieldNaming
+
+	// GenericNaming is the text/template the instantiations of generic types are named
+	// with, given the name of the generic type as .Type and the names of its type
+	// arguments as .Args, i.e. {{.Type}}_{{join .Args "_"}} names Page[User] Page_User.
+	// By default the type arguments come first, Page[User] is named UserPage. A type
+	// argument is named as it is in the schema, so Page[billing.Invoice] aliased Bill is BillPage.
+	GenericNaming string
+
+	// TypeAliases are the names of structs in the schema, keyed by the Go type qualified
+	// by its package path, i.e. github.com/x/billing.Invoice: BillingInvoice.
+	TypeAliases map[string]string
+
+	// NameCollisions is what happens when two Go types would have the same name,
+	// NameCollisionError by default.
+	NameCollisions NameCollision
+
+	// ExcludeTypes are types that are left out of the schema along with every field
+	// of their type. Types are given by name, optionally qualified by their package path.
+	ExcludeTypes []string
+
+	// IDFields are the fields output as the ID scalar, when they are strings or
+	// integers. They are patterns matched against both the Go and the output name
+	// of fields, i.e. ID matches the ID field and *ID matches UserID too. Nil is
+	// DefaultIDFields, an empty list outputs every field as its Go type.
+	IDFields []string
+
+	// MapStrategy is how maps are output, MapStrategyEntries by default.
+	MapStrategy MapStrategy
+
+	// MapScalar is the scalar maps are output as with MapStrategyScalar.
+	MapScalar string
+
+	// Federation outputs the schema as an Apollo Federation v2 subgraph,
+	// see the package documentation for the tags it reads.
+	Federation bool
+
+	// TypeScriptEnums is how TypeScript outputs enums, TypeScriptEnumsUnion by default.
+	TypeScriptEnums TypeScriptEnums
+
+	// Plugins change the schema before it is output, see Plugin.
+	Plugins []Plugin
+
+	// Directives are the definitions of the directives decorators apply,
+	// the same as adding each of them with AddDirective.
+	Directives []*schema.Directive
+
+	// TypeOrder is the order types are output in, once the plugins have run. By default
+	// it is the order they were found in, schema.TypeOrderAlphabetical or
+	// schema.TypeOrderDependency don't depend on the order structs are added in.
+	TypeOrder schema.TypeOrder
+}
+
+// Enum is an enum added by hand, as Go enums are named constants that can't be discovered.
+type Enum struct {
+	Name        string
+	Description string
+	Values      []EnumValue
+}
+
+// EnumValue is a value of an Enum. Value is the Go value it stands for.
+type EnumValue struct {
+	Name        string
+	Value       interface{}
+	Description string
+	// DeprecationReason deprecates the value when it isn't empty.
+	DeprecationReason string
+}
+
+// Generator discovers Go types and generates the GraphQL schema for them. AddStruct,
+// AddSourceStruct and AddEnum can be called from several goroutines at once, and the
+// schema built while they run has the types added so far. As the types are then found
+// in the order the calls happen to run in, use a TypeOrder other than discovery.
+type Generator struct {
+	builder *builder.GraphQLSchemaBuilder
+	options *Options
+
+	// mu guards the schema the plugins were run on, which is kept until types are added.
+	mu sync.Mutex
+	// added counts the calls adding types or directives, built is the count the schema was built at.
+	added  int
+	built  int
+	schema *schema.Schema
+}
+
+// New returns a Generator configured by the options, which can be nil.
+func New(options *Options) *Generator {
+	if options == nil {
+		options = &Options{}
+	}
+
+	idFields := options.IDFields
+	if idFields == nil {
+		idFields = DefaultIDFields
+	}
+
+	builderOptions := &builder.GraphQLSchemaBuilderOptions{
+		TypeParserOptions: &typeparser.TypeParserOptions{
+			ParseDocComments: options.DocComments,
+			Scalars:          options.Scalars,
+			FieldNaming:      typeparser.FieldNaming(options.FieldNaming),
+			ExcludeTypes:     options.ExcludeTypes,
+			IDFields:         idFields,
+			GenericNaming:    typeparser.GenericNaming(options.GenericNaming),
+			TypeAliases:      options.TypeAliases,
+			NameCollisions:   typeparser.NameCollision(options.NameCollisions),
+		},
+		MapStrategy: builder.MapStrategy(options.MapStrategy),
+		MapScalar:   options.MapScalar,
+		Federation:  options.Federation,
+		TypeOrder:   options.TypeOrder,
+	}
+
+	g := &Generator{
+		builder: builder.NewGraphQLSchemaBuilder(builderOptions),
+		options: options,
+	}
+
+	for _, directive := range options.Directives {
+		g.AddDirective(directive)
+	}
+
+	return g
+}
+
+// AddStruct adds a struct, or a pointer to one, and every type discovered from its
+// fields to the schema. It panics when it isn't given a struct.
+func (g *Generator) AddStruct(s any) *Generator {
+	g.builder.AddStruct(s, nil)
+	g.changed()
+
+	return g
+}
+
+// AddSourceStruct is AddStruct for a struct type that was type checked from
+// source rather than reflected, i.e. by a code generator that can't import
+// the packages it generates a schema for.
+func (g *Generator) AddSourceStruct(s types.Type) *Generator {
+	g.builder.AddSourceStruct(s, nil)
+	g.changed()
+
+	return g
+}
+
+// AddEnum adds an enum to the schema.
+func (g *Generator) AddEnum(e Enum) *Generator {
+	enum := builder.Enum{
+		Name:        e.Name,
+		Description: optional(e.Description),
+	}
+
+	for _, value := range e.Values {
+		enum.Values = append(enum.Values, &builder.EnumKeyPairOptions{
+			Key:         value.Name,
+			Value:       value.Value,
+			Description: optional(value.Description),
+			Deprecation: optional(value.DeprecationReason),
+		})
+	}
+
+	g.builder.AddEnum(enum)
+	g.changed()
+
+	return g
+}
+
+// AddDirective adds the definition of a directive applied by decorators, i.e.
+//
+//	&schema.Directive{
+//		Name:      "auth",
+//		Arguments: []*schema.InputValue{{Name: "role", Type: schema.NonNullOf(schema.Named("String"))}},
+//		Locations: []string{"FIELD_DEFINITION"},
+//	}
+//
+// Validate reports a decorator that applies a directive that isn't built in and wasn't
+// added, or doesn't match its definition.
+func (g *Generator) AddDirective(directive *schema.Directive) *Generator {
+	g.builder.AddDirective(directive)
+	g.changed()
+
+	return g
+}
+
+// changed tells the generator types were added, so the schema is built again.
+func (g *Generator) changed() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.added++
+}
+
+// Schema returns the types added so far as a schema, as the plugins left it. The plugins
+// run once for the types added so far: the schema is shared by the calls to Schema, Build,
+// Validate and Introspection until more types are added, so it must not be changed.
+func (g *Generator) Schema() *schema.Schema {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.schema != nil && g.built == g.added {
+		return g.schema
+	}
+
+	added := g.added
+
+	// The types are already in order for the plugins, they are sorted again for the
+	// ones the plugins added or renamed.
+	s := g.builder.Schema()
+	runPlugins(g.options.Plugins, s)
+	s.SortTypes(g.options.TypeOrder)
+
+	g.schema, g.built = s, added
+
+	return s
+}
+
+// Validate checks the schema, as the plugins left it, against the type system rules of the
+// GraphQL specification: names are valid and unique, types aren't empty, every type
+// referenced is defined and the directives applied, i.e. by plugins, match their
+// definitions. It returns every rule broken as schema.ValidationErrors, each
+// pointing back to the Go type or field it comes from, after the syntax errors in graphql
+// tags, which the schema is built around. Build doesn't validate the schema.
+func (g *Generator) Validate() error {
+	return g.builder.Validate(g.Schema())
+}
+
+// Warnings returns the problems found in the types added so far that don't prevent the
+// schema from being built, i.e. an unknown option in a graphql tag, which is likely a typo.
+func (g *Generator) Warnings() []string {
+	return g.builder.Warnings()
+}
+
+// Build prints the schema in SDL, hands it to the writer if there is one and returns it.
+func (g *Generator) Build() string {
+	sdl := builder.Print(g.Schema())
+
+	if g.options.Writer != nil {
+		g.options.Writer.WriteSchema(sdl)
+	}
+
+	return sdl
+}
+
+// Introspection returns the schema as the JSON result of the introspection query,
+// {"data": {"__schema": ...}}, for tools that read it rather than SDL.
+func (g *Generator) Introspection() string {
+	return builder.Introspection(g.Schema())
+}
+
+// JSONSchema returns the Go types as a JSON Schema (draft 2020-12) document, with a
+// definition in $defs for every struct, enum and custom scalar. It describes the JSON
+// the types are encoded as rather than the GraphQL schema, so plugins don't apply.
+func (g *Generator) JSONSchema() string {
+	return g.builder.JSONSchema()
+}
+
+// TypeScript returns the Go types as TypeScript declarations, an interface for every
+// struct and a type or an enum for every enum. Like JSONSchema it describes the JSON
+// the types are encoded as, so fields tagged with omitempty are optional properties.
+func (g *Generator) TypeScript() string {
+	return g.builder.TypeScript(&typeparser.TypeScriptOptions{
+		Enums: typeparser.TypeScriptEnumStyle(g.options.TypeScriptEnums),
+	})
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
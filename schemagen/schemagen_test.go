@@ -0,0 +1,642 @@
+package schemagen_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schema"
+	"github.com/warpspeed-cloud/graphql-schema-generator/schemagen"
+)
+
+type User struct {
+	ID       string    `json:"id" graphql:"description=The ID of the user"`
+	Username string    `json:"username" graphql:"decorators=[+unique()]"`
+	Password string    `json:"-"`
+	Roles    []Role    `json:"roles"`
+	Projects []Project `json:"projects"`
+}
+
+type Role uint
+
+type Project struct {
+	Name      string            `json:"name"`
+	Meta      map[string]string `json:"meta"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+type collectingWriter struct {
+	schema string
+}
+
+func (w *collectingWriter) WriteSchema(schema string) {
+	w.schema = schema
+}
+
+func TestGenerator(t *testing.T) {
+	writer := &collectingWriter{}
+
+	generator := schemagen.New(&schemagen.Options{
+		Writer: writer,
+		Scalars: map[string]string{
+			"time.Time": "DateTime",
+			"github.com/warpspeed-cloud/graphql-schema-generator/schemagen_test.Role": "Role",
+		},
+		FieldNaming: schemagen.FieldNamingSnakeCase,
+		MapStrategy: schemagen.MapStrategyScalar,
+		Directives:  []*schema.Directive{{Name: "unique", Locations: []string{"FIELD_DEFINITION"}}},
+	}).AddStruct(&User{}).AddEnum(schemagen.Enum{
+		Name:        "Role",
+		Description: "What a user can do.",
+		Values: []schemagen.EnumValue{
+			{Name: "USER", Value: 0},
+			{Name: "ADMIN", Value: 1, Description: "Can do anything"},
+		},
+	})
+
+	expected := `directive @unique on FIELD_DEFINITION
+
+type Project {
+  name: String!
+  meta: JSON!
+  created_at: DateTime!
+}
+
+type User {
+  "The ID of the user"
+  id: ID!
+  username: String! @unique
+  roles: [Role!]!
+  projects: [Project!]!
+}
+
+"What a user can do."
+enum Role {
+  USER
+  "Can do anything"
+  ADMIN
+}
+
+scalar DateTime
+
+scalar JSON
+`
+
+	assert.Equal(t, expected, generator.Build())
+	assert.Equal(t, expected, writer.schema)
+	assert.Equal(t, "[Role!]!", generator.Schema().Type("User").(*schema.ObjectType).Field("roles").Type.String())
+
+	// An empty list of ID fields outputs IDs as their Go type.
+	withoutIDs := schemagen.New(&schemagen.Options{IDFields: []string{}}).AddStruct(User{})
+	assert.Equal(t, "String!", withoutIDs.Schema().Type("User").(*schema.ObjectType).Field("id").Type.String())
+	assert.Contains(t, generator.Introspection(), `"queryType": null`)
+	assert.Contains(t, generator.TypeScript(), "/** What a user can do. */\nexport type Role = 0 | 1;\n")
+	assert.Contains(t, generator.JSONSchema(), `"Role": {
+      "description": "What a user can do.",
+      "enum": [
+        0,
+        1
+      ]
+    }`)
+}
+
+func ExampleGenerator() {
+	type Account struct {
+		Email string  `json:"email" graphql:"description=Where to reach the account"`
+		Name  *string `json:"name"`
+	}
+
+	fmt.Print(schemagen.New(nil).AddStruct(Account{}).Build())
+	// Output:
+	// type Account {
+	//   "Where to reach the account"
+	//   email: String!
+	//   name: String
+	// }
+}
+
+// authPlugin requires a role to read the fields of users.
+type authPlugin struct{}
+
+func (authPlugin) Name() string { return "auth" }
+
+func (authPlugin) OnField(parent schema.Type, field *schema.Field) {
+	if parent.TypeName() == "User" {
+		field.Directives = append(field.Directives, &schema.AppliedDirective{
+			Name:      "auth",
+			Arguments: []*schema.Argument{{Name: "role", Value: `"admin"`}},
+		})
+	}
+}
+
+func (authPlugin) OnSchema(s *schema.Schema) {
+	s.Directives = append(s.Directives, &schema.Directive{
+		Name:      "auth",
+		Arguments: []*schema.InputValue{{Name: "role", Type: schema.NonNullOf(schema.Named("String"))}},
+		Locations: []string{"FIELD_DEFINITION"},
+	})
+}
+
+// versionPlugin adds a version field to every object type.
+type versionPlugin struct{}
+
+func (versionPlugin) Name() string { return "version" }
+
+func (versionPlugin) OnType(t schema.Type) {
+	if object, ok := t.(*schema.ObjectType); ok {
+		object.Fields = append(object.Fields, &schema.Field{Name: "version", Type: schema.NonNullOf(schema.Named("Int"))})
+	}
+}
+
+// renamePlugin prefixes the name of every type.
+type renamePlugin struct{}
+
+func (renamePlugin) Name() string { return "rename" }
+
+func (renamePlugin) OnSchema(s *schema.Schema) {
+	for _, t := range s.Types {
+		s.RenameType(t.TypeName(), "Api"+t.TypeName())
+	}
+}
+
+// orderPlugin records the types it is called with.
+type orderPlugin struct {
+	types *[]string
+}
+
+func (orderPlugin) Name() string { return "order" }
+
+func (p orderPlugin) OnType(t schema.Type) {
+	*p.types = append(*p.types, t.TypeName())
+}
+
+type Account struct {
+	Email string   `json:"email"`
+	Owner *Account `json:"owner"`
+}
+
+func TestPlugins(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugins  []schemagen.Plugin
+		expected string
+	}{
+		{
+			name: "No plugins",
+			expected: `type Account {
+  email: String!
+  owner: Account
+}
+`,
+		},
+		{
+			name:    "Type hook",
+			plugins: []schemagen.Plugin{versionPlugin{}},
+			expected: `type Account {
+  email: String!
+  owner: Account
+  version: Int!
+}
+`,
+		},
+		{
+			name:    "Field and schema hooks",
+			plugins: []schemagen.Plugin{authPlugin{}},
+			expected: `directive @auth(role: String!) on FIELD_DEFINITION
+
+type Account {
+  email: String!
+  owner: Account
+}
+`,
+		},
+		{
+			name:    "Schema hook",
+			plugins: []schemagen.Plugin{renamePlugin{}},
+			expected: `type ApiAccount {
+  email: String!
+  owner: ApiAccount
+}
+`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, schemagen.New(&schemagen.Options{Plugins: test.plugins}).AddStruct(Account{}).Build())
+		})
+	}
+}
+
+func TestPlugins_Order(t *testing.T) {
+	generator := schemagen.New(&schemagen.Options{
+		Plugins: []schemagen.Plugin{versionPlugin{}, authPlugin{}},
+	}).AddStruct(Account{})
+
+	// The auth plugin runs after the version plugin so it sees the version field too.
+	// Each build starts from the discovered types again, hooks don't pile up.
+	expected := `directive @auth(role: String!) on FIELD_DEFINITION
+
+type Account {
+  email: String!
+  owner: Account
+  version: Int!
+}
+`
+
+	assert.Equal(t, expected, generator.Build())
+	assert.Equal(t, expected, generator.Build())
+}
+
+type Product struct {
+	_        struct{} `graphql:"key=[id, sku upc]"`
+	ID       string   `json:"id"`
+	SKU      string   `json:"sku"`
+	UPC      string   `json:"upc"`
+	Weight   int      `json:"weight" graphql:"external"`
+	Shipping int      `json:"shipping" graphql:"requires=weight"`
+	Reviews  []Review `json:"reviews" graphql:"provides=author"`
+	Vendor   Vendor   `json:"vendor"`
+}
+
+type Review struct {
+	ID     string `json:"id" graphql:"key"`
+	Author string `json:"author" graphql:"external"`
+}
+
+type Vendor struct {
+	_    struct{} `graphql:"shareable"`
+	Name string   `json:"name"`
+}
+
+func TestGenerator_Federation(t *testing.T) {
+	expected := `extend schema @link(url: "https://specs.apollo.dev/federation/v2.3", import: ["@key", "@shareable", "@external", "@requires", "@provides"])
+
+type Review @key(fields: "id") {
+  id: ID!
+  author: String! @external
+}
+
+type Vendor @shareable {
+  name: String!
+}
+
+type Product @key(fields: "id") @key(fields: "sku upc") {
+  id: ID!
+  sku: String!
+  upc: String!
+  weight: Int! @external
+  shipping: Int! @requires(fields: "weight")
+  reviews: [Review!]! @provides(fields: "author")
+  vendor: Vendor!
+}
+
+type Query {
+  _entities(representations: [_Any!]!): [_Entity]!
+  _service: _Service!
+}
+
+union _Entity = Review | Product
+
+scalar _Any
+
+type _Service {
+  sdl: String!
+}
+`
+
+	generator := schemagen.New(&schemagen.Options{Federation: true}).AddStruct(Product{})
+	assert.Equal(t, expected, generator.Build())
+	// The federation directives are imported with @link rather than defined.
+	assert.NoError(t, generator.Validate())
+
+	// Without federation the tags are ignored.
+	assert.Equal(t, `type Vendor {
+  name: String!
+}
+`, schemagen.New(nil).AddStruct(Vendor{}).Build())
+}
+
+type Login struct {
+	Email    string `json:"email"`
+	Username string `json:"username" graphql:"deprecated=\"Sign in with the email\""`
+}
+
+func TestGenerator_Deprecation(t *testing.T) {
+	generator := schemagen.New(nil).AddStruct(Login{}).AddEnum(schemagen.Enum{
+		Name: "Provider",
+		Values: []schemagen.EnumValue{
+			{Name: "EMAIL"},
+			{Name: "LEGACY", DeprecationReason: "Use EMAIL"},
+		},
+	})
+
+	assert.Equal(t, `type Login {
+  email: String!
+  username: String! @deprecated(reason: "Sign in with the email")
+}
+
+enum Provider {
+  EMAIL
+  LEGACY @deprecated(reason: "Use EMAIL")
+}
+`, generator.Build())
+
+	introspection := generator.Introspection()
+	assert.Contains(t, introspection, `"name": "username",
+              "description": null,
+              "args": [],
+              "type": {
+                "kind": "NON_NULL",
+                "name": null,
+                "ofType": {
+                  "kind": "SCALAR",
+                  "name": "String",
+                  "ofType": null
+                }
+              },
+              "isDeprecated": true,
+              "deprecationReason": "Sign in with the email"`)
+	assert.Contains(t, introspection, `"name": "LEGACY",
+              "description": null,
+              "isDeprecated": true,
+              "deprecationReason": "Use EMAIL"`)
+}
+
+type Invitation struct {
+	Email string `json:"email" graphql:"decorators=[+constraint(format: \"email\", maxLength: 255)]"`
+	Token string `json:"token" graphql:"decorators=[+constraint(maxLength: \"64\")]"`
+}
+
+func TestGenerator_Directives(t *testing.T) {
+	constraint := &schema.Directive{
+		Name:        "constraint",
+		Description: "Limits the values of a field.",
+		Arguments: []*schema.InputValue{
+			{Name: "format", Type: schema.Named("String")},
+			{Name: "maxLength", Type: schema.Named("Int")},
+		},
+		Locations: []string{"FIELD_DEFINITION", "INPUT_FIELD_DEFINITION"},
+	}
+
+	generator := schemagen.New(nil).AddStruct(Invitation{}).AddDirective(constraint)
+
+	assert.EqualError(t, generator.Validate(), "invalid schema: 1 error\n"+
+		`  Invitation.token: the argument maxLength of @constraint is "64", which isn't of type Int (Go field schemagen_test.Invitation.Token)`)
+
+	constraint.Arguments[1].Type = schema.Named("Float")
+	assert.EqualError(t, generator.Validate(), "invalid schema: 1 error\n"+
+		`  Invitation.token: the argument maxLength of @constraint is "64", which isn't of type Float (Go field schemagen_test.Invitation.Token)`)
+
+	constraint.Arguments[1].Type = schema.Named("String")
+	generator = schemagen.New(nil).AddStruct(Invitation{}).AddDirective(constraint)
+	assert.EqualError(t, generator.Validate(), "invalid schema: 1 error\n"+
+		"  Invitation.email: the argument maxLength of @constraint is 255, which isn't of type String (Go field schemagen_test.Invitation.Email)")
+
+	constraint.Arguments[1].Type = schema.Named("Scalar")
+	assert.EqualError(t, generator.Validate(), "invalid schema: 1 error\n  @constraint(maxLength:): the type Scalar isn't defined")
+
+	// Custom scalars are declared for the directives that use them and their values aren't checked.
+	constraint.Arguments[1].Type = schema.Named("Length")
+	generator = schemagen.New(&schemagen.Options{Scalars: map[string]string{"uint16": "Length"}}).
+		AddStruct(Invitation{}).AddDirective(constraint)
+
+	assert.Equal(t, `"Limits the values of a field."
+directive @constraint(format: String, maxLength: Length) on FIELD_DEFINITION | INPUT_FIELD_DEFINITION
+
+type Invitation {
+  email: String! @constraint(format: "email", maxLength: 255)
+  token: String! @constraint(maxLength: "64")
+}
+
+scalar Length
+`, generator.Build())
+}
+
+// auditPlugin adds a field of a type it doesn't define.
+type auditPlugin struct{}
+
+func (auditPlugin) Name() string { return "audit" }
+
+func (auditPlugin) OnField(parent schema.Type, field *schema.Field) {
+	if object, ok := parent.(*schema.ObjectType); ok && field.Name == "email" {
+		object.Fields = append(object.Fields, &schema.Field{Name: "auditedBy", Type: schema.Named("Auditor")})
+	}
+}
+
+type Session struct {
+	Token   string    `json:"token" graphql:"-"`
+	Expires time.Time `json:"expires" graphql:"exclude"`
+}
+
+func TestGenerator_Validate(t *testing.T) {
+	assert.NoError(t, schemagen.New(nil).AddStruct(Account{}).Validate())
+
+	assert.EqualError(t, schemagen.New(nil).AddStruct(Session{}).Validate(),
+		"invalid schema: 1 error\n  Session: the object type Session has no fields (Go type schemagen_test.Session)")
+
+	// The schema is validated as the plugins left it, the fields they add don't come from Go.
+	generator := schemagen.New(&schemagen.Options{Plugins: []schemagen.Plugin{auditPlugin{}}}).AddStruct(Account{})
+	assert.EqualError(t, generator.Validate(), "invalid schema: 1 error\n  Account.auditedBy: the type Auditor isn't defined")
+}
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+type Directory struct {
+	Accounts Page[Account] `json:"accounts"`
+	Logins   Page[*Login]  `json:"logins"`
+}
+
+func TestGenerator_Generics(t *testing.T) {
+	generator := schemagen.New(&schemagen.Options{GenericNaming: `{{.Type}}_{{join .Args "_"}}`}).AddStruct(Directory{})
+
+	assert.Equal(t, `type Account {
+  email: String!
+  owner: Account
+}
+
+type Page_Account {
+  items: [Account!]!
+  total: Int!
+}
+
+type Login {
+  email: String!
+  username: String! @deprecated(reason: "Sign in with the email")
+}
+
+type Page_Login {
+  items: [Login]!
+  total: Int!
+}
+
+type Directory {
+  accounts: Page_Account!
+  logins: Page_Login!
+}
+`, generator.Build())
+	assert.NoError(t, generator.Validate())
+}
+
+func TestPlugins_TypeOrder(t *testing.T) {
+	var types []string
+
+	schemagen.New(&schemagen.Options{
+		TypeOrder: schema.TypeOrderAlphabetical,
+		Plugins:   []schemagen.Plugin{orderPlugin{types: &types}},
+	}).AddStruct(Directory{}).Build()
+
+	// The type hook sees the types in the order they are output.
+	assert.Equal(t, []string{"Account", "AccountPage", "Directory", "Login", "LoginPage"}, types)
+}
+
+func TestPlugins_Once(t *testing.T) {
+	var types []string
+
+	generator := schemagen.New(&schemagen.Options{Plugins: []schemagen.Plugin{orderPlugin{types: &types}}}).AddStruct(Account{})
+	generator.Build()
+	generator.Introspection()
+	assert.NoError(t, generator.Validate())
+	assert.Same(t, generator.Schema(), generator.Schema())
+
+	// The plugins run once for the types added so far, and again once more are added.
+	assert.Equal(t, []string{"Account"}, types)
+
+	generator.AddStruct(Directory{})
+	generator.Build()
+	assert.Equal(t, []string{"Account", "Account", "AccountPage", "Login", "LoginPage", "Directory"}, types)
+}
+
+func TestGenerator_TypeOrder(t *testing.T) {
+	build := func(order schema.TypeOrder, structs ...any) string {
+		generator := schemagen.New(&schemagen.Options{TypeOrder: order, Plugins: []schemagen.Plugin{renamePlugin{}}})
+		for _, s := range structs {
+			generator.AddStruct(s)
+		}
+
+		return generator.Build()
+	}
+
+	// The types no other type refers to come first, followed by the types they refer to.
+	assert.Equal(t, `type ApiDirectory {
+  accounts: ApiAccountPage!
+  logins: ApiLoginPage!
+}
+
+type ApiAccountPage {
+  items: [ApiAccount!]!
+  total: Int!
+}
+
+type ApiAccount {
+  email: String!
+  owner: ApiAccount
+}
+
+type ApiLoginPage {
+  items: [ApiLogin]!
+  total: Int!
+}
+
+type ApiLogin {
+  email: String!
+  username: String! @deprecated(reason: "Sign in with the email")
+}
+`, build(schema.TypeOrderDependency, Login{}, Directory{}))
+
+	// The order structs are added in doesn't matter, the types are sorted once the plugins renamed them.
+	for _, order := range []schema.TypeOrder{schema.TypeOrderAlphabetical, schema.TypeOrderDependency} {
+		assert.Equal(t, build(order, Login{}, Directory{}), build(order, Directory{}, Login{}))
+	}
+
+	assert.NotEqual(t, build(schema.TypeOrderDiscovery, Login{}, Directory{}), build(schema.TypeOrderDiscovery, Directory{}, Login{}))
+}
+
+func TestGenerator_Concurrency(t *testing.T) {
+	structs := []any{Directory{}, Session{}, Account{}, Login{}, Invitation{}}
+	options := &schemagen.Options{
+		Scalars:   map[string]string{"uint16": "Length"},
+		TypeOrder: schema.TypeOrderAlphabetical,
+		Directives: []*schema.Directive{{
+			Name: "constraint",
+			Arguments: []*schema.InputValue{
+				{Name: "format", Type: schema.Named("String")},
+				{Name: "maxLength", Type: schema.Named("Length")},
+			},
+			Locations: []string{"FIELD_DEFINITION"},
+		}},
+	}
+
+	audit := &schema.Directive{Name: "audit", Locations: []string{"OBJECT"}}
+
+	sequential := schemagen.New(options).AddDirective(audit)
+	for _, s := range structs {
+		sequential.AddStruct(s)
+	}
+
+	generator := schemagen.New(options)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		generator.AddDirective(audit)
+	}()
+
+	for _, s := range structs {
+		wg.Add(2)
+
+		go func(s any) {
+			defer wg.Done()
+			generator.AddStruct(s)
+		}(s)
+
+		go func() {
+			defer wg.Done()
+			generator.Build()
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, sequential.Build(), generator.Build())
+}
+
+func TestLoadConfig(t *testing.T) {
+	cfg, err := schemagen.LoadConfig("testdata/config.yaml")
+	assert.NoError(t, err)
+
+	assert.Equal(t, &schemagen.Config{
+		Options: schemagen.Options{
+			Scalars:         map[string]string{"time.Time": "DateTime"},
+			FieldNaming:     schemagen.FieldNamingCamelCase,
+			NameCollisions:  schemagen.NameCollisionPrefix,
+			MapStrategy:     schemagen.MapStrategyScalar,
+			MapScalar:       schemagen.DefaultMapScalar,
+			TypeScriptEnums: schemagen.TypeScriptEnumsEnum,
+			TypeOrder:       schema.TypeOrderAlphabetical,
+			Directives: []*schema.Directive{
+				{Name: "unique", Locations: []string{"FIELD_DEFINITION"}},
+			},
+		},
+		Packages:   []string{"./models/..."},
+		Roots:      []string{"User"},
+		Output:     "schema.graphql",
+		TypeScript: "types.ts",
+	}, cfg)
+
+	assert.True(t, schemagen.DefaultConfig().Options.DocComments)
+
+	_, err = schemagen.LoadConfig("testdata/missing.yaml")
+	assert.Error(t, err)
+}